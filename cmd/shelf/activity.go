@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/state"
+)
+
+// runActivity implements `shelf activity`: a per-day histogram of articles
+// opened and finished, with the current reading streak.
+func runActivity(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("activity", flag.ContinueOnError)
+	days := fs.Int("days", 14, "number of days to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	st, err := state.Load(filepath.Join(cfg.DataDir, state.FileName))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	const barWidth = 40
+	peak := 1
+	for i := 0; i < *days; i++ {
+		peak = max(peak, st.Day(now.AddDate(0, 0, -i)).Opened)
+	}
+	for i := *days - 1; i >= 0; i-- {
+		day := now.AddDate(0, 0, -i)
+		a := st.Day(day)
+		bar := strings.Repeat("█", (a.Opened*barWidth+peak-1)/peak)
+		fmt.Fprintf(w, "%s  %-*s %d opened, %d finished\n",
+			day.Format("Mon Jan 02"), barWidth, bar, a.Opened, a.Finished)
+	}
+	fmt.Fprintf(w, "\nStreak: %d day(s)\n", st.Streak(now))
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// runDedup implements `shelf dedup`: list groups of articles saved more
+// than once. With -interactive, ask for each group which copy to keep; the
+// others' tags are merged into it and they are moved to the trash. Just
+// pressing enter skips a group, so nothing is merged unless asked for.
+func runDedup(cfg config.Config, args []string, in io.Reader, w io.Writer) error {
+	fs := flag.NewFlagSet("dedup", flag.ContinueOnError)
+	interactive := fs.Bool("interactive", false, "merge each group, asking which article to keep")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	groups, err := store.FindDuplicates()
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No duplicates")
+		return nil
+	}
+
+	answers := bufio.NewReader(in)
+	var merged, trashed int
+	for gi, g := range groups {
+		if gi > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Same %s:\n", strings.Join(g.Matched, ", "))
+		for i, a := range g.Articles {
+			fmt.Fprintf(w, "  %d. %s  (%s, saved %s", i+1, a.Title, a.FilePath, a.SavedAt.Format("2006-01-02"))
+			if len(a.Tags) > 0 {
+				fmt.Fprintf(w, ", tags: %s", strings.Join(a.Tags, ", "))
+			}
+			fmt.Fprintln(w, ")")
+		}
+		if !*interactive {
+			continue
+		}
+
+		fmt.Fprintf(w, "Keep which? [1-%d, s to skip, q to quit] (s) ", len(g.Articles))
+		answer, _ := answers.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		var keep int
+		switch answer {
+		case "", "s":
+			continue
+		case "q":
+			fmt.Fprintf(w, "Merged %d group(s), moved %d article(s) to %s\n", merged, trashed, storage.TrashDir)
+			return nil
+		default:
+			keep, err = strconv.Atoi(answer)
+			if err != nil || keep < 1 || keep > len(g.Articles) {
+				fmt.Fprintln(w, "Skipped")
+				continue
+			}
+		}
+		var others []string
+		for i, a := range g.Articles {
+			if i != keep-1 {
+				others = append(others, a.FilePath)
+			}
+		}
+		if err := store.MergeDuplicates(g.Articles[keep-1].FilePath, others); err != nil {
+			return err
+		}
+		merged++
+		trashed += len(others)
+	}
+	if *interactive {
+		fmt.Fprintf(w, "\nMerged %d group(s), moved %d article(s) to %s\n", merged, trashed, storage.TrashDir)
+	} else {
+		fmt.Fprintf(w, "\n%d group(s) of duplicates; run with -interactive to merge them\n", len(groups))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runDeleteTag implements `shelf delete-tag <tag>`: remove a tag from every
+// article carrying it, after saying how many that is and asking first. The
+// archive and pin tags are refused without -f.
+func runDeleteTag(cfg config.Config, args []string, in io.Reader, w io.Writer) error {
+	fs := flag.NewFlagSet("delete-tag", flag.ContinueOnError)
+	yes := fs.Bool("y", false, "remove without asking")
+	force := fs.Bool("f", false, "also remove the archive and pin tags")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: shelf delete-tag [-f] [-y] <tag>")
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	tag := fs.Arg(0)
+	if store.IsStatusTag(tag) && !*force {
+		return fmt.Errorf("%q marks articles archived or pinned; pass -f to remove it from all of them", tag)
+	}
+	n := len(store.ListByTag(tag))
+	if n == 0 {
+		fmt.Fprintf(w, "No articles tagged %q\n", tag)
+		return nil
+	}
+	if !*yes {
+		fmt.Fprintf(w, "Remove tag %q from %d article(s)? [y/N] ", tag, n)
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Fprintln(w, "Nothing removed")
+			return nil
+		}
+	}
+	n, err = store.DeleteTag(tag, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Removed tag %q from %d article(s)\n", tag, n)
+	return nil
+}
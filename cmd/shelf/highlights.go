@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runHighlights implements `shelf highlights`: every passage marked
+// ==like this== across the shelf, grouped by article.
+func runHighlights(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("highlights", flag.ContinueOnError)
+	tag := fs.String("tag", "", "only articles with this tag")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	articles := store.List()
+	if *tag != "" {
+		articles = store.ListByTag(*tag)
+	}
+	var n int
+	for _, a := range articles {
+		highlights, err := store.Highlights(a.FilePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.FilePath, err)
+		}
+		if len(highlights) == 0 {
+			continue
+		}
+		if n > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n", a.Title)
+		for _, h := range highlights {
+			fmt.Fprintf(w, "  L%-5d %s\n", h.Line, h.Text)
+		}
+		n += len(highlights)
+	}
+	if n == 0 {
+		fmt.Fprintln(w, "No highlights; mark passages ==like this== (<leader>h in vim)")
+	}
+	return nil
+}
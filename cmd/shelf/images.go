@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runImages implements `shelf images`: a summary of how many articles still
+// reference remote images, or with --remote, a list of them with counts.
+func runImages(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("images", flag.ContinueOnError)
+	remote := fs.Bool("remote", false, "list the articles that reference remote images")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	report, err := store.RemoteImageReport()
+	if err != nil {
+		return err
+	}
+
+	images := 0
+	for _, r := range report {
+		images += r.Count
+		if *remote {
+			fmt.Fprintf(w, "%4d/%-4d %s\n", r.Count, r.Total, r.Article.Title)
+			fmt.Fprintf(w, "          %s\n", store.GetFilePath(r.Article.FilePath))
+		}
+	}
+	if *remote && len(report) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d of %d articles reference %d remote image(s)\n", len(report), store.Count(), images)
+	if !*remote && len(report) > 0 {
+		fmt.Fprintln(w, "Run `shelf images --remote` to list them.")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// runImportFile implements `shelf import-file`: save markdown and HTML files
+// from elsewhere on disk as articles, copying the images they reference.
+func runImportFile(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("import-file", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: shelf import-file <path>...")
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for _, path := range fs.Args() {
+		if err := store.ImportFile(path); err != nil {
+			var existsErr *storage.ErrArticleExists
+			if errors.As(err, &existsErr) {
+				err = fmt.Errorf("%s: %q is already saved", path, existsErr.Title)
+			}
+			fmt.Fprintln(w, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "Imported %s\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) not imported", failed, fs.NArg())
+	}
+	return nil
+}
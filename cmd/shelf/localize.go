@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runLocalizeImages implements `shelf localize-images`: download the remote
+// images referenced by saved articles and store them with each article, so
+// the shelf no longer depends on the original hosts.
+func runLocalizeImages(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("localize-images", flag.ContinueOnError)
+	tag := fs.String("tag", "", "only articles with this tag")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	report, err := store.RemoteImageReport()
+	if err != nil {
+		return err
+	}
+
+	var tagged map[string]bool
+	if *tag != "" {
+		tagged = make(map[string]bool)
+		for _, a := range store.ListByTag(*tag) {
+			tagged[a.FilePath] = true
+		}
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var articles, fetched, failed int
+	for _, r := range report {
+		if tagged != nil && !tagged[r.Article.FilePath] {
+			continue
+		}
+		articles++
+		n, failures, err := store.LocalizeImages(r.Article.FilePath, client)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", r.Article.Title, err)
+			failed += r.Count
+			continue
+		}
+		fetched += n
+		failed += len(failures)
+		fmt.Fprintf(w, "%s: fetched %d of %d\n", r.Article.Title, n, r.Count)
+		for _, f := range failures {
+			fmt.Fprintf(w, "  failed %v\n", f)
+		}
+	}
+	fmt.Fprintf(w, "\nFetched %d image(s) for %d article(s), %d failed\n", fetched, articles, failed)
+	return nil
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/extractor"
+	"github.com/irfansharif/shelf/pkg/logging"
 	"github.com/irfansharif/shelf/pkg/storage"
 	"github.com/irfansharif/shelf/pkg/tui"
 )
@@ -17,36 +23,71 @@ func main() {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+	if len(cfg.UnknownKeys) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: unknown keys in %s: %s\n", config.Path(), strings.Join(cfg.UnknownKeys, ", "))
+	}
 
-	if cfg.Endpoint == "" {
+	// --plain, or output that isn't a terminal, gets the line-based
+	// interface instead of the full-screen one.
+	plain := !isTerminal(os.Stdout)
+	if len(os.Args) > 1 {
+		if os.Args[1] != "--plain" && os.Args[1] != "-plain" {
+			runCommand(cfg, os.Args[1], os.Args[2:])
+			return
+		}
+		plain = true
+	}
+
+	if cfg.Endpoint == "" && cfg.ExtractStrategy != "local" {
 		fmt.Fprintf(os.Stderr, "error: endpoint not configured in %s\n", config.Path())
 		os.Exit(1)
 	}
 
-	store, err := storage.New(cfg.DataDir)
+	// The TUI scans the articles in the background (see tui.Model.Init),
+	// so a large shelf doesn't hold up startup; plain mode needs them first.
+	opts, err := storeOptions(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+		os.Exit(1)
+	}
+	store, err := storage.Open(cfg.DataDir, opts...)
+	if err == nil && plain {
+		err = store.Scan()
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 		os.Exit(1)
 	}
 
-	model := tui.New(store, cfg.Endpoint)
+	logger, logCloser, err := logging.Open(filepath.Join(cfg.DataDir, "shelf.log"), cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer logCloser.Close()
 
-	// Filter out SIGINT-generated quit/interrupt messages when not in list
-	// state, so that Ctrl+C cancels the current operation instead of killing
-	// the app. Bubbletea v1 sends InterruptMsg for SIGINT (not QuitMsg).
-	filter := func(m tea.Model, msg tea.Msg) tea.Msg {
-		switch msg.(type) {
-		case tea.QuitMsg, tea.InterruptMsg:
-			if model, ok := m.(tui.Model); ok && !model.InListState() {
-				return nil
-			}
+	model := tui.New(store, cfg, logger)
+	if plain {
+		err := model.RunPlain(os.Stdin, os.Stdout)
+		store.Wait()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
 		}
-		return msg
+		return
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithFilter(filter))
+	// Ctrl+C cancels the current operation instead of killing the app,
+	// unless ctrl_c = "quit"; see tui.QuitFilter.
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithFilter(tui.QuitFilter))
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if m, ok := final.(tui.Model); ok {
+		m.SaveSession()
+	}
+	// Let any in-flight import save finish rather than leaving it half-written.
+	store.Wait()
+	if err != nil {
 		if err == tea.ErrInterrupted {
 			os.Exit(0)
 		}
@@ -54,3 +95,98 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// openStore opens the article store configured by cfg.
+func openStore(cfg config.Config) (*storage.Store, error) {
+	opts, err := storeOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return storage.New(cfg.DataDir, opts...)
+}
+
+// storeOptions returns the options for the article store configured by cfg.
+func storeOptions(cfg config.Config) ([]storage.Option, error) {
+	opts := []storage.Option{
+		storage.WithArchiveTag(cfg.ArchiveTag),
+		storage.WithArchiveAliases(cfg.ArchiveAliases...),
+		storage.WithDefaultTags(cfg.DefaultTags...),
+		storage.WithImageDir(cfg.ImageDir),
+		storage.WithHTMLConverter(extractor.HTMLToMarkdown),
+	}
+	if tmpl, err := os.ReadFile(config.TemplatePath()); err == nil {
+		opts = append(opts, storage.WithNoteTemplate(string(tmpl)))
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading note template: %w", err)
+	}
+	switch cfg.Sort {
+	case "manual":
+		opts = append(opts, storage.WithManualOrder())
+	case "size":
+		opts = append(opts, storage.WithSizeOrder())
+	}
+	return opts, nil
+}
+
+// newExtractor returns an extractor configured as the TUI's is.
+func newExtractor(cfg config.Config) *extractor.Extractor {
+	pdfArgv, _ := config.SplitCommand(cfg.PDFCommand) // checked by config.Load
+	opts := []extractor.Option{
+		extractor.WithStrategy(extractor.Strategy(cfg.ExtractStrategy)),
+		extractor.WithPDFCommand(pdfArgv...),
+		extractor.WithDomainEndpoints(cfg.Endpoints),
+	}
+	if cfg.DemoteH1 {
+		opts = append(opts, extractor.WithDemoteH1())
+	}
+	return extractor.New(cfg.Endpoint, opts...)
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runCommand runs a non-interactive subcommand and exits on failure.
+func runCommand(cfg config.Config, name string, args []string) {
+	var err error
+	switch name {
+	case "activity":
+		err = runActivity(cfg, args, os.Stdout)
+	case "dedup":
+		err = runDedup(cfg, args, os.Stdin, os.Stdout)
+	case "delete-tag":
+		err = runDeleteTag(cfg, args, os.Stdin, os.Stdout)
+	case "highlights":
+		err = runHighlights(cfg, args, os.Stdout)
+	case "images":
+		err = runImages(cfg, args, os.Stdout)
+	case "import-file":
+		err = runImportFile(cfg, args, os.Stdout)
+	case "localize-images":
+		err = runLocalizeImages(cfg, args, os.Stdout)
+	case "migrate":
+		err = runMigrate(cfg, args, os.Stdout)
+	case "open-dir":
+		err = runOpenDir(cfg, args, os.Stdout)
+	case "prune-images":
+		err = runPruneImages(cfg, args, os.Stdin, os.Stdout)
+	case "rename-tag":
+		err = runRenameTag(cfg, args, os.Stdout)
+	case "save-current":
+		err = runSaveCurrent(cfg, args, os.Stdout)
+	case "stats":
+		err = runStats(cfg, args, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown command %q\n", name)
+		os.Exit(2)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/state"
+)
+
+// runMigrate implements `shelf migrate`: convert the flat-file articles left
+// from before the directory format (marked "flat file" in the list) to
+// articles/<slug>/index.md, so they can hold images and notes. With -n it
+// only lists them. Saved editor positions move with the articles.
+func runMigrate(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("n", false, "list the flat-file articles without converting them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	// Where the reader left each article in vim is kept by its path, so
+	// it moves with the article.
+	st, err := state.Load(filepath.Join(cfg.DataDir, state.FileName))
+	if err != nil {
+		return err
+	}
+	var moved bool
+	var migrated, failed int
+	for _, a := range store.List() {
+		if !a.IsFlat() {
+			continue
+		}
+		if *dryRun {
+			fmt.Fprintf(w, "%s (%s)\n", a.Title, a.FilePath)
+			migrated++
+			continue
+		}
+		newPath, err := store.MigrateFlat(a.FilePath)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", a.FilePath, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "%s -> %s\n", a.FilePath, newPath)
+		if st.MoveArticle(a.FilePath, newPath) {
+			moved = true
+		}
+		migrated++
+	}
+	if moved {
+		if err := st.Save(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case migrated == 0 && failed == 0:
+		fmt.Fprintln(w, "No flat-file articles")
+	case *dryRun:
+		fmt.Fprintf(w, "\n%d flat-file article(s); run `shelf migrate` to convert them\n", migrated)
+	default:
+		fmt.Fprintf(w, "\nConverted %d article(s) to directory format\n", migrated)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d article(s) couldn't be converted", failed)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/tui"
+)
+
+// runOpenDir implements `shelf open-dir`: show data_dir in the OS file
+// manager, as pressing O in the TUI does.
+func runOpenDir(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("open-dir", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(cfg.DataDir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("data directory %s doesn't exist", cfg.DataDir)
+		}
+		return err
+	}
+	opener := tui.Opener()
+	if err := exec.Command(opener, cfg.DataDir).Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", opener, cfg.DataDir, err)
+	}
+	fmt.Fprintf(w, "Opened %s\n", cfg.DataDir)
+	return nil
+}
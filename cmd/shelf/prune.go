@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// runPruneImages implements `shelf prune-images`: delete image files that
+// no article references any more, after listing them and asking first.
+func runPruneImages(cfg config.Config, args []string, in io.Reader, w io.Writer) error {
+	fs := flag.NewFlagSet("prune-images", flag.ContinueOnError)
+	yes := fs.Bool("y", false, "delete without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	var paths []string // articles with orphaned images
+	var files int
+	var total int64
+	for _, a := range store.List() {
+		orphans, size, err := store.OrphanedImages(a.FilePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.FilePath, err)
+		}
+		if len(orphans) == 0 {
+			continue
+		}
+		paths = append(paths, a.FilePath)
+		files += len(orphans)
+		total += size
+		fmt.Fprintf(w, "%s (%s)\n", a.Title, storage.FormatSize(size))
+		for _, o := range orphans {
+			fmt.Fprintf(w, "  %s\n", o)
+		}
+	}
+	if files == 0 {
+		fmt.Fprintln(w, "No unreferenced images")
+		return nil
+	}
+
+	if !*yes {
+		fmt.Fprintf(w, "\nDelete %d unreferenced image(s) from %d article(s), %s? [y/N] ", files, len(paths), storage.FormatSize(total))
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Fprintln(w, "Nothing deleted")
+			return nil
+		}
+	}
+	var removed int
+	var freed int64
+	for _, p := range paths {
+		n, size, err := store.PruneImages(p)
+		removed += n
+		freed += size
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	fmt.Fprintf(w, "Deleted %d image(s), freed %s\n", removed, storage.FormatSize(freed))
+	return nil
+}
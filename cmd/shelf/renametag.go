@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runRenameTag implements `shelf rename-tag <old> <new>`: rename a tag on
+// every article carrying it, merging it into new where that's already used,
+// as r does in the TUI's tag sidebar.
+func runRenameTag(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("rename-tag", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: shelf rename-tag <old> <new>")
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	from, to := fs.Arg(0), fs.Arg(1)
+	n, err := store.RenameTag(from, to)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		fmt.Fprintf(w, "No articles tagged %q\n", from)
+		return nil
+	}
+	fmt.Fprintf(w, "Renamed %q to %q on %d article(s)\n", from, to, n)
+	return nil
+}
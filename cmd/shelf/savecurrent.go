@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// runSaveCurrent implements `shelf save-current`: save the article open in
+// Safari's frontmost tab, as pressing c in the TUI does. An article already
+// saved from the same URL is unarchived rather than fetched again.
+func runSaveCurrent(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("save-current", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.Endpoint == "" && cfg.ExtractStrategy != "local" {
+		return fmt.Errorf("endpoint not configured in %s", config.Path())
+	}
+
+	url, err := safari.FrontTabURL()
+	if err != nil {
+		return err
+	}
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	for _, a := range store.List() {
+		if a.SourceURL != url {
+			continue
+		}
+		if a.IsArchived() {
+			if err := store.SetArchived(a.FilePath, false); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Unarchived %q\n", a.Title)
+			return nil
+		}
+		fmt.Fprintf(w, "Already saved as %q\n", a.Title)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Fetching %s\n", url)
+	result, err := newExtractor(cfg).Extract(url)
+	if err != nil {
+		return err
+	}
+	images := make([]storage.ImageFile, len(result.Images))
+	for i, img := range result.Images {
+		images[i] = storage.ImageFile{Path: img.Path, Data: img.Data}
+	}
+	if err := store.SaveContent(result.Title, result.Content, images); err != nil {
+		var existsErr *storage.ErrArticleExists
+		if errors.As(err, &existsErr) {
+			return fmt.Errorf("%q is already saved", existsErr.Title)
+		}
+		return err
+	}
+	fmt.Fprintf(w, "Saved %q\n", result.Title)
+	return nil
+}
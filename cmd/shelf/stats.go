@@ -0,0 +1,80 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// runStats implements `shelf stats`: the domains whose articles took
+// longest to extract, from the fetch_ms: recorded when each was saved.
+func runStats(cfg config.Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	top := fs.Int("n", 10, "number of domains to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	type domainStats struct {
+		domain   string
+		articles int
+		total    time.Duration
+		slowest  time.Duration
+	}
+	byDomain := make(map[string]*domainStats)
+	var timed int
+	for _, a := range store.List() {
+		if a.FetchTime == 0 || a.SourceDomain == "" {
+			continue
+		}
+		timed++
+		d := byDomain[a.SourceDomain]
+		if d == nil {
+			d = &domainStats{domain: a.SourceDomain}
+			byDomain[a.SourceDomain] = d
+		}
+		d.articles++
+		d.total += a.FetchTime
+		d.slowest = max(d.slowest, a.FetchTime)
+	}
+	if timed == 0 {
+		fmt.Fprintln(w, "No fetch times recorded yet; they're saved with articles fetched from now on")
+		return nil
+	}
+
+	domains := make([]*domainStats, 0, len(byDomain))
+	for _, d := range byDomain {
+		domains = append(domains, d)
+	}
+	average := func(d *domainStats) time.Duration { return d.total / time.Duration(d.articles) }
+	slices.SortFunc(domains, func(a, b *domainStats) int {
+		return cmp.Or(cmp.Compare(average(b), average(a)), cmp.Compare(a.domain, b.domain))
+	})
+	if *top > 0 && len(domains) > *top {
+		domains = domains[:*top]
+	}
+
+	fmt.Fprintf(w, "Slowest domains (%d articles with fetch times)\n\n", timed)
+	fmt.Fprintf(w, "%-32s %8s %8s %8s\n", "DOMAIN", "ARTICLES", "AVERAGE", "SLOWEST")
+	for _, d := range domains {
+		fmt.Fprintf(w, "%-32s %8d %8s %8s\n", d.domain, d.articles, roundDuration(average(d)), roundDuration(d.slowest))
+	}
+	return nil
+}
+
+// roundDuration rounds d for display, e.g. to "350ms" or "2.4s".
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/mattn/go-runewidth"
 )
 
 const defaultConfigTmpl = `# Shelf configuration file.
@@ -13,13 +16,403 @@ const defaultConfigTmpl = `# Shelf configuration file.
 # Modal endpoint URL for HTML-to-Markdown conversion.
 endpoint = ""
 
+# Endpoints used instead of the one above for pages on particular sites.
+# "*.example.com" matches example.com and its subdomains; a leading "www."
+# is ignored.
+# [endpoints]
+# "medium.com" = "https://you--medium-convert.modal.run"
+# "*.substack.com" = "https://you--substack-convert.modal.run"
+
 # Directory where article data is stored.
 data_dir = %q
+
+# How pages are converted to markdown: "remote" (the endpoint fetches and
+# converts), "local" (fetched and converted here with simple heuristics; no
+# endpoint needed), or "auto" (local for simple static pages, the endpoint
+# for the rest).
+# extract_strategy = "remote"
+
+# Check at startup that the endpoint is reachable, warning in the header if
+# it isn't.
+# check_endpoint = true
+
+# Demote an article's leading H1 to H2, or drop it if it repeats the title
+# (which is already in the front matter).
+# demote_h1 = false
+
+# Command that converts a saved PDF to text, printed to stdout. "{}" is
+# replaced by the PDF's path; without it the PDF is piped to stdin. Quote
+# arguments with spaces as in a shell: "'/opt/my tools/pdf2txt' {}".
+# pdf_command = "pdftotext -layout {} -"
+
+# Batch import: maximum requests/sec to any single host, and the number of
+# articles fetched in parallel.
+# import_rate = 0.5
+# import_concurrency = 4
+
+# Look up page titles for Safari tabs that have none before opening the
+# import buffer. Adds a request per untitled tab; press Esc while they load
+# to open the buffer with the titles found so far.
+# import_fetch_titles = false
+
+# How tabs are arranged in the import buffer: "source" (by Safari source,
+# then domain), "recent" (flat, most recently viewed first), "domain" (by
+# domain across sources), or "title" (flat, alphabetical).
+# import_sort = "source"
+
+# Safari sources to gather tabs from on import, in the order the import
+# buffer lists them: "local" (open tabs), "icloud" (iCloud Tabs) and
+# "readinglist". Sources left out aren't read, so their permissions aren't
+# asked for.
+# import_sources = ["local", "icloud", "readinglist"]
+
+# Verbosity of the import log (shelf.log in data_dir): debug, info, warn,
+# error, or off.
+# log_level = "info"
+
+# Number of recent search queries remembered (recalled with up/down while
+# searching). Set to 0 to disable and clear the saved history.
+# search_history = 50
+
+# While searching, break the number of results down by the field the text
+# matched, e.g. "12 in title, 3 in tags", to see why things matched.
+# match_counts = true
+
+# Save the list filter (search query, saved search, archive visibility) on
+# exit and restore it at the next launch.
+# restore_session = false
+
+# How "d" deletes an article: "confirm" asks first; "dd" deletes immediately
+# on a vim-style double press.
+# delete_style = "confirm"
+
+# What to do when a URL or title being saved is already saved: "ask" (the
+# overwrite prompt, where a or s remembers "always overwrite" or "always
+# skip" for the rest of the session), "always" overwrite, or "never".
+# overwrite = "ask"
+
+# Watch this file while shelf runs and apply changes to the list's layout,
+# sort and time format, key macros, saved searches, messages and the like
+# without a restart. Settings such as data_dir and endpoint still need one;
+# shelf says which when they change.
+# watch_config = false
+
+# Ask "Quit? [y/n]" before q quits from the list, instead of quitting at once.
+# confirm_quit = false
+
+# What ctrl+c does away from the list: "cancel" backs out of the current
+# screen or operation, as Esc does; "quit" quits shelf from anywhere.
+# ctrl_c = "cancel"
+
+# Keys that run several actions on the selected article in one go, in
+# order: "tag:<tag>", "untag:<tag>", "archive", "unarchive", "pin",
+# "unpin", "lock", "unlock" or "reset-progress". Keys shelf already uses
+# can't be bound.
+# macros = [{key = "A", actions = ["tag:read-later", "archive"]}]
+
+# What Enter does with an article: "editor" ($EDITOR, tracks reading
+# progress), "pager" ($PAGER), "browser" (the source URL), or "preview"
+# (inside shelf). The others stay available on E, v, o and p.
+# open_action = "editor"
+
+# Inside tmux, whether opening an article reuses the editor pane already
+# open ("reuse", switching vim/nvim to the new file) or splits a new pane for
+# each article ("new"), e.g. to read two side by side.
+# tmux_panes = "reuse"
+
+# How often, in seconds, vim/nvim checkpoints your reading position while an
+# article is open, so a crash loses at most this much; inside tmux shelf
+# saves it as progress as you go. 0 saves it only when the editor exits.
+# checkpoint = 60
+
+# Article list layout: "comfortable" (title, then details and tags on a
+# second line) or "compact" (one line per article, fitting about three times
+# as many).
+# density = "comfortable"
+
+# How the list is ordered: "date" (newest first), "manual" (by each
+# article's order: front matter field, set by moving it with K and J, e.g.
+# for a course reading list; articles never moved follow, newest first), or
+# "size" (largest first, images included, to find what takes up space).
+# Archived and pinned articles are grouped as usual either way.
+# sort = "date"
+
+# Show the section of the site an article is from next to its domain, e.g.
+# "example.com · blog" for example.com/blog/..., to tell apart articles
+# from different parts of a large site.
+# source_section = false
+
+# Mark each article in the list with a dot colored by its source's domain,
+# the same for every article from it, to pick out where things are from at
+# a glance.
+# domain_marks = false
+
+# How the list shows when an article was saved: "relative" ("3 days ago"),
+# "short" ("3d"), or "absolute" (the date, formatted with date_layout using
+# Go's reference time, Mon Jan 2 15:04:05 2006).
+# time_format = "relative"
+# date_layout = "2006-01-02"
+
+# What ends text cut short to fit, such as long titles: "..." or the
+# narrower "…", at most three columns wide.
+# ellipsis = "..."
+
+# A TOML file translating the TUI's messages, e.g. view.fetching = "...".
+# Messages it leaves out stay in English; see pkg/tui/messages.go for the
+# identifiers. Relative paths are resolved against ~/.shelf.
+# locale = "de.toml"
+
+# Spinner shown while fetching and importing: dot, minidot, line, jump,
+# pulse, points, globe, moon, monkey, meter, hamburger or ellipsis.
+# spinner = "dot"
+
+# Tag that marks an article as archived ("x" toggles it), and other tags
+# that also count as archived, e.g. from an older convention.
+# archive_tag = "archived"
+# archive_aliases = ["done"]
+
+# Ask why when archiving with "x", keeping the answer as archive_note: in the
+# front matter (shown by peek and preview). Enter with nothing typed
+# archives without one.
+# archive_note = false
+
+# Tags added to every newly saved article, alongside any it's given when
+# saved (e.g. by an import). Re-fetching an article doesn't add them again.
+# default_tags = ["inbox"]
+
+# Name of the directory, inside each article's directory, that its images
+# are saved in. Some sync tools treat "images" specially; "assets" or "media"
+# may suit them better. Existing articles keep the directory they have.
+# image_dir = "images"
+
+# Individual messages, overriding both English and the locale file, e.g.
+# the loading messages.
+# [messages]
+# "view.fetching" = "Fetching, hang on..."
+# "view.importing" = "Saving tab %%d of %%d..."
+
+# Saved searches, picked with "s". Queries accept plain text plus tag:,
+# domain: and status: (unread, reading, archived) filters.
+# [[saved_search]]
+# name = "Rust, unread"
+# query = "tag:rust status:unread"
 `
 
 type Config struct {
 	Endpoint string `toml:"endpoint"`
 	DataDir  string `toml:"data_dir"`
+
+	// Endpoints maps sites, e.g. "medium.com" or "*.substack.com", to
+	// endpoints used instead of Endpoint for their pages.
+	Endpoints map[string]string `toml:"endpoints"`
+
+	// ExtractStrategy is how pages are converted: "remote" (the endpoint
+	// fetches and converts), "local" (fetched and converted here, without
+	// the endpoint), or "auto" (local for simple static pages, the endpoint
+	// otherwise).
+	ExtractStrategy string `toml:"extract_strategy"`
+	// CheckEndpoint checks at startup that the endpoint is reachable and
+	// warns in the header if not.
+	CheckEndpoint bool `toml:"check_endpoint"`
+	// DemoteH1 demotes a saved article's leading H1 to H2, or drops it if
+	// it repeats the title.
+	DemoteH1 bool `toml:"demote_h1"`
+	// PDFCommand converts a saved PDF to text: "{}" is replaced by the
+	// PDF's path, or the PDF is piped to stdin if there is none. It's split
+	// into arguments with SplitCommand.
+	PDFCommand string `toml:"pdf_command"`
+
+	// ImportRate is the maximum number of requests per second sent to any
+	// single host during batch import. Zero or negative disables limiting.
+	ImportRate float64 `toml:"import_rate"`
+	// ImportConcurrency is the maximum number of articles fetched in
+	// parallel during batch import.
+	ImportConcurrency int `toml:"import_concurrency"`
+	// ImportFetchTitles fetches the <title> of untitled Safari tabs so the
+	// import buffer shows something more recognizable than the URL.
+	ImportFetchTitles bool `toml:"import_fetch_titles"`
+	// ImportSort arranges tabs in the import buffer: "source", "recent",
+	// "domain" or "title".
+	ImportSort string `toml:"import_sort"`
+	// ImportSources are the Safari sources gathered on import, in the
+	// order the import buffer lists them: "local", "icloud" and
+	// "readinglist".
+	ImportSources []string `toml:"import_sources"`
+
+	// LogLevel controls what is written to shelf.log in the data directory:
+	// "debug", "info", "warn", "error", or "off".
+	LogLevel string `toml:"log_level"`
+
+	// SearchHistory is the number of recent search queries kept in the data
+	// directory. Zero disables history.
+	SearchHistory int `toml:"search_history"`
+
+	// MatchCounts shows, while searching, how many results matched in each
+	// field.
+	MatchCounts bool `toml:"match_counts"`
+
+	// RestoreSession saves the list filter (search query, saved search and
+	// archive visibility) on exit and restores it at the next launch.
+	RestoreSession bool `toml:"restore_session"`
+
+	// DeleteStyle is "confirm" (d, then y to confirm) or "dd" (delete on a
+	// double press, without confirmation).
+	DeleteStyle string `toml:"delete_style"`
+
+	// Overwrite is what happens when an article being saved is already
+	// saved: "ask", "always" (overwrite it) or "never" (keep it).
+	Overwrite string `toml:"overwrite"`
+
+	// WatchConfig reloads this file when it changes while shelf runs.
+	WatchConfig bool `toml:"watch_config"`
+
+	// ConfirmQuit asks for confirmation before q quits from the list.
+	ConfirmQuit bool `toml:"confirm_quit"`
+
+	// CtrlC is what ctrl+c does away from the list: "cancel" backs out of
+	// the current screen, "quit" quits.
+	CtrlC string `toml:"ctrl_c"`
+
+	// Macros are keys that run a sequence of actions on the selected
+	// article.
+	Macros []Macro `toml:"macros"`
+
+	// OpenAction is what Enter does: "editor", "pager", "browser" or
+	// "preview".
+	OpenAction string `toml:"open_action"`
+
+	// TmuxPanes is "reuse" (one editor pane, switched between articles) or
+	// "new" (a pane per opened article) when running inside tmux.
+	TmuxPanes string `toml:"tmux_panes"`
+
+	// Checkpoint is how often, in seconds, the reading position in vim/nvim
+	// is saved while an article is open; 0 only saves it on exit.
+	Checkpoint int `toml:"checkpoint"`
+
+	// Density is the article list layout: "comfortable" (two lines per
+	// article) or "compact" (one).
+	Density string `toml:"density"`
+
+	// Sort is how the list is ordered: "date", "manual" or "size".
+	Sort string `toml:"sort"`
+
+	// SourceSection shows the first segment of an article's source path,
+	// e.g. "blog", beside its domain in the list.
+	SourceSection bool `toml:"source_section"`
+
+	// DomainMarks marks each article in the list with a dot colored by its
+	// source domain.
+	DomainMarks bool `toml:"domain_marks"`
+
+	// TimeFormat is how saved times are shown in the list: "relative",
+	// "short" or "absolute".
+	TimeFormat string `toml:"time_format"`
+	// DateLayout is the time.Format layout for absolute times.
+	DateLayout string `toml:"date_layout"`
+	// Ellipsis marks text truncated to fit, e.g. "..." or "…".
+	Ellipsis string `toml:"ellipsis"`
+
+	// Locale is a file of translated TUI messages, or "" for English.
+	Locale string `toml:"locale"`
+	// Messages override individual TUI messages by identifier, on top of
+	// Locale.
+	Messages map[string]string `toml:"messages"`
+
+	// Spinner names the spinner shown while fetching and importing.
+	Spinner string `toml:"spinner"`
+
+	// ArchiveTag is the tag that marks an article as archived.
+	ArchiveTag string `toml:"archive_tag"`
+	// ArchiveAliases are other tags treated as archived when filtering and
+	// sorting. Archiving always adds ArchiveTag.
+	ArchiveAliases []string `toml:"archive_aliases"`
+	// ArchiveNote asks for an optional reason when archiving.
+	ArchiveNote bool `toml:"archive_note"`
+
+	// DefaultTags are added to every newly saved article.
+	DefaultTags []string `toml:"default_tags"`
+
+	// ImageDir names the directory inside each article's directory that
+	// its images are saved in.
+	ImageDir string `toml:"image_dir"`
+
+	// SavedSearches are named queries offered by the saved-search picker.
+	SavedSearches []SavedSearch `toml:"saved_search"`
+
+	// UnknownKeys are keys in the file that aren't settings, e.g. a
+	// misspelled one. Load reports them here, for a warning, rather than
+	// failing.
+	UnknownKeys []string `toml:"-"`
+}
+
+// SavedSearch is a named search query, e.g. "Rust, unread" for
+// "tag:rust status:unread".
+type SavedSearch struct {
+	Name  string `toml:"name"`
+	Query string `toml:"query"`
+}
+
+// Macro binds a key in the article list to actions run in order on the
+// selected article, e.g. "A" to ["tag:read-later", "archive"].
+type Macro struct {
+	Key     string   `toml:"key"`
+	Actions []string `toml:"actions"`
+}
+
+// BuiltinKeys are the keys the article list already handles, which a macro
+// can't bind: they'd never reach it. They must be the keys of
+// tui.DefaultKeyMap, which tui's tests check.
+var BuiltinKeys = []string{
+	"up", "down", "k", "j", "g", "home", "end", "enter", "esc", "tab", " ",
+	"a", "c", "d", "e", "f", "i", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y",
+	"D", "E", "F", "G", "I", "J", "K", "L", "N", "O", "P", "R", "W", "X",
+	"0", "/", "?", ">", "ctrl+c", "ctrl+r",
+}
+
+// validMacroAction reports whether action is one a macro can run.
+func validMacroAction(action string) bool {
+	if tag, ok := strings.CutPrefix(action, "tag:"); ok {
+		return strings.TrimSpace(tag) != ""
+	}
+	if tag, ok := strings.CutPrefix(action, "untag:"); ok {
+		return strings.TrimSpace(tag) != ""
+	}
+	switch action {
+	case "archive", "unarchive", "pin", "unpin", "lock", "unlock", "reset-progress":
+		return true
+	}
+	return false
+}
+
+// defaults returns the configuration used for any key not present in the
+// config file.
+func defaults() Config {
+	return Config{
+		ExtractStrategy:   "remote",
+		CheckEndpoint:     true,
+		ImportRate:        0.5,
+		ImportConcurrency: 4,
+		LogLevel:          "info",
+		SearchHistory:     50,
+		MatchCounts:       true,
+		ImportSort:        "source",
+		ImportSources:     []string{"local", "icloud", "readinglist"},
+		DeleteStyle:       "confirm",
+		Overwrite:         "ask",
+		CtrlC:             "cancel",
+		OpenAction:        "editor",
+		TmuxPanes:         "reuse",
+		Checkpoint:        60,
+		Density:           "comfortable",
+		Sort:              "date",
+		TimeFormat:        "relative",
+		DateLayout:        "2006-01-02",
+		Ellipsis:          "...",
+		Spinner:           "dot",
+		ArchiveTag:        "archived",
+		ImageDir:          "images",
+		PDFCommand:        "pdftotext -layout {} -",
+	}
 }
 
 // Dir returns the shelf configuration directory (~/.shelf).
@@ -37,6 +430,13 @@ func Path() string {
 	return filepath.Join(dir, "shelf.toml")
 }
 
+// TemplatePath returns the path to the note template, which new notes and
+// imported files start from if it exists.
+func TemplatePath() string {
+	dir, _ := Dir()
+	return filepath.Join(dir, "template.md")
+}
+
 // Load reads the config from ~/.shelf/shelf.toml, creating a default
 // config file if one doesn't exist.
 func Load() (Config, error) {
@@ -58,10 +458,14 @@ func Load() (Config, error) {
 		}
 	}
 
-	var cfg Config
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	cfg := defaults()
+	md, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
 		return Config{}, fmt.Errorf("could not parse %s: %w", path, err)
 	}
+	for _, key := range md.Undecoded() {
+		cfg.UnknownKeys = append(cfg.UnknownKeys, key.String())
+	}
 
 	// Expand ~ in data_dir.
 	if len(cfg.DataDir) >= 2 && cfg.DataDir[:2] == "~/" {
@@ -71,6 +475,219 @@ func Load() (Config, error) {
 		}
 		cfg.DataDir = filepath.Join(home, cfg.DataDir[2:])
 	}
+	// Expand ~ in locale, and resolve it against the config directory.
+	if len(cfg.Locale) >= 2 && cfg.Locale[:2] == "~/" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Config{}, fmt.Errorf("could not determine home directory: %w", err)
+		}
+		cfg.Locale = filepath.Join(home, cfg.Locale[2:])
+	} else if cfg.Locale != "" && !filepath.IsAbs(cfg.Locale) {
+		cfg.Locale = filepath.Join(dir, cfg.Locale)
+	}
+
+	if cfg.ImportConcurrency < 1 {
+		cfg.ImportConcurrency = 1
+	}
+	switch cfg.ExtractStrategy {
+	case "remote", "local", "auto":
+	default:
+		return Config{}, fmt.Errorf("invalid extract_strategy %q in %s: want \"remote\", \"local\" or \"auto\"", cfg.ExtractStrategy, path)
+	}
+	for domain, endpoint := range cfg.Endpoints {
+		if !validEndpointDomain(domain) {
+			return Config{}, fmt.Errorf("invalid endpoints key %q in %s: want a host name, e.g. \"medium.com\", or a wildcard, e.g. \"*.substack.com\"", domain, path)
+		}
+		if endpoint == "" {
+			return Config{}, fmt.Errorf("empty endpoint for %q in %s", domain, path)
+		}
+	}
+	switch cfg.ImportSort {
+	case "source", "recent", "domain", "title":
+	default:
+		return Config{}, fmt.Errorf("invalid import_sort %q in %s: want \"source\", \"recent\", \"domain\" or \"title\"", cfg.ImportSort, path)
+	}
+	if len(cfg.ImportSources) == 0 {
+		return Config{}, fmt.Errorf("import_sources in %s is empty: want one or more of \"local\", \"icloud\" and \"readinglist\"", path)
+	}
+	for i, source := range cfg.ImportSources {
+		switch source {
+		case "local", "icloud", "readinglist":
+		default:
+			return Config{}, fmt.Errorf("invalid import_sources entry %q in %s: want \"local\", \"icloud\" or \"readinglist\"", source, path)
+		}
+		if slices.Contains(cfg.ImportSources[:i], source) {
+			return Config{}, fmt.Errorf("import_sources in %s lists %q twice", path, source)
+		}
+	}
+	switch cfg.DeleteStyle {
+	case "confirm", "dd":
+	default:
+		return Config{}, fmt.Errorf("invalid delete_style %q in %s: want \"confirm\" or \"dd\"", cfg.DeleteStyle, path)
+	}
+	switch cfg.Overwrite {
+	case "ask", "always", "never":
+	default:
+		return Config{}, fmt.Errorf("invalid overwrite %q in %s: want \"ask\", \"always\" or \"never\"", cfg.Overwrite, path)
+	}
+	switch cfg.CtrlC {
+	case "cancel", "quit":
+	default:
+		return Config{}, fmt.Errorf("invalid ctrl_c %q in %s: want \"cancel\" or \"quit\"", cfg.CtrlC, path)
+	}
+	for i, mac := range cfg.Macros {
+		if mac.Key == "" {
+			return Config{}, fmt.Errorf("macro %d in %s has no key", i+1, path)
+		}
+		if len(mac.Actions) == 0 {
+			return Config{}, fmt.Errorf("macro %q in %s has no actions", mac.Key, path)
+		}
+		if slices.Contains(BuiltinKeys, mac.Key) {
+			return Config{}, fmt.Errorf("invalid macro key %q in %s: shelf already uses it", mac.Key, path)
+		}
+		for _, action := range mac.Actions {
+			if !validMacroAction(action) {
+				return Config{}, fmt.Errorf("invalid action %q in macro %q in %s: want tag:<tag>, untag:<tag>, archive, unarchive, pin, unpin, lock, unlock or reset-progress", action, mac.Key, path)
+			}
+		}
+		for _, prev := range cfg.Macros[:i] {
+			if prev.Key == mac.Key {
+				return Config{}, fmt.Errorf("macros in %s bind %q twice", path, mac.Key)
+			}
+		}
+	}
+	switch cfg.OpenAction {
+	case "editor", "pager", "browser", "preview":
+	default:
+		return Config{}, fmt.Errorf("invalid open_action %q in %s: want \"editor\", \"pager\", \"browser\" or \"preview\"", cfg.OpenAction, path)
+	}
+	switch cfg.TmuxPanes {
+	case "reuse", "new":
+	default:
+		return Config{}, fmt.Errorf("invalid tmux_panes %q in %s: want \"reuse\" or \"new\"", cfg.TmuxPanes, path)
+	}
+	if cfg.Checkpoint < 0 {
+		return Config{}, fmt.Errorf("invalid checkpoint %d in %s: want seconds, or 0 to save only on exit", cfg.Checkpoint, path)
+	}
+	switch cfg.Density {
+	case "comfortable", "compact":
+	default:
+		return Config{}, fmt.Errorf("invalid density %q in %s: want \"comfortable\" or \"compact\"", cfg.Density, path)
+	}
+	switch cfg.Sort {
+	case "date", "manual", "size":
+	default:
+		return Config{}, fmt.Errorf("invalid sort %q in %s: want \"date\", \"manual\" or \"size\"", cfg.Sort, path)
+	}
+	switch cfg.TimeFormat {
+	case "relative", "short", "absolute":
+	default:
+		return Config{}, fmt.Errorf("invalid time_format %q in %s: want \"relative\", \"short\" or \"absolute\"", cfg.TimeFormat, path)
+	}
+	if cfg.DateLayout == "" {
+		return Config{}, fmt.Errorf("empty date_layout in %s", path)
+	}
+	if runewidth.StringWidth(cfg.Ellipsis) > 3 {
+		return Config{}, fmt.Errorf("invalid ellipsis %q in %s: want at most 3 columns, e.g. \"...\" or \"…\"", cfg.Ellipsis, path)
+	}
+	switch cfg.Spinner {
+	case "dot", "minidot", "line", "jump", "pulse", "points", "globe", "moon", "monkey", "meter", "hamburger", "ellipsis":
+	default:
+		return Config{}, fmt.Errorf("invalid spinner %q in %s: want one of dot, minidot, line, jump, pulse, points, globe, moon, monkey, meter, hamburger or ellipsis", cfg.Spinner, path)
+	}
+	if argv, err := SplitCommand(cfg.PDFCommand); err != nil {
+		return Config{}, fmt.Errorf("invalid pdf_command %q in %s: %v", cfg.PDFCommand, path, err)
+	} else if len(argv) == 0 {
+		return Config{}, fmt.Errorf("empty pdf_command in %s", path)
+	}
+	if !validImageDir(cfg.ImageDir) {
+		return Config{}, fmt.Errorf("invalid image_dir %q in %s: want a single directory name of letters, digits, '.', '-' or '_'", cfg.ImageDir, path)
+	}
+	for _, tag := range cfg.DefaultTags {
+		if strings.TrimSpace(tag) == "" || strings.ContainsAny(tag, ",;\n") {
+			return Config{}, fmt.Errorf("invalid default_tags entry %q in %s: want a tag name, without commas or semicolons", tag, path)
+		}
+	}
 
 	return cfg, nil
 }
+
+// SplitCommand splits a command line such as pdf_command into arguments
+// the way a shell would, without expanding anything: they're separated by
+// spaces, which single or double quotes or a backslash keep in one, e.g.
+// "'/opt/my tools/pdf2txt' {}".
+func SplitCommand(s string) ([]string, error) {
+	var (
+		args  []string
+		arg   strings.Builder
+		inArg bool
+		quote rune
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(rs) && (rs[i+1] == '"' || rs[i+1] == '\\'):
+				i++
+				arg.WriteRune(rs[i])
+			default:
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '\\' && i+1 < len(rs):
+			i++
+			arg.WriteRune(rs[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// validEndpointDomain reports whether domain can be a key of endpoints: a
+// host name, optionally with a leading "*." wildcard.
+func validEndpointDomain(domain string) bool {
+	domain = strings.TrimPrefix(domain, "*.")
+	return domain != "" && !strings.ContainsAny(domain, "*/: ")
+}
+
+// validImageDir reports whether name can be used as image_dir: a single
+// path component that needs no escaping in a markdown link.
+func validImageDir(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
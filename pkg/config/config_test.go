@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// The default config file written on first run has no unknown keys.
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.UnknownKeys) != 0 {
+		t.Fatalf("default config: unknown keys = %q, want none", cfg.UnknownKeys)
+	}
+
+	contents := `endpont = "http://localhost:8080"
+archive_tag = "done"
+
+[[macros]]
+key = "A"
+actions = ["archive"]
+acton = "pin"
+`
+	if err := os.WriteFile(config.Path(), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"endpont", "macros.acton"}; !reflect.DeepEqual(cfg.UnknownKeys, want) {
+		t.Fatalf("unknown keys = %q, want %q", cfg.UnknownKeys, want)
+	}
+	if cfg.ArchiveTag != "done" {
+		t.Fatalf("archive tag = %q, want the known keys to still apply", cfg.ArchiveTag)
+	}
+}
+
+func TestMacroBuiltinKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	contents := "[[macros]]\nkey = \"d\"\nactions = [\"archive\"]\n"
+	if err := os.WriteFile(config.Path(), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), `invalid macro key "d"`) {
+		t.Errorf("macro bound to d: err = %v, want it rejected", err)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"pdftotext -layout {} -", []string{"pdftotext", "-layout", "{}", "-"}},
+		{`'/opt/my tools/pdf2txt' {}`, []string{"/opt/my tools/pdf2txt", "{}"}},
+		{`"/opt/my tools/pdf2txt" --title "say \"hi\"" {}`, []string{"/opt/my tools/pdf2txt", "--title", `say "hi"`, "{}"}},
+		{`/opt/my\ tools/pdf2txt ''`, []string{"/opt/my tools/pdf2txt", ""}},
+		{"  ", nil},
+	} {
+		got, err := config.SplitCommand(tc.in)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("SplitCommand(%q) = %q, %v; want %q", tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := config.SplitCommand(`"/opt/my tools/pdf2txt {}`); err == nil {
+		t.Errorf("unterminated quote accepted")
+	}
+}
@@ -1,6 +1,7 @@
 package extractor_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -18,14 +19,20 @@ func TestConvert(t *testing.T) {
 		t.Skip("skipping modal tests (set MODAL=1 to run)")
 	}
 	datadriven.Walk(t, "testdata/convert", func(t *testing.T, path string) {
-		var state string
+		var state convertState
 		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
 			return runCmd(t, d, &state)
 		})
 	})
 }
 
-func runCmd(t *testing.T, d *datadriven.TestData, state *string) string {
+// convertState is carried between the directives of a convert test file.
+type convertState struct {
+	content string // output of the last convert or process
+	fixture string // <slug>.html of the last process; "" after convert
+}
+
+func runCmd(t *testing.T, d *datadriven.TestData, state *convertState) string {
 	t.Helper()
 	switch d.Cmd {
 	case "convert":
@@ -34,13 +41,15 @@ func runCmd(t *testing.T, d *datadriven.TestData, state *string) string {
 		return cmdProcess(t, d, state)
 	case "lines":
 		return cmdLines(t, d, state)
+	case "stats":
+		return cmdStats(t, d, state)
 	default:
 		d.Fatalf(t, "unknown command %q", d.Cmd)
 		return ""
 	}
 }
 
-func cmdConvert(t *testing.T, d *datadriven.TestData, state *string) string {
+func cmdConvert(t *testing.T, d *datadriven.TestData, state *convertState) string {
 	t.Helper()
 	if len(d.CmdArgs) == 0 {
 		d.Fatalf(t, "convert requires a URL argument")
@@ -53,11 +62,11 @@ func cmdConvert(t *testing.T, d *datadriven.TestData, state *string) string {
 		d.Fatalf(t, "extracting %s: %v", url, err)
 	}
 
-	*state = result.Content
+	*state = convertState{content: result.Content}
 	return ""
 }
 
-func cmdProcess(t *testing.T, d *datadriven.TestData, state *string) string {
+func cmdProcess(t *testing.T, d *datadriven.TestData, state *convertState) string {
 	t.Helper()
 	if len(d.CmdArgs) == 0 {
 		d.Fatalf(t, "process requires a <slug>.html argument")
@@ -76,11 +85,11 @@ func cmdProcess(t *testing.T, d *datadriven.TestData, state *string) string {
 		d.Fatalf(t, "processing %s: %v", slug, err)
 	}
 
-	*state = result.Content
+	*state = convertState{content: result.Content, fixture: slug}
 	return ""
 }
 
-func cmdLines(t *testing.T, d *datadriven.TestData, state *string) string {
+func cmdLines(t *testing.T, d *datadriven.TestData, state *convertState) string {
 	t.Helper()
 	if len(d.CmdArgs) == 0 {
 		d.Fatalf(t, "lines requires a range argument (e.g., 1-5)")
@@ -101,7 +110,7 @@ func cmdLines(t *testing.T, d *datadriven.TestData, state *string) string {
 		d.Fatalf(t, "invalid end line %q: %v", parts[1], err)
 	}
 
-	lines := strings.Split(*state, "\n")
+	lines := strings.Split(state.content, "\n")
 	if start < 1 {
 		start = 1
 	}
@@ -120,3 +129,82 @@ func cmdLines(t *testing.T, d *datadriven.TestData, state *string) string {
 	}
 	return strings.Join(selected, "\n") + "\n"
 }
+
+// wordsPerMinute is the reading speed behind a fixture's reading time.
+const wordsPerMinute = 230
+
+// fixtureStats is a fixture's metadata sidecar, fixtures/<slug>.json: the
+// size of its processed article when last recorded.
+type fixtureStats struct {
+	Words          int `json:"words"`
+	ReadingMinutes int `json:"reading_minutes"`
+}
+
+// statsOf measures the body of converted markdown, front matter aside.
+func statsOf(content string) fixtureStats {
+	body := content
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		if _, after, ok := strings.Cut(rest, "\n---\n"); ok {
+			body = after
+		}
+	}
+	words := len(strings.Fields(body))
+	return fixtureStats{Words: words, ReadingMinutes: (words + wordsPerMinute - 1) / wordsPerMinute}
+}
+
+// cmdStats checks the word count and reading time of the fixture last
+// processed against its sidecar, within tolerance=N percent (default 10),
+// to catch content silently going missing. The sidecar must exist; it is
+// (re-)recorded when run with -rewrite.
+func cmdStats(t *testing.T, d *datadriven.TestData, state *convertState) string {
+	t.Helper()
+	if state.fixture == "" {
+		d.Fatalf(t, "stats requires a preceding process <slug>.html")
+	}
+	tolerance := 10
+	if d.HasArg("tolerance") {
+		d.ScanArgs(t, "tolerance", &tolerance)
+	}
+
+	got := statsOf(state.content)
+	sidecar := filepath.Join("fixtures", strings.TrimSuffix(state.fixture, ".html")+".json")
+	if d.Rewrite {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			d.Fatalf(t, "encoding %s: %v", sidecar, err)
+		}
+		if err := os.WriteFile(sidecar, append(data, '\n'), 0644); err != nil {
+			d.Fatalf(t, "writing %s: %v", sidecar, err)
+		}
+		t.Logf("recorded %s: %d words, %d min", sidecar, got.Words, got.ReadingMinutes)
+		return ""
+	}
+	data, err := os.ReadFile(sidecar)
+	if os.IsNotExist(err) {
+		d.Fatalf(t, "%s is missing; record it with -rewrite", sidecar)
+	} else if err != nil {
+		d.Fatalf(t, "reading %s: %v", sidecar, err)
+	}
+	var want fixtureStats
+	if err := json.Unmarshal(data, &want); err != nil {
+		d.Fatalf(t, "parsing %s: %v", sidecar, err)
+	}
+
+	within := func(got, want int) bool {
+		return abs(got-want) <= max(1, want*tolerance/100)
+	}
+	if !within(got.Words, want.Words) {
+		d.Fatalf(t, "%s: %d words, want %d ±%d%%", state.fixture, got.Words, want.Words, tolerance)
+	}
+	if !within(got.ReadingMinutes, want.ReadingMinutes) {
+		d.Fatalf(t, "%s: %d min to read, want %d ±%d%%", state.fixture, got.ReadingMinutes, want.ReadingMinutes, tolerance)
+	}
+	return ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,236 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// defaultPDFCommand converts a PDF to text with poppler's pdftotext.
+var defaultPDFCommand = []string{"pdftotext", "-layout", "{}", "-"}
+
+// UnsupportedContentTypeError is returned by Extract for URLs that serve
+// something other than an HTML page, plain text or a PDF.
+type UnsupportedContentTypeError struct {
+	ContentType string // media type the URL was served as, e.g. "image/png"
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("can't save %s; only HTML pages, plain text and PDFs are supported", e.ContentType)
+}
+
+// WithPDFCommand sets the command that converts a PDF to text. It must
+// print the text to stdout. An argument of "{}" is replaced by the path to
+// the PDF; without one, the PDF is written to the command's stdin. The
+// default is "pdftotext -layout {} -".
+func WithPDFCommand(argv ...string) Option {
+	return func(e *Extractor) {
+		if len(argv) > 0 {
+			e.pdfCommand = argv
+		}
+	}
+}
+
+// mediaType returns the media type of a Content-Type header, lowercased and
+// without parameters.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// headTimeout limits the HEAD request headMediaType makes before every
+// remote extraction, so a slow server doesn't add the whole request timeout
+// to it.
+const headTimeout = 5 * time.Second
+
+// headMediaType returns the media type sourceURL is served as, asking with a
+// HEAD request. It returns "" if the server doesn't say or the request
+// fails or takes longer than headTimeout.
+func (e *Extractor) headMediaType(sourceURL string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), headTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, sourceURL, nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ""
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	return mediaType(resp.Header.Get("Content-Type"))
+}
+
+// convertDocument converts a fetched page that isn't HTML. Plain text is
+// kept as it is and PDFs are converted with the PDF command; both are
+// titled from the URL's file name unless a PDF has a title of its own.
+func (e *Extractor) convertDocument(sourceURL string, pg *page) (*ExtractResult, error) {
+	var title, body string
+	switch pg.mediaType {
+	case "text/plain", "text/markdown", "text/x-markdown":
+		body = pg.body
+	case "application/pdf", "application/x-pdf":
+		text, err := e.pdfText([]byte(pg.body))
+		if err != nil {
+			return nil, err
+		}
+		body = strings.ReplaceAll(text, "\f", "\n\n")
+		title = pdfTitle([]byte(pg.body))
+	default:
+		return nil, &UnsupportedContentTypeError{ContentType: pg.mediaType}
+	}
+
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return nil, fmt.Errorf("no article content found")
+	}
+	if title == "" {
+		title = fileTitle(sourceURL)
+	}
+	return &ExtractResult{
+		Title:   title,
+		Content: frontMatter(title, "", sourceURL) + body + "\n",
+	}, nil
+}
+
+// pdfText converts a PDF to text with the PDF command.
+func (e *Extractor) pdfText(data []byte) (string, error) {
+	argv := slices.Clone(e.pdfCommand)
+	var stdin bool
+	if i := slices.Index(argv, "{}"); i < 0 {
+		stdin = true
+	} else {
+		f, err := os.CreateTemp("", "shelf-*.pdf")
+		if err != nil {
+			return "", fmt.Errorf("converting PDF: %w", err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", fmt.Errorf("converting PDF: %w", err)
+		}
+		argv[i] = f.Name()
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if stdin {
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("converting PDF: %s not found; install it or set pdf_command", argv[0])
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("converting PDF: %s: %s", argv[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("converting PDF: %w", err)
+	}
+	return string(out), nil
+}
+
+var (
+	// pdfTitleLiteral and pdfTitleHex match the /Title entry of a PDF's
+	// document information, as a literal string or a hex string. PDFs that
+	// keep it in a compressed object stream don't match.
+	pdfTitleLiteral = regexp.MustCompile(`/Title\s*\(((?:\\.|[^\\)])*)\)`)
+	pdfTitleHex     = regexp.MustCompile(`/Title\s*<([0-9A-Fa-f\s]*)>`)
+	pdfEscape       = regexp.MustCompile(`\\([0-7]{1,3}|.)`)
+)
+
+// pdfTitle returns the title in a PDF's document information, or "" if it
+// has none that can be read.
+func pdfTitle(data []byte) string {
+	var raw []byte
+	if m := pdfTitleLiteral.FindSubmatch(data); m != nil {
+		raw = pdfEscape.ReplaceAllFunc(m[1], func(esc []byte) []byte {
+			c := esc[1:]
+			if c[0] >= '0' && c[0] <= '7' {
+				var n int
+				for _, d := range c {
+					n = n*8 + int(d-'0')
+				}
+				return []byte{byte(n)}
+			}
+			switch c[0] {
+			case 'n':
+				return []byte{'\n'}
+			case 'r':
+				return []byte{'\r'}
+			case 't':
+				return []byte{'\t'}
+			}
+			return c
+		})
+	} else if m := pdfTitleHex.FindSubmatch(data); m != nil {
+		digits := strings.Join(strings.Fields(string(m[1])), "")
+		if len(digits)%2 == 1 {
+			digits += "0"
+		}
+		var err error
+		if raw, err = hex.DecodeString(digits); err != nil {
+			return ""
+		}
+	}
+
+	// Text strings are UTF-16BE if they start with a byte order mark, and
+	// otherwise PDFDocEncoding, which matches Latin-1 for printable text.
+	var title string
+	if len(raw) >= 2 && raw[0] == 0xfe && raw[1] == 0xff {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		title = string(utf16.Decode(units))
+	} else {
+		runes := make([]rune, len(raw))
+		for i, b := range raw {
+			runes[i] = rune(b)
+		}
+		title = string(runes)
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// fileTitle makes a title from the file name at the end of a URL's path,
+// e.g. "release notes" for ".../release-notes.txt", falling back to the
+// host.
+func fileTitle(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return sourceURL
+	}
+	name := path.Base(u.Path)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	}), " ")
+	if name == "" || name == "/" {
+		return u.Host
+	}
+	return name
+}
@@ -0,0 +1,52 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WithDomainEndpoints sets endpoints to use instead of the default one
+// (WithEndpoint) for pages on particular sites. Keys are host names, e.g.
+// "medium.com", or wildcards, e.g. "*.substack.com", which match the
+// domain and any subdomain of it. Hosts are compared lowercased and
+// without a leading "www.". An exact match is used over a wildcard, and a
+// longer wildcard over a shorter one; pages that match nothing use the
+// default endpoint.
+func WithDomainEndpoints(endpoints map[string]string) Option {
+	return func(e *Extractor) {
+		e.domainEndpoints = make(map[string]string, len(endpoints))
+		for domain, endpointURL := range endpoints {
+			e.domainEndpoints[normalizeHost(domain)] = endpointURL
+		}
+	}
+}
+
+// endpointFor returns the endpoint that converts sourceURL.
+func (e *Extractor) endpointFor(sourceURL string) string {
+	if len(e.domainEndpoints) == 0 {
+		return e.endpointURL
+	}
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return e.endpointURL
+	}
+	host := normalizeHost(u.Hostname())
+	if endpointURL, ok := e.domainEndpoints[host]; ok {
+		return endpointURL
+	}
+	// Try wildcards from the longest suffix of host down:
+	// "*.a.b.com", then "*.b.com", then "*.com".
+	for domain := host; domain != ""; {
+		if endpointURL, ok := e.domainEndpoints["*."+domain]; ok {
+			return endpointURL
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return e.endpointURL
+}
+
+// normalizeHost lowercases a host and drops a leading "www.", as
+// storage.NormalizeURL does.
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "www.")
+}
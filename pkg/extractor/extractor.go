@@ -10,23 +10,97 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/irfansharif/shelf/pkg/storage"
 )
 
-// Extractor handles content extraction from URLs.
+// Extractor handles content extraction from URLs. It needs no config, TUI
+// or data directory, only shelf's storage and images packages, so other
+// programs can use it to fetch and convert articles too; see
+// NewWithOptions.
 type Extractor struct {
 	client      *http.Client
-	endpointURL string // Modal endpoint for HTML-to-Markdown conversion
+	timeout     time.Duration // overrides client's timeout; see WithTimeout
+	endpointURL string        // Modal endpoint for HTML-to-Markdown conversion
+	userAgent   string        // sent when fetching pages and images
+	strategy    Strategy      // how Extract converts pages
+	demoteH1    bool          // demote or drop the leading H1; see WithDemoteH1
+	pdfCommand  []string      // converts PDFs to text; see WithPDFCommand
+
+	// domainEndpoints are endpoints for particular sites, keyed by
+	// normalized host or "*.domain"; see WithDomainEndpoints.
+	domainEndpoints map[string]string
+}
+
+// Option configures an Extractor.
+type Option func(*Extractor)
+
+// WithEndpoint sets the Modal endpoint that converts pages with
+// StrategyRemote and StrategyAuto.
+func WithEndpoint(endpointURL string) Option {
+	return func(e *Extractor) {
+		e.endpointURL = endpointURL
+	}
+}
+
+// WithHTTPClient sets the client used for every request: fetching pages and
+// images, and calling the endpoint. The default has a one-minute timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Extractor) {
+		e.client = client
+	}
+}
+
+// WithTimeout limits each request to d. The client passed to WithHTTPClient
+// is left as it is; the extractor uses a copy.
+func WithTimeout(d time.Duration) Option {
+	return func(e *Extractor) {
+		e.timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent sent when fetching pages and images. The
+// default is Safari's, which fewer sites block than Go's.
+func WithUserAgent(ua string) Option {
+	return func(e *Extractor) {
+		e.userAgent = ua
+	}
+}
+
+// WithStrategy sets how Extract converts pages. The default is
+// StrategyRemote.
+func WithStrategy(s Strategy) Option {
+	return func(e *Extractor) {
+		e.strategy = s
+	}
 }
 
 // New creates a new Extractor that uses the given Modal endpoint for
 // HTML-to-Markdown conversion.
-func New(endpointURL string) *Extractor {
-	return &Extractor{
+func New(endpointURL string, opts ...Option) *Extractor {
+	return NewWithOptions(append([]Option{WithEndpoint(endpointURL)}, opts...)...)
+}
+
+// NewWithOptions creates an Extractor configured entirely by opts. With no
+// endpoint (WithEndpoint), use it with WithStrategy(StrategyLocal).
+func NewWithOptions(opts ...Option) *Extractor {
+	e := &Extractor{
 		client: &http.Client{
 			Timeout: 1 * time.Minute,
 		},
-		endpointURL: endpointURL,
+		userAgent:  defaultUserAgent,
+		strategy:   StrategyRemote,
+		pdfCommand: defaultPDFCommand,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.timeout > 0 {
+		client := *e.client
+		client.Timeout = e.timeout
+		e.client = &client
 	}
+	return e
 }
 
 // ImageData holds a downloaded image with its relative path.
@@ -40,6 +114,15 @@ type ExtractResult struct {
 	Title   string      // article title (for slug generation)
 	Content string      // complete index.md content (front matter + markdown)
 	Images  []ImageData // downloaded images with relative paths
+
+	// Warnings describe doubts about the extraction, e.g. images that
+	// couldn't be downloaded. postprocess writes them into the front
+	// matter as a warnings: line.
+	Warnings []string
+
+	// FetchTime is how long fetching and converting the page took.
+	// postprocess writes it into the front matter as a fetch_ms: line.
+	FetchTime time.Duration
 }
 
 // endpointResponse is the structured response from the Modal endpoint.
@@ -54,6 +137,26 @@ type endpointImageData struct {
 	Data string `json:"data"` // base64-encoded
 }
 
+// Check reports whether the endpoint can be reached, giving up after
+// timeout. The endpoint only accepts POSTs, so any response other than
+// 404 (which Modal returns for unknown apps) counts as reachable. With
+// StrategyLocal the endpoint isn't used and Check always succeeds.
+func (e *Extractor) Check(timeout time.Duration) error {
+	if e.strategy == StrategyLocal {
+		return nil
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(e.endpointURL)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("endpoint not found (HTTP 404)")
+	}
+	return nil
+}
+
 // formatEndpointError parses the Modal endpoint's JSON error response and
 // returns a user-friendly error message.
 func formatEndpointError(statusCode int, body []byte) error {
@@ -79,7 +182,10 @@ func formatEndpointError(statusCode int, body []byte) error {
 	return fmt.Errorf("conversion failed (HTTP %d)", statusCode)
 }
 
-// Extract fetches HTML from a URL and converts it to markdown via the Modal endpoint.
+// Extract fetches HTML from a URL and converts it to markdown, via the Modal
+// endpoint or locally depending on the extractor's Strategy. Plain text and
+// PDFs are converted locally whatever the strategy; other content that isn't
+// HTML fails with an *UnsupportedContentTypeError.
 func (e *Extractor) Extract(sourceURL string) (*ExtractResult, error) {
 	parsed, err := url.Parse(sourceURL)
 	if err != nil {
@@ -90,12 +196,55 @@ func (e *Extractor) Extract(sourceURL string) (*ExtractResult, error) {
 		sourceURL = "https://" + sourceURL
 	}
 
+	start := time.Now()
+	var result *ExtractResult
+	switch e.strategy {
+	case StrategyLocal:
+		result, err = e.extractLocal(sourceURL)
+	case StrategyAuto:
+		result, err = e.extractAuto(sourceURL)
+	default:
+		// The endpoint only converts HTML; other documents are fetched and
+		// converted here.
+		if mt := e.headMediaType(sourceURL); mt != "" && !strings.Contains(mt, "html") {
+			result, err = e.extractLocal(sourceURL)
+		} else {
+			result, err = e.extractRemote(sourceURL)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.FetchTime = time.Since(start)
+	return e.postprocess(result), nil
+}
+
+// ExtractArticle is Extract for use outside shelf: it returns the article
+// parsed into a storage.Article, with its metadata filled in from the front
+// matter, and the images its markdown references.
+func (e *Extractor) ExtractArticle(sourceURL string) (*storage.Article, []ImageData, error) {
+	result, err := e.Extract(sourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	article, err := storage.ParseArticle(result.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if article.Meta.Title == "" {
+		article.Meta.Title = result.Title
+	}
+	return article, result.Images, nil
+}
+
+// extractRemote has the Modal endpoint fetch and convert sourceURL.
+func (e *Extractor) extractRemote(sourceURL string) (*ExtractResult, error) {
 	// POST URL to Modal endpoint for conversion.
 	reqBody, err := json.Marshal(map[string]string{"url": sourceURL})
 	if err != nil {
 		return nil, fmt.Errorf("encoding request: %w", err)
 	}
-	resp, err := e.client.Post(e.endpointURL, "application/json", bytes.NewReader(reqBody))
+	resp, err := e.client.Post(e.endpointFor(sourceURL), "application/json", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("converting to markdown: %w", err)
 	}
@@ -129,10 +278,26 @@ func (e *Extractor) Extract(sourceURL string) (*ExtractResult, error) {
 }
 
 // ExtractFromHTML processes pre-fetched HTML via the Modal process endpoint,
-// skipping the HTTP fetch step.
+// skipping the HTTP fetch step. With StrategyLocal the HTML is converted
+// locally instead.
 func (e *Extractor) ExtractFromHTML(sourceURL, rawHTML string) (*ExtractResult, error) {
+	start := time.Now()
+	result, err := e.extractFromHTML(sourceURL, rawHTML)
+	if err != nil {
+		return nil, err
+	}
+	result.FetchTime = time.Since(start)
+	return e.postprocess(result), nil
+}
+
+// extractFromHTML is ExtractFromHTML without the post-processing.
+func (e *Extractor) extractFromHTML(sourceURL, rawHTML string) (*ExtractResult, error) {
+	if e.strategy == StrategyLocal {
+		return e.convertLocal(sourceURL, parseHTML(rawHTML))
+	}
+
 	// Derive process endpoint URL from convert endpoint URL.
-	processURL := strings.Replace(e.endpointURL, "-convert.", "-process.", 1)
+	processURL := strings.Replace(e.endpointFor(sourceURL), "-convert.", "-process.", 1)
 
 	reqBody, err := json.Marshal(map[string]string{"url": sourceURL, "html": rawHTML})
 	if err != nil {
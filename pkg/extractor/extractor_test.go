@@ -0,0 +1,110 @@
+package extractor_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+)
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		// Like the real endpoint, only POST is allowed.
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}))
+	defer srv.Close()
+
+	if err := extractor.New(srv.URL + "/convert").Check(time.Second); err != nil {
+		t.Errorf("reachable endpoint: %v", err)
+	}
+	if err := extractor.New(srv.URL + "/missing").Check(time.Second); err == nil {
+		t.Errorf("unknown endpoint: no error")
+	}
+
+	unreachable := srv.URL + "/convert"
+	srv.Close()
+	if err := extractor.New(unreachable).Check(time.Second); err == nil {
+		t.Errorf("closed server: no error")
+	}
+	if err := extractor.New(unreachable, extractor.WithStrategy(extractor.StrategyLocal)).Check(time.Second); err != nil {
+		t.Errorf("local strategy: %v", err)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/paywall", http.StatusMovedPermanently)
+		case "/paywall":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html>" + strings.Repeat("x", 4096)))
+		}
+	}))
+	defer srv.Close()
+
+	result, err := extractor.New(srv.URL).Probe(srv.URL + "/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Redirects) != 1 || result.Redirects[0] != srv.URL+"/paywall" {
+		t.Errorf("redirects = %v", result.Redirects)
+	}
+	if result.Status != "403 Forbidden" || result.Header.Get("Retry-After") != "120" {
+		t.Errorf("status %q, headers %v", result.Status, result.Header)
+	}
+	if len(result.Body) != 1024 || !strings.HasPrefix(result.Body, "<html>") {
+		t.Errorf("body: %d bytes, want the first 1024", len(result.Body))
+	}
+}
+
+func TestDomainEndpoints(t *testing.T) {
+	// Each endpoint answers with its own path, so the article says which
+	// one converted it.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"title":   "t",
+			"content": "---\ntitle: t\n---\n\n" + r.URL.Path + "\n",
+		})
+	}))
+	defer srv.Close()
+
+	e := extractor.New(srv.URL+"/default", extractor.WithDomainEndpoints(map[string]string{
+		"medium.com":         srv.URL + "/medium",
+		"*.substack.com":     srv.URL + "/substack",
+		"*.eng.substack.com": srv.URL + "/eng",
+		"WWW.Example.org":    srv.URL + "/example",
+	}))
+	for _, tc := range []struct {
+		url, want string
+	}{
+		{"https://medium.com/@a/post", "/medium"},
+		{"https://www.medium.com/@a/post", "/medium"},
+		{"https://blog.medium.com/post", "/default"},
+		{"https://substack.com/home", "/substack"},
+		{"https://someone.substack.com/p/post", "/substack"},
+		{"https://eng.substack.com/p/post", "/eng"},
+		{"https://team.eng.substack.com/p/post", "/eng"},
+		{"https://notsubstack.com/p/post", "/default"},
+		{"https://EXAMPLE.org:8443/a", "/example"},
+		{"https://example.com/a", "/default"},
+	} {
+		result, err := e.ExtractFromHTML(tc.url, "<html><body><p>hi</p></body></html>")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.url, err)
+		}
+		if got := strings.TrimSpace(result.Content[strings.LastIndex(result.Content, "---")+3:]); got != tc.want {
+			t.Errorf("%s: converted by %s, want %s", tc.url, got, tc.want)
+		}
+	}
+}
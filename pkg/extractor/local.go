@@ -0,0 +1,782 @@
+package extractor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/images"
+	"golang.org/x/net/html"
+)
+
+// Strategy selects how Extract converts a page to markdown.
+type Strategy string
+
+const (
+	// StrategyRemote sends the URL to the Modal endpoint, which fetches and
+	// converts the page.
+	StrategyRemote Strategy = "remote"
+	// StrategyLocal fetches the page and converts it locally with simple
+	// readability-style heuristics, without contacting the endpoint.
+	StrategyLocal Strategy = "local"
+	// StrategyAuto fetches the page and converts it locally if it looks
+	// simple and static, otherwise hands the fetched HTML to the endpoint.
+	StrategyAuto Strategy = "auto"
+)
+
+const (
+	// autoMaxScripts is the most <script> tags a page may have for
+	// StrategyAuto to convert it locally; more suggests the content is
+	// rendered client-side.
+	autoMaxScripts = 15
+	// autoMinWords is the least text a local conversion must find for
+	// StrategyAuto to keep it; less suggests the heuristics missed the
+	// article.
+	autoMinWords = 150
+	// autoMinShare is the least fraction of the page's text the content
+	// element must hold for StrategyAuto to keep it; less suggests the
+	// article is split up or buried among other content.
+	autoMinShare = 0.5
+
+	// minPageSize is the fewest bytes, ignoring surrounding whitespace, an
+	// HTML page must have to be worth converting; less is a stub, not an
+	// article.
+	minPageSize = 64
+	maxPageSize = 10 << 20
+
+	// wrapWidth matches the line width of the endpoint's output.
+	wrapWidth = 100
+
+	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15"
+)
+
+// extractLocal fetches sourceURL and converts it without the endpoint.
+func (e *Extractor) extractLocal(sourceURL string) (*ExtractResult, error) {
+	pg, err := e.fetchPage(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if !pg.isHTML() {
+		return e.convertDocument(sourceURL, pg)
+	}
+	return e.convertLocal(sourceURL, parseHTML(pg.body))
+}
+
+// extractAuto converts simple pages locally and sends the rest to the
+// endpoint. The page is fetched once either way; if it can't be fetched
+// here (e.g. the site blocks the request) the endpoint fetches it instead,
+// unless it came back empty.
+func (e *Extractor) extractAuto(sourceURL string) (*ExtractResult, error) {
+	pg, err := e.fetchPage(sourceURL)
+	if errors.Is(err, ErrEmptyPage) {
+		// The endpoint would likely be served the same empty page.
+		return nil, err
+	}
+	if err != nil {
+		return e.extractRemote(sourceURL)
+	}
+	if !pg.isHTML() {
+		return e.convertDocument(sourceURL, pg)
+	}
+	if strings.Count(strings.ToLower(pg.body), "<script") <= autoMaxScripts {
+		doc := parseHTML(pg.body)
+		if isSimplePage(doc) {
+			if result, err := e.convertLocal(sourceURL, doc); err == nil {
+				return result, nil
+			}
+		}
+	}
+	return e.extractFromHTML(sourceURL, pg.body)
+}
+
+// isSimplePage reports whether the local heuristics can be trusted with a
+// page: they find a substantial article that makes up most of its text.
+func isSimplePage(doc *node) bool {
+	content := findContent(doc)
+	if content == nil {
+		return false
+	}
+	words := len(strings.Fields(textOf(content)))
+	total := len(strings.Fields(textOf(doc)))
+	return words >= autoMinWords && float64(words) >= autoMinShare*float64(total)
+}
+
+// page is a fetched URL: its body and the media type it was served as.
+type page struct {
+	body      string
+	mediaType string // e.g. "text/html"; empty if the server didn't say
+}
+
+// isHTML reports whether the page is (or may be) an HTML page.
+func (p *page) isHTML() bool {
+	return p.mediaType == "" || strings.Contains(p.mediaType, "html")
+}
+
+// ErrEmptyPage is returned by Extract when a page is served successfully
+// but with an empty or trivially small body, as some sites do for requests
+// that don't come from a browser.
+var ErrEmptyPage = errors.New("page came back empty; try refetching with Safari (R)")
+
+// fetchPage downloads the page at sourceURL. An empty page, or an HTML one
+// smaller than minPageSize, fails with ErrEmptyPage.
+func (e *Extractor) fetchPage(sourceURL string) (*page, error) {
+	req, err := http.NewRequest(http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,application/pdf;q=0.9,*/*;q=0.8")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("site blocked the request (HTTP 403); try refetching with Safari (R)")
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("page not found (HTTP 404)")
+	default:
+		return nil, fmt.Errorf("fetching page: HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading page: %w", err)
+	}
+	pg := &page{body: string(body), mediaType: mediaType(resp.Header.Get("Content-Type"))}
+	if size := len(strings.TrimSpace(pg.body)); size == 0 || pg.isHTML() && size < minPageSize {
+		return nil, ErrEmptyPage
+	}
+	return pg, nil
+}
+
+// convertLocal converts a parsed page to an index.md, downloading the images
+// it references. Images that fail to download keep their remote URL.
+func (e *Extractor) convertLocal(sourceURL string, doc *node) (*ExtractResult, error) {
+	base, err := url.Parse(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	content := findContent(doc)
+	if content == nil {
+		return nil, fmt.Errorf("no article content found")
+	}
+
+	title := pageTitle(doc, content)
+	if title == "" {
+		title = base.Host + base.Path
+	}
+	md := &markdownWriter{base: base}
+	blocks := md.blocks(content)
+	// The title is written as the heading below; drop a leading copy.
+	if len(blocks) > 0 && strings.TrimSpace(strings.TrimPrefix(blocks[0], "# ")) == title {
+		blocks = blocks[1:]
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no article content found")
+	}
+	body := strings.Join(blocks, "\n\n")
+
+	downloaded := images.DownloadAndRewriteAs(e.client, e.userAgent, body, "images", nil)
+	body = downloaded.Markdown
+	var imgs []ImageData
+	for _, img := range downloaded.Images {
+		imgs = append(imgs, ImageData{Path: img.Path, Data: img.Data})
+	}
+
+	var warnings []string
+	tooLarge := 0
+	for _, f := range downloaded.Failed {
+		if errors.Is(f.Err, images.ErrTooLarge) {
+			tooLarge++
+		}
+	}
+	if failed := len(downloaded.Failed) - tooLarge; failed > 0 {
+		warnings = append(warnings, imageWarning(failed, "downloaded"))
+	}
+	if tooLarge > 0 {
+		warnings = append(warnings, imageWarning(tooLarge, "saved: "+images.ErrTooLarge.Error()))
+	}
+	if contentUncertain(doc, content) {
+		warnings = append(warnings, "most of the page's text was left out; the article may be incomplete")
+	}
+	return &ExtractResult{
+		Title:    title,
+		Content:  frontMatter(title, pageAuthor(doc), sourceURL) + fmt.Sprintf("# %s\n\n%s\n", title, body),
+		Images:   imgs,
+		Warnings: warnings,
+	}, nil
+}
+
+// contentUncertain reports whether the content findContent picked holds
+// less than half of the page's paragraph text, a sign that part of the
+// article may have been stripped along with the page's boilerplate.
+func contentUncertain(doc, content *node) bool {
+	return paragraphText(content)*2 < paragraphText(doc)
+}
+
+// HTMLToMarkdown converts an HTML page that isn't fetched from a URL, such
+// as a file on disk, with the same heuristics as StrategyLocal. It returns
+// the page's title, or "" if it has none, and the article's markdown
+// without front matter. Images are left referenced as written, e.g.
+// relative to the file, and links are kept only if they're absolute.
+func HTMLToMarkdown(src string) (title, markdown string, err error) {
+	doc := parseHTML(src)
+	content := findContent(doc)
+	if content == nil {
+		return "", "", fmt.Errorf("no article content found")
+	}
+	title = pageTitle(doc, content)
+	md := &markdownWriter{base: &url.URL{}, keepImages: true}
+	blocks := md.blocks(content)
+	if len(blocks) == 0 {
+		return "", "", fmt.Errorf("no article content found")
+	}
+	return title, strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+// frontMatter returns the front matter that starts a locally converted
+// index.md, in the same form as the endpoint's.
+func frontMatter(title, author, sourceURL string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	fmt.Fprintf(&sb, "title: %s\n", yamlValue(title))
+	fmt.Fprintf(&sb, "author: %s\n", yamlValue(author))
+	fmt.Fprintf(&sb, "source: %s\n", sourceURL)
+	fmt.Fprintf(&sb, "saved: %s\n", time.Now().UTC().Format(time.RFC3339))
+	sb.WriteString("tags:\nprogress:\n---\n\n")
+	return sb.String()
+}
+
+// yamlSpecial matches characters that need a front matter value quoted.
+var yamlSpecial = regexp.MustCompile("[:#{}\\[\\]&*!|>'\"%@`]")
+
+// yamlValue formats s as a front matter value the way the endpoint does:
+// curly quotes are straightened, and values with special characters are
+// quoted in the form the storage package unescapes.
+func yamlValue(s string) string {
+	s = strings.NewReplacer("\u2018", "'", "\u2019", "'", "\u201c", `"`, "\u201d", `"`).Replace(s)
+	if yamlSpecial.MatchString(s) || strings.HasPrefix(s, "-") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
+
+// node is an element or text node of a parsed HTML document.
+type node struct {
+	tag      string // lowercase element name; empty for text
+	attrs    map[string]string
+	text     string
+	parent   *node
+	children []*node
+}
+
+// ignoredElements are dropped while parsing: their contents aren't article
+// text.
+var ignoredElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// parseHTML builds a tree from HTML with the HTML5 parsing algorithm in
+// golang.org/x/net/html, which closes implied elements such as an open <p>
+// or <li> the way browsers do. Comments, the doctype and ignoredElements
+// are left out.
+func parseHTML(src string) *node {
+	root := &node{tag: "#document"}
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return root // only reading src can fail
+	}
+	var build func(parent *node, hn *html.Node)
+	build = func(parent *node, hn *html.Node) {
+		for c := hn.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				parent.children = append(parent.children, &node{text: c.Data, parent: parent})
+			case html.ElementNode:
+				if ignoredElements[c.Data] {
+					continue
+				}
+				n := &node{tag: c.Data, attrs: make(map[string]string, len(c.Attr)), parent: parent}
+				for _, a := range c.Attr {
+					n.attrs[a.Key] = a.Val
+				}
+				parent.children = append(parent.children, n)
+				build(n, c)
+			}
+		}
+	}
+	build(root, doc)
+	return root
+}
+
+// walk calls fn for n and each of its descendants, depth first, skipping
+// the children of any node for which fn returns false.
+func walk(n *node, fn func(*node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.children {
+		walk(c, fn)
+	}
+}
+
+// findAll returns the elements under n with the given tag.
+func findAll(n *node, tag string) []*node {
+	var found []*node
+	walk(n, func(c *node) bool {
+		if c.tag == tag {
+			found = append(found, c)
+		}
+		return true
+	})
+	return found
+}
+
+// textOf returns the text under n with whitespace collapsed.
+func textOf(n *node) string {
+	var sb strings.Builder
+	walk(n, func(c *node) bool {
+		if c.tag == "" {
+			sb.WriteString(c.text)
+			sb.WriteByte(' ')
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// paragraphText returns the length of text in the <p> elements under n.
+func paragraphText(n *node) int {
+	total := 0
+	for _, p := range findAll(n, "p") {
+		total += len(textOf(p))
+	}
+	return total
+}
+
+// findContent returns the element most likely to hold the article: the
+// <article> (or failing that, <main>) with the most paragraph text, or else
+// the element scoring highest for the paragraphs it contains. Paragraphs
+// are <p> elements or, for pages built from <div>s, any block holding only
+// text; each counts fully towards its parent and half towards its
+// grandparent, so an article split into a few sibling sections is kept
+// whole.
+func findContent(doc *node) *node {
+	for _, tag := range []string{"article", "main"} {
+		var best *node
+		bestScore := 0
+		for _, n := range findAll(doc, tag) {
+			if score := paragraphText(n); score > bestScore {
+				best, bestScore = n, score
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+
+	scores := make(map[*node]float64)
+	walk(doc, func(n *node) bool {
+		if skippedElements[n.tag] {
+			return false
+		}
+		if n.tag != "p" && (!blockElements[n.tag] || hasBlockChildren(n)) {
+			return true
+		}
+		text := len(textOf(n))
+		if text < 25 || n.parent == nil {
+			return n.tag != "p"
+		}
+		scores[n.parent] += float64(text)
+		if gp := n.parent.parent; gp != nil {
+			scores[gp] += float64(text) / 2
+		}
+		return false
+	})
+	var best *node
+	bestScore := 0.0
+	walk(doc, func(n *node) bool {
+		if scores[n] > bestScore {
+			best, bestScore = n, scores[n]
+		}
+		return true
+	})
+	return best
+}
+
+// hasBlockChildren reports whether any element under n is a block.
+func hasBlockChildren(n *node) bool {
+	found := false
+	walk(n, func(c *node) bool {
+		if c != n && blockElements[c.tag] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// meta returns the content of the first <meta> whose name or property is
+// one of keys, in order of preference.
+func meta(doc *node, keys ...string) string {
+	metas := findAll(doc, "meta")
+	for _, key := range keys {
+		for _, m := range metas {
+			if m.attrs["name"] == key || m.attrs["property"] == key {
+				if v := strings.Join(strings.Fields(m.attrs["content"]), " "); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// pageTitle returns the article's title: the Open Graph title, the content's
+// first heading, or the document title.
+func pageTitle(doc, content *node) string {
+	if t := meta(doc, "og:title", "twitter:title"); t != "" {
+		return t
+	}
+	if h1 := findAll(content, "h1"); len(h1) > 0 {
+		if t := textOf(h1[0]); t != "" {
+			return t
+		}
+	}
+	if t := findAll(doc, "title"); len(t) > 0 {
+		return textOf(t[0])
+	}
+	return ""
+}
+
+// pageAuthor returns the author named in the page's metadata, if any.
+func pageAuthor(doc *node) string {
+	author := meta(doc, "author", "article:author", "twitter:creator")
+	if strings.Contains(author, "://") {
+		return "" // a profile link rather than a name
+	}
+	return author
+}
+
+// skippedElements hold page chrome or controls rather than article text.
+var skippedElements = map[string]bool{
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "input": true, "select": true, "textarea": true,
+	"iframe": true, "canvas": true, "video": true, "audio": true,
+	"dialog": true, "menu": true, "head": true, "title": true, "meta": true,
+	"link": true, "object": true, "embed": true,
+}
+
+// blockElements start a new markdown block.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "blockquote": true, "pre": true,
+	"hr": true, "figure": true, "figcaption": true, "table": true,
+	"dl": true, "dt": true, "dd": true, "body": true, "html": true,
+	"details": true, "summary": true, "picture": true,
+}
+
+// markdownWriter converts a parsed HTML tree to markdown blocks.
+type markdownWriter struct {
+	base       *url.URL
+	keepImages bool // leave image sources as written; see HTMLToMarkdown
+}
+
+// blocks returns the markdown blocks for n's children.
+func (w *markdownWriter) blocks(n *node) []string {
+	var out []string
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(collapseSpace(inline.String())); text != "" {
+			out = append(out, wrap(text, wrapWidth, ""))
+		}
+		inline.Reset()
+	}
+	for _, c := range n.children {
+		if c.tag == "" || (!blockElements[c.tag] && !skippedElements[c.tag] && c.tag != "img") {
+			inline.WriteString(w.inline(c))
+			continue
+		}
+		flush()
+		out = append(out, w.block(c)...)
+	}
+	flush()
+	return out
+}
+
+// block returns the markdown blocks for a block-level element.
+func (w *markdownWriter) block(n *node) []string {
+	if skippedElements[n.tag] {
+		return nil
+	}
+	switch n.tag {
+	case "p", "dt", "summary":
+		if text := strings.TrimSpace(collapseSpace(w.inline(n))); text != "" {
+			return []string{wrap(text, wrapWidth, "")}
+		}
+		return nil
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if text := strings.TrimSpace(collapseSpace(strings.ReplaceAll(w.inline(n), "\n", " "))); text != "" {
+			return []string{strings.Repeat("#", int(n.tag[1]-'0')) + " " + text}
+		}
+		return nil
+	case "figcaption":
+		if text := strings.TrimSpace(collapseSpace(w.inline(n))); text != "" {
+			return []string{wrap("*"+text+"*", wrapWidth, "")}
+		}
+		return nil
+	case "img":
+		if img := w.image(n); img != "" {
+			return []string{img}
+		}
+		return nil
+	case "hr":
+		return []string{"***"}
+	case "pre":
+		code := strings.Trim(rawText(n), "\n")
+		if code == "" {
+			return nil
+		}
+		return []string{"```\n" + code + "\n```"}
+	case "blockquote":
+		var lines []string
+		for i, b := range w.blocks(n) {
+			if i > 0 {
+				lines = append(lines, ">")
+			}
+			for _, line := range strings.Split(b, "\n") {
+				lines = append(lines, strings.TrimRight("> "+line, " "))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return []string{strings.Join(lines, "\n")}
+	case "ul", "ol":
+		var items []string
+		for _, li := range n.children {
+			if li.tag != "li" {
+				continue
+			}
+			marker := "- "
+			if n.tag == "ol" {
+				marker = fmt.Sprintf("%d. ", len(items)+1)
+			}
+			indent := strings.Repeat(" ", len(marker))
+			var lines []string
+			for i, b := range w.blocks(li) {
+				for j, line := range strings.Split(b, "\n") {
+					switch {
+					case i == 0 && j == 0:
+						lines = append(lines, marker+line)
+					case line == "":
+						lines = append(lines, "")
+					default:
+						lines = append(lines, indent+line)
+					}
+				}
+			}
+			if len(lines) > 0 {
+				items = append(items, strings.Join(lines, "\n"))
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return []string{strings.Join(items, "\n")}
+	case "table":
+		return w.table(n)
+	default:
+		return w.blocks(n)
+	}
+}
+
+// table renders a table's rows as a markdown table, treating the first row
+// as the header.
+func (w *markdownWriter) table(n *node) []string {
+	var rows [][]string
+	walk(n, func(c *node) bool {
+		if c.tag != "tr" {
+			return true
+		}
+		var cells []string
+		for _, cell := range c.children {
+			if cell.tag == "td" || cell.tag == "th" {
+				text := strings.TrimSpace(collapseSpace(strings.ReplaceAll(w.inline(cell), "\n", " ")))
+				cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+		return false
+	})
+	if len(rows) == 0 {
+		return nil
+	}
+	cols := 0
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	var lines []string
+	for i, r := range rows {
+		for len(r) < cols {
+			r = append(r, "")
+		}
+		lines = append(lines, "| "+strings.Join(r, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", cols))
+		}
+	}
+	return []string{strings.Join(lines, "\n")}
+}
+
+// inline returns the markdown for n as inline text. Hard line breaks are
+// kept as newlines; other whitespace is left for the caller to collapse.
+func (w *markdownWriter) inline(n *node) string {
+	if n.tag == "" {
+		return strings.ReplaceAll(n.text, "\n", " ")
+	}
+	if skippedElements[n.tag] {
+		return ""
+	}
+	switch n.tag {
+	case "br":
+		return "\n"
+	case "img":
+		return w.image(n)
+	case "code", "kbd", "samp":
+		if code := strings.Join(strings.Fields(rawText(n)), " "); code != "" {
+			return "`" + code + "`"
+		}
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, c := range n.children {
+		sb.WriteString(w.inline(c))
+	}
+	inner := sb.String()
+	switch n.tag {
+	case "a":
+		href := w.resolve(n.attrs["href"])
+		if href == "" || strings.HasPrefix(n.attrs["href"], "#") || strings.TrimSpace(inner) == "" {
+			return inner
+		}
+		return wrapMarkers(inner, "[", "]("+href+")")
+	case "strong", "b":
+		return wrapMarkers(inner, "**", "**")
+	case "em", "i", "cite":
+		return wrapMarkers(inner, "*", "*")
+	case "del", "s", "strike":
+		return wrapMarkers(inner, "~~", "~~")
+	default:
+		return inner
+	}
+}
+
+// wrapMarkers surrounds the text of s with open and close, keeping any
+// surrounding whitespace outside them.
+func wrapMarkers(s, open, close string) string {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return s
+	}
+	start := strings.Index(s, text)
+	return s[:start] + open + text + close + s[start+len(text):]
+}
+
+// image returns the markdown for an <img>, referencing its absolute URL
+// for convertLocal to download.
+func (w *markdownWriter) image(n *node) string {
+	src := n.attrs["src"]
+	for _, lazy := range []string{"data-src", "data-original", "data-lazy-src"} {
+		if v := n.attrs[lazy]; v != "" && (src == "" || strings.HasPrefix(src, "data:")) {
+			src = v
+		}
+	}
+	alt := strings.Join(strings.Fields(n.attrs["alt"]), " ")
+	alt = strings.NewReplacer("[", "", "]", "").Replace(alt)
+	if w.keepImages {
+		if src = strings.TrimSpace(src); src == "" || strings.HasPrefix(src, "data:") {
+			return ""
+		}
+		return fmt.Sprintf("![%s](%s)", alt, src)
+	}
+	abs := w.resolve(src)
+	if abs == "" || strings.HasPrefix(src, "data:") {
+		return ""
+	}
+
+	return fmt.Sprintf("![%s](%s)", alt, abs)
+}
+
+// resolve returns ref as an absolute http(s) URL relative to the page, or
+// "" if it isn't one.
+func (w *markdownWriter) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := w.base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// rawText returns the text under n verbatim, for preformatted content.
+func rawText(n *node) string {
+	var sb strings.Builder
+	walk(n, func(c *node) bool {
+		if c.tag == "br" {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(c.text)
+		return true
+	})
+	return sb.String()
+}
+
+// collapseSpace collapses runs of whitespace within each line into a single
+// space, trimming the ends of lines.
+func collapseSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap breaks each line of s at word boundaries so that lines are at most
+// width columns wide where possible, prefixing continuation lines with
+// indent.
+func wrap(s string, width int, indent string) string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		words := strings.Fields(line)
+		cur := ""
+		for _, word := range words {
+			switch {
+			case cur == "":
+				cur = word
+			case len(cur)+1+len(word) > width:
+				out = append(out, cur)
+				cur = indent + word
+			default:
+				cur += " " + word
+			}
+		}
+		out = append(out, cur)
+	}
+	return strings.Join(out, "\n")
+}
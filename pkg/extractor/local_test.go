@@ -0,0 +1,391 @@
+package extractor_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+)
+
+// localTestServer serves a simple static article, a script-heavy page, a
+// few documents that aren't HTML, and an endpoint that records what it was
+// sent.
+func localTestServer(t *testing.T, endpointCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	filler := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html><head>
+<title>Static Post | Example</title>
+<meta property="og:title" content="Static Post">
+<meta name="author" content="Jane Doe">
+<script>if (a < b && c) { render("<p>not content</p>") }</script>
+</head><body>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<article>
+<h1>Static Post</h1>
+<p>First paragraph with <em>emphasis</em>, <strong>bold</strong> and a
+<a href="/other">relative link</a>. %s</p>
+<p>Second paragraph &amp; an entity, a&nbsp;nbsp, and a stray 1 < 2.<br>After a break.</p>
+<h2>Details</h2>
+<ul><li>one</li><li>two <code>x := 1</code></li></ul>
+<img src="/img.png" alt="a diagram" data-src="ignored">
+<blockquote><p>Quoted.</p></blockquote>
+<pre><code>func main() {
+	fmt.Println("hi")
+}</code></pre>
+</article>
+<footer>Copyright</footer>
+</body></html>`, filler)
+	})
+	mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><div id="root"></div>`+strings.Repeat(`<script src="/chunk.js"></script>`, 30)+`</body></html>`)
+	})
+	mux.HandleFunc("/img.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\x89PNG\r\n\x1a\n fake"))
+	})
+	mux.HandleFunc("/notes/release-notes.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "Release notes\r\n\r\n* Fixed a bug.\r\n")
+	})
+	mux.HandleFunc("/paper.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, "%PDF-1.4\n1 0 obj\n<< /Title (On \\(Fake\\) PDFs) >>\nendobj\nTEXT: Abstract.\nTEXT: We study fakes.\n%%EOF\n")
+	})
+	mux.HandleFunc("/archive.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write([]byte("PK\x03\x04"))
+	})
+	mux.HandleFunc("/convert", func(w http.ResponseWriter, r *http.Request) {
+		endpointCalls.Add(1)
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req["html"] == "" {
+			http.Error(w, `{"error": "expected fetched html"}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"title":   "From the model",
+			"content": "---\ntitle: From the model\n---\n\nbody\n",
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLocalStrategy(t *testing.T) {
+	var calls atomic.Int32
+	srv := localTestServer(t, &calls)
+	ext := extractor.New(srv.URL+"/convert", extractor.WithStrategy(extractor.StrategyLocal))
+
+	result, err := ext.Extract(srv.URL + "/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("local extraction called the endpoint")
+	}
+	if result.Title != "Static Post" {
+		t.Errorf("title = %q", result.Title)
+	}
+	for _, want := range []string{
+		"title: Static Post\nauthor: Jane Doe\nsource: " + srv.URL + "/post\n",
+		"\n---\n\n# Static Post\n\nFirst paragraph with *emphasis*, **bold** and a [relative link](" + srv.URL + "/other).",
+		"Second paragraph & an entity, a nbsp, and a stray 1 < 2.\nAfter a break.",
+		"\n## Details\n\n- one\n- two `x := 1`\n",
+		"\n![a diagram](images/img.png)\n",
+		"\n> Quoted.\n",
+		"```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```",
+	} {
+		if !strings.Contains(result.Content, want) {
+			t.Errorf("content missing %q:\n%s", want, result.Content)
+		}
+	}
+	for _, unwanted := range []string{"Home", "Copyright", "not content", "# Static Post\n\n# Static Post"} {
+		if strings.Contains(result.Content, unwanted) {
+			t.Errorf("content contains %q:\n%s", unwanted, result.Content)
+		}
+	}
+	for _, line := range strings.Split(result.Content, "\n") {
+		if len(line) > 100 && !strings.Contains(line, "](") {
+			t.Errorf("line not wrapped: %q", line)
+		}
+	}
+	if len(result.Images) != 1 || result.Images[0].Path != "images/img.png" || string(result.Images[0].Data) != "\x89PNG\r\n\x1a\n fake" {
+		t.Errorf("images = %+v", result.Images)
+	}
+}
+
+func TestLocalImageTooLarge(t *testing.T) {
+	filler := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Big</title></head><body><article><p>%s</p><img src="/huge.png" alt="huge"></article></body></html>`, filler)
+	})
+	mux.HandleFunc("/huge.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		w.Write(make([]byte, 10<<20))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	ext := extractor.New(srv.URL+"/convert", extractor.WithStrategy(extractor.StrategyLocal))
+
+	// An image over the limit is left remote, not saved cut short.
+	result, err := ext.Extract(srv.URL + "/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Images) != 0 {
+		t.Errorf("images = %d, want the oversized one skipped", len(result.Images))
+	}
+	if !strings.Contains(result.Content, "![huge]("+srv.URL+"/huge.png)") {
+		t.Errorf("image not left remote:\n%s", result.Content)
+	}
+	if want := "1 image couldn't be saved: larger than 10 MB"; len(result.Warnings) == 0 || result.Warnings[0] != want {
+		t.Errorf("warnings = %q, want %q", result.Warnings, want)
+	}
+}
+
+func TestAutoStrategy(t *testing.T) {
+	var calls atomic.Int32
+	srv := localTestServer(t, &calls)
+	ext := extractor.New(srv.URL+"/convert", extractor.WithStrategy(extractor.StrategyAuto))
+
+	// A static article is converted locally.
+	result, err := ext.Extract(srv.URL + "/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Title != "Static Post" || calls.Load() != 0 {
+		t.Errorf("static page: title %q, %d endpoint calls; want local conversion", result.Title, calls.Load())
+	}
+
+	// A client-rendered page goes to the endpoint, with the HTML already
+	// fetched.
+	result, err = ext.Extract(srv.URL + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Title != "From the model" || calls.Load() != 1 {
+		t.Errorf("script-heavy page: title %q, %d endpoint calls; want the endpoint's result", result.Title, calls.Load())
+	}
+}
+
+func TestEmptyPage(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+	mux.HandleFunc("/stub", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "<html><body></body></html>\n")
+	})
+	mux.HandleFunc("/short.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "Short but real.\n")
+	})
+	mux.HandleFunc("/convert", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "unexpected", http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	for _, strategy := range []extractor.Strategy{extractor.StrategyLocal, extractor.StrategyAuto} {
+		ext := extractor.New(srv.URL+"/convert", extractor.WithStrategy(strategy))
+		for _, path := range []string{"/empty", "/stub"} {
+			if _, err := ext.Extract(srv.URL + path); !errors.Is(err, extractor.ErrEmptyPage) {
+				t.Errorf("%s %s: err = %v, want ErrEmptyPage", strategy, path, err)
+			}
+		}
+		// Small documents that aren't HTML are fine.
+		if _, err := ext.Extract(srv.URL + "/short.txt"); err != nil {
+			t.Errorf("%s short text file: %v", strategy, err)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Errorf("%d endpoint calls for empty pages, want none", calls.Load())
+	}
+}
+
+func TestExtractionWarnings(t *testing.T) {
+	filler := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/patchy", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Patchy</title></head><body>
+<article><p>A short article. %s</p><img src="/gone.png"></article>
+<div><p>%s</p><p>%s</p><p>%s</p></div>
+</body></html>`, filler, filler, filler, filler)
+	})
+	mux.HandleFunc("/convert", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"title":   "Remote",
+			"content": "---\ntitle: Remote\n---\n\n![a](images/a.png) ![b](images/b.png) ![c](https://example.com/c.png)\n",
+			"images":  []map[string]string{{"path": "images/a.png", "data": ""}},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	local := extractor.New(srv.URL+"/convert", extractor.WithStrategy(extractor.StrategyLocal))
+	result, err := local.Extract(srv.URL + "/patchy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1 image couldn't be downloaded", "most of the page's text was left out; the article may be incomplete"}
+	if fmt.Sprint(result.Warnings) != fmt.Sprint(want) {
+		t.Errorf("local warnings = %q, want %q", result.Warnings, want)
+	}
+	if !strings.Contains(result.Content, "\nwarnings: \"1 image couldn't be downloaded; most of") {
+		t.Errorf("warnings missing from front matter:\n%s", result.Content)
+	}
+	if result.FetchTime <= 0 || !strings.Contains(result.Content, "\nfetch_ms: ") {
+		t.Errorf("fetch time %v missing from front matter:\n%s", result.FetchTime, result.Content)
+	}
+
+	remote := extractor.New(srv.URL + "/convert")
+	result, err = remote.Extract(srv.URL + "/patchy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Content, "---\ntitle: Remote\nwarnings: \"1 image couldn't be placed\"\n") {
+		t.Errorf("remote content:\n%s", result.Content)
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	filler := strings.Repeat("Words to make this the article. ", 10)
+	title, md, err := extractor.HTMLToMarkdown(`<html><head><title>Saved Page</title></head><body>
+<nav><a href="/">Home</a></nav>
+<article><h1>Saved Page</h1>
+<p>` + filler + `<a href="other.html">relative</a> and <a href="https://example.com/">absolute</a>.</p>
+<img src="figs/plot.png" alt="plot"><img src="data:image/png;base64,AAAA">
+</article></body></html>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Saved Page" {
+		t.Errorf("title = %q", title)
+	}
+	for _, want := range []string{"# Saved Page\n", "relative and [absolute](https://example.com/).", "![plot](figs/plot.png)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Home") || strings.Contains(md, "data:") || strings.HasPrefix(md, "---") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+}
+
+// TestHTMLToMarkdownImpliedEnds checks that elements closed only by the
+// next one, as browsers allow for <p> and <li>, come out as siblings.
+func TestHTMLToMarkdownImpliedEnds(t *testing.T) {
+	filler := strings.Repeat("Words to make this the article. ", 10)
+	_, md, err := extractor.HTMLToMarkdown(`<article><h1>Open Ends</h1>
+<p>` + filler + `
+<p>A second paragraph.
+<ul><li>one<li>two</ul>
+</article>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n\nA second paragraph.\n\n- one\n- two\n"
+	if !strings.HasSuffix(md, want) {
+		t.Errorf("markdown doesn't end with %q:\n%s", want, md)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	var calls atomic.Int32
+	srv := localTestServer(t, &calls)
+	// A stand-in for pdftotext that prints the fixture's TEXT: lines.
+	ext := extractor.New(srv.URL+"/convert", extractor.WithPDFCommand("sed", "-n", "s/^TEXT: //p", "{}"))
+
+	result, err := ext.Extract(srv.URL + "/notes/release-notes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Title != "release notes" || !strings.HasSuffix(result.Content, "\n---\n\nRelease notes\n\n* Fixed a bug.\n") {
+		t.Errorf("plain text: title %q, content:\n%s", result.Title, result.Content)
+	}
+
+	result, err = ext.Extract(srv.URL + "/paper.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Title != "On (Fake) PDFs" || !strings.HasSuffix(result.Content, "\n---\n\nAbstract.\nWe study fakes.\n") ||
+		!strings.Contains(result.Content, "title: On (Fake) PDFs\n") {
+		t.Errorf("PDF: title %q, content:\n%s", result.Title, result.Content)
+	}
+
+	// Without "{}" the PDF is piped to the command.
+	stdin := extractor.New(srv.URL+"/convert", extractor.WithPDFCommand("sed", "-n", "s/^TEXT: //p"))
+	if result, err = stdin.Extract(srv.URL + "/paper.pdf"); err != nil || !strings.HasSuffix(result.Content, "\nWe study fakes.\n") {
+		t.Errorf("PDF on stdin: err %v, result %+v", err, result)
+	}
+
+	_, err = ext.Extract(srv.URL + "/archive.zip")
+	var unsupported *extractor.UnsupportedContentTypeError
+	if !errors.As(err, &unsupported) || unsupported.ContentType != "application/zip" {
+		t.Errorf("zip: err = %v, want an UnsupportedContentTypeError", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("documents were sent to the endpoint %d times", calls.Load())
+	}
+}
+
+func TestExtractArticle(t *testing.T) {
+	var calls atomic.Int32
+	srv := localTestServer(t, &calls)
+	var agents []string
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	ext := extractor.NewWithOptions(
+		extractor.WithStrategy(extractor.StrategyLocal),
+		extractor.WithHTTPClient(client),
+		extractor.WithUserAgent("reader/1.0"),
+		extractor.WithTimeout(time.Minute),
+	)
+
+	article, images, err := ext.ExtractArticle(srv.URL + "/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := article.Meta
+	if meta.Title != "Static Post" || meta.Author != "Jane Doe" || meta.SourceURL != srv.URL+"/post" ||
+		meta.SourceDomain != strings.TrimPrefix(srv.URL, "http://") || meta.SavedAt.IsZero() {
+		t.Errorf("meta = %+v", meta)
+	}
+	if !strings.HasPrefix(article.Content, "# Static Post\n") {
+		t.Errorf("content doesn't start with the title:\n%s", article.Content)
+	}
+	if len(images) != 1 || images[0].Path != "images/img.png" {
+		t.Errorf("images = %+v", images)
+	}
+	if len(agents) != 2 || agents[0] != "reader/1.0" || agents[1] != "reader/1.0" {
+		t.Errorf("user agents = %q, want the page and image fetched as reader/1.0", agents)
+	}
+	if client.Timeout != 0 {
+		t.Errorf("WithTimeout changed the caller's client")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
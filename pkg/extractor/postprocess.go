@@ -0,0 +1,143 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WithDemoteH1 demotes an article's leading H1 to H2, or drops it if it
+// just repeats the title. The title already lives in the front matter, so
+// without this many articles render with two titles.
+func WithDemoteH1() Option {
+	return func(e *Extractor) {
+		e.demoteH1 = true
+	}
+}
+
+// postprocess applies the extractor's optional markdown clean-ups to a
+// converted article. It runs after conversion, whichever strategy did it.
+func (e *Extractor) postprocess(result *ExtractResult) *ExtractResult {
+	if e.demoteH1 {
+		result.Content = demoteLeadingH1(result.Content, result.Title)
+	}
+	if n := missingImages(result); n > 0 {
+		result.Warnings = append(result.Warnings, imageWarning(n, "placed"))
+	}
+	result.Content = addWarnings(result.Content, result.Warnings)
+	if result.FetchTime > 0 {
+		result.Content = addField(result.Content, "fetch_ms", strconv.FormatInt(max(result.FetchTime.Milliseconds(), 1), 10))
+	}
+	return result
+}
+
+// imageRefRe matches a markdown image reference, capturing its path.
+var imageRefRe = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)`)
+
+// missingImages counts the local images an article's markdown references
+// that weren't extracted with it, which would show as broken images.
+func missingImages(result *ExtractResult) int {
+	have := make(map[string]bool, len(result.Images))
+	for _, img := range result.Images {
+		have[img.Path] = true
+	}
+	missing := make(map[string]bool)
+	for _, m := range imageRefRe.FindAllStringSubmatch(result.Content, -1) {
+		ref := m[1]
+		if strings.Contains(ref, ":") || strings.HasPrefix(ref, "/") || have[ref] {
+			continue
+		}
+		missing[ref] = true
+	}
+	return len(missing)
+}
+
+// imageWarning describes n images that couldn't be downloaded, placed, etc.
+func imageWarning(n int, what string) string {
+	if n == 1 {
+		return "1 image couldn't be " + what
+	}
+	return fmt.Sprintf("%d images couldn't be %s", n, what)
+}
+
+// addWarnings adds a warnings: line listing warnings to the end of
+// content's front matter. Content without front matter is left as is.
+func addWarnings(content string, warnings []string) string {
+	if len(warnings) == 0 {
+		return content
+	}
+	return addField(content, "warnings", yamlValue(strings.Join(warnings, "; ")))
+}
+
+// addField adds a "key: value" line to the end of content's front matter.
+// Content without front matter is left as is.
+func addField(content, key, value string) string {
+	frontMatter, body := splitFrontMatter(content)
+	if frontMatter == "" {
+		return content
+	}
+	header := strings.TrimSuffix(frontMatter, "---\n")
+	return header + key + ": " + value + "\n---\n" + body
+}
+
+// demoteLeadingH1 rewrites the first line of an article's body if it is an
+// H1: it is dropped if it matches title, and demoted to H2 otherwise. Any
+// front matter is left untouched, as are H1s further down.
+func demoteLeadingH1(content, title string) string {
+	frontMatter, body := splitFrontMatter(content)
+	lines := strings.Split(body, "\n")
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first == len(lines) {
+		return content
+	}
+	heading, ok := strings.CutPrefix(lines[first], "# ")
+	if !ok {
+		return content
+	}
+	if sameTitle(heading, title) {
+		// Drop the heading along with the blank lines after it.
+		rest := first + 1
+		for rest < len(lines) && strings.TrimSpace(lines[rest]) == "" {
+			rest++
+		}
+		lines = append(lines[:first], lines[rest:]...)
+	} else {
+		lines[first] = "## " + heading
+	}
+	return frontMatter + strings.Join(lines, "\n")
+}
+
+// splitFrontMatter splits content after its closing "---" line, returning
+// ("", content) if it has no front matter.
+func splitFrontMatter(content string) (frontMatter, body string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[len("---\n"):], "\n---\n")
+	if end < 0 {
+		return "", content
+	}
+	end += len("---\n") + len("\n---\n")
+	return content[:end], content[end:]
+}
+
+// sameTitle reports whether a heading says the same thing as title,
+// ignoring case, punctuation, and markdown emphasis.
+func sameTitle(heading, title string) bool {
+	normalize := func(s string) string {
+		var sb strings.Builder
+		for _, r := range strings.ToLower(s) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String()
+	}
+	h := normalize(heading)
+	return h != "" && h == normalize(title)
+}
@@ -0,0 +1,23 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/datadriven"
+)
+
+func TestDemoteLeadingH1(t *testing.T) {
+	datadriven.Walk(t, "testdata/postprocess", func(t *testing.T, path string) {
+		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
+			switch d.Cmd {
+			case "demote-h1":
+				var title string
+				d.ScanArgs(t, "title", &title)
+				return demoteLeadingH1(d.Input+"\n", title)
+			default:
+				d.Fatalf(t, "unknown command %q", d.Cmd)
+				return ""
+			}
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package extractor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// probeBodyLimit is how much of the response body Probe keeps.
+const probeBodyLimit = 1024
+
+// ProbeResult describes what a plain GET of a page returned, for diagnosing
+// extractions that fail or come out wrong: paywalls, redirects, bot blocks.
+type ProbeResult struct {
+	URL       string
+	Redirects []string // URLs redirected to, in order
+	Status    string   // e.g. "403 Forbidden"
+	Header    http.Header
+	Body      string // the start of the body, up to probeBodyLimit bytes
+	Elapsed   time.Duration
+}
+
+// Probe fetches sourceURL the way local extraction does and reports the
+// response, without converting anything.
+func (e *Extractor) Probe(sourceURL string) (*ProbeResult, error) {
+	if parsed, err := url.Parse(sourceURL); err == nil && parsed.Scheme == "" {
+		sourceURL = "https://" + sourceURL
+	}
+	result := &ProbeResult{URL: sourceURL}
+	client := *e.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		result.Redirects = append(result.Redirects, req.URL.String())
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, probeBodyLimit))
+	result.Elapsed = time.Since(start)
+	result.Status = resp.Status
+	result.Header = resp.Header
+	result.Body = string(body)
+	if err != nil {
+		return result, fmt.Errorf("reading page: %w", err)
+	}
+	return result, nil
+}
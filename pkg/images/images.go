@@ -0,0 +1,264 @@
+// Package images downloads the remote images an article's markdown
+// references and rewrites the references to point at local copies, so the
+// article no longer depends on the remote host.
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxImageSize = 10 << 20
+	maxImages    = 100
+
+	userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15"
+)
+
+// imageRefRe matches markdown image references, capturing everything up to
+// the URL and the URL itself: ![alt](url "title").
+var imageRefRe = regexp.MustCompile(`(!\[[^\]]*\]\(\s*<?)([^)\s>]+)`)
+
+// Image is a downloaded image and its path relative to the article, e.g.
+// "images/figure-1.png".
+type Image struct {
+	Path string
+	Data []byte
+}
+
+// ErrTooLarge is the error for an image larger than the limit on images
+// saved with an article; it's left remote rather than saved cut short.
+var ErrTooLarge = fmt.Errorf("larger than %d MB", maxImageSize>>20)
+
+// Failure is a remote image that couldn't be downloaded. Its reference is
+// left as is.
+type Failure struct {
+	URL string
+	Err error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("%s: %v", f.URL, f.Err)
+}
+
+// Result is the outcome of DownloadAndRewrite.
+type Result struct {
+	Markdown string    // the markdown with downloaded images pointing at Images
+	Images   []Image   // images to write alongside the article
+	Reused   int       // images already in dir that references now point at
+	Failed   []Failure // remote images left as they were
+}
+
+// DownloadAndRewrite downloads the remote (http, https or protocol-relative)
+// images referenced in markdown and rewrites each reference to <dir>/<name>,
+// where dir is the article's image directory, e.g. "images". Names are taken
+// from the image URLs, made unique among themselves and the names in
+// existing, the files already in dir mapped to their sizes. An image
+// referenced several times is downloaded once.
+//
+// A file in existing with the name an image would be given, and the size the
+// server reports for it, is taken to be the image itself, saved by an earlier
+// run that was interrupted before the markdown was rewritten. It is reused
+// instead of being downloaded again, so rerunning is cheap and doesn't leave
+// duplicates behind.
+func DownloadAndRewrite(client *http.Client, markdown, dir string, existing map[string]int64) Result {
+	return DownloadAndRewriteAs(client, userAgent, markdown, dir, existing)
+}
+
+// DownloadAndRewriteAs is DownloadAndRewrite, sending userAgent as the
+// User-Agent of its requests instead of desktop Safari's.
+func DownloadAndRewriteAs(client *http.Client, userAgent, markdown, dir string, existing map[string]int64) Result {
+	used := make(map[string]bool, len(existing))
+	for name := range existing {
+		used[name] = true
+	}
+	reused := make(map[string]bool) // names of existing files already claimed
+	var result Result
+	localPaths := make(map[string]string) // remote URL -> local path, "" if it failed
+	result.Markdown = imageRefRe.ReplaceAllStringFunc(markdown, func(ref string) string {
+		m := imageRefRe.FindStringSubmatch(ref)
+		prefix, remote := m[1], m[2]
+		abs := absURL(remote)
+		if abs == "" {
+			return ref
+		}
+		local, ok := localPaths[abs]
+		if !ok {
+			if len(result.Images) >= maxImages {
+				return ref
+			}
+			if name := localFilename(abs, nil); !reused[name] && existing[name] > 0 && remoteSize(client, userAgent, abs) == existing[name] {
+				reused[name] = true
+				result.Reused++
+				local = dir + "/" + name
+				localPaths[abs] = local
+				return prefix + local
+			}
+			data, err := fetch(client, userAgent, abs)
+			if err != nil {
+				result.Failed = append(result.Failed, Failure{URL: abs, Err: err})
+			} else {
+				name := localFilename(abs, used)
+				used[name] = true
+				local = dir + "/" + name
+				result.Images = append(result.Images, Image{Path: local, Data: data})
+			}
+			localPaths[abs] = local
+		}
+		if local == "" {
+			return ref
+		}
+		return prefix + local
+	})
+	return result
+}
+
+// absURL returns ref as an absolute http(s) URL, or "" if it is local.
+func absURL(ref string) string {
+	if strings.HasPrefix(ref, "//") {
+		ref = "https:" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+// remoteSize returns the size the server reports for an image, from a HEAD
+// request, or -1 if it doesn't say.
+func remoteSize(client *http.Client, userAgent, imageURL string) int64 {
+	req, err := http.NewRequest(http.MethodHead, imageURL, nil)
+	if err != nil {
+		return -1
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return -1
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// fetch downloads a single image.
+func fetch(client *http.Client, userAgent, imageURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, ErrTooLarge
+	}
+	if err := Check(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Check reports an error if data isn't an image, judging by its first
+// bytes. Some servers answer a request for an image with an HTML error or
+// login page and a 200, which would otherwise be saved as, say, foo.png.
+// Formats are recognized by their signatures: those http.DetectContentType
+// knows (PNG, JPEG, GIF, WebP, BMP, ICO), AVIF and HEIC, and SVG.
+func Check(data []byte) error {
+	kind := http.DetectContentType(data)
+	if strings.HasPrefix(kind, "image/") {
+		return nil
+	}
+	// ISO base media files start with a box of type "ftyp" naming a brand.
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "avif", "avis", "heic", "heix", "mif1", "msf1":
+			return nil
+		}
+	}
+	if isSVG(data) {
+		return nil
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("not an image: empty")
+	}
+	return fmt.Errorf("not an image: looks like %s", strings.TrimSuffix(kind, "; charset=utf-8"))
+}
+
+// isSVG reports whether data is an SVG document: its first element, after
+// any XML declaration, comments and doctype, is <svg>.
+func isSVG(data []byte) bool {
+	head := data[:min(len(data), 1024)]
+	for {
+		head = bytes.TrimLeft(head, " \t\r\n\ufeff")
+		switch {
+		case bytes.HasPrefix(head, []byte("<?")), bytes.HasPrefix(head, []byte("<!")):
+			end := bytes.IndexByte(head, '>')
+			if end < 0 {
+				return false
+			}
+			head = head[end+1:]
+		default:
+			return bytes.HasPrefix(head, []byte("<svg"))
+		}
+	}
+}
+
+// localFilename returns a sanitized file name for an image, taken from its
+// URL and made unique among used. The endpoint's _local_filename names
+// images the same way.
+func localFilename(imageURL string, used map[string]bool) string {
+	base := ""
+	if u, err := url.Parse(imageURL); err == nil {
+		base = path.Base(u.Path)
+	}
+	return FileName(base, used)
+}
+
+// FileName returns name made safe for an image file name, keeping only
+// letters, digits, '.', '-' and '_', with an extension, and numbered to be
+// unique among used.
+func FileName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return -1
+	}, name)
+	if strings.Trim(name, ".") == "" {
+		name = "image.png"
+	}
+	if path.Ext(name) == "" {
+		name += ".png"
+	}
+	if !used[name] {
+		return name
+	}
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s-%d%s", stem, i, ext); !used[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// MaxSize is the size in bytes at which the log file is rotated. One
+// rotated file (path + ".1") is kept, so logs use at most ~2×MaxSize on disk.
+const MaxSize = 1 << 20
+
+// levelOff is above every slog level, so nothing is logged.
+const levelOff = slog.Level(100)
+
+// ParseLevel maps a config log_level ("debug", "info", "warn", "error",
+// "off") to a slog level. The empty string means "info".
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "off", "none":
+		return levelOff, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn, error, or off)", s)
+}
+
+// Open returns a logger that appends timestamped key=value records to the
+// file at path, rotating it once it grows past MaxSize. The returned closer
+// must be closed on exit.
+func Open(path, level string) (*slog.Logger, io.Closer, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lvl == levelOff {
+		return Discard(), nopCloser{}, nil
+	}
+	w, err := openRotating(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})
+	return slog.New(h), w, nil
+}
+
+// Discard returns a logger that drops all records.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: levelOff}))
+}
+
+// rotatingFile is an append-only file that is moved aside to path+".1" once
+// it exceeds MaxSize.
+type rotatingFile struct {
+	path string
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func openRotating(path string) (*rotatingFile, error) {
+	r := &rotatingFile{path: path}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if r.size >= MaxSize {
+		if err := r.rotate(); err != nil {
+			r.f.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return fmt.Errorf("rotating log file: %w", err)
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("rotating log file: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size+int64(len(p)) > MaxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,110 @@
+package safari
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// ErrNoWindow is returned by FrontTabURL and FrontWindowTabs when
+	// Safari isn't running or has no window open.
+	ErrNoWindow = errors.New("no Safari window is open")
+	// ErrNoPage is returned by FrontTabURL when the front tab isn't showing
+	// a web page, e.g. it's a new tab or the Start Page.
+	ErrNoPage = errors.New("Safari's front tab isn't showing a web page")
+	// ErrAutomationDenied is returned when macOS doesn't allow the terminal
+	// to control Safari.
+	ErrAutomationDenied = errors.New("Automation permission required — allow your terminal to control Safari in System Settings > Privacy & Security > Automation")
+)
+
+// FrontTabURL returns the URL of the current tab of Safari's front window.
+// It doesn't launch Safari if it isn't running.
+func FrontTabURL() (string, error) {
+	const script = `if application "Safari" is not running then return ""
+tell application "Safari"
+	if (count of windows) is 0 then return ""
+	set u to URL of current tab of front window
+	if u is missing value then return "-"
+	return u
+end tell`
+	out, err := runOSAScript("AppleScript", script)
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSpace(string(out))
+	switch {
+	case url == "":
+		return "", ErrNoWindow
+	case !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://"):
+		return "", ErrNoPage
+	}
+	return url, nil
+}
+
+// FrontWindowTabs returns the tabs of Safari's front window, in the order
+// they appear in the tab bar, with Source "window". Tabs not showing a web
+// page are left out. It doesn't launch Safari if it isn't running.
+func FrontWindowTabs() ([]Tab, error) {
+	const script = `
+var safari = Application("Safari");
+var tabs = [];
+if (safari.running() && safari.windows.length > 0) {
+    var win = safari.windows[0];
+    for (var t = 0; t < win.tabs.length; t++) {
+        var tab = win.tabs[t];
+        tabs.push({url: tab.url() || "", title: tab.name() || ""});
+    }
+}
+JSON.stringify(tabs);
+`
+	out, err := runOSAScript("JavaScript", script)
+	if err != nil {
+		return nil, err
+	}
+	var raw []struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("parsing JXA output: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, ErrNoWindow
+	}
+
+	historyTimes := localTabHistoryTimes()
+	var tabs []Tab
+	for _, r := range raw {
+		if !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+			continue
+		}
+		t := Tab{URL: r.URL, Title: r.Title, Source: "window"}
+		if ts, ok := historyTimes[r.URL]; ok && ts > 0 {
+			t.LastViewed = appleTimeToGoTime(ts)
+		}
+		tabs = append(tabs, t)
+	}
+	return tabs, nil
+}
+
+// runOSAScript runs script with osascript in the given language
+// ("AppleScript" or "JavaScript"), returning its output. A denied
+// Automation permission is reported as ErrAutomationDenied.
+func runOSAScript(language, script string) ([]byte, error) {
+	out, err := exec.Command("osascript", "-l", language, "-e", script).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			stderr := strings.TrimSpace(string(exitErr.Stderr))
+			if strings.Contains(stderr, "-1743") {
+				return nil, ErrAutomationDenied
+			}
+			return nil, fmt.Errorf("osascript: %s", stderr)
+		}
+		return nil, fmt.Errorf("osascript: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package safari
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Permission is a macOS privacy permission that some tab sources need.
+type Permission struct {
+	Name    string // e.g. "Automation"
+	Granted bool
+	// Purpose says which sources need the permission; Detail says why it
+	// looks missing.
+	Purpose string
+	Detail  string
+	// SettingsURL opens the matching System Settings pane.
+	SettingsURL string
+}
+
+// System Settings panes for the permissions shelf needs.
+const (
+	automationSettingsURL     = "x-apple.systempreferences:com.apple.preference.security?Privacy_Automation"
+	fullDiskAccessSettingsURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_AllFiles"
+)
+
+// CheckPermissions probes the permissions the given Safari sources (see
+// Sources; all of them if none are given) rely on: Automation (controlling
+// Safari via JXA, for open tabs) and Full Disk Access (reading Safari's
+// databases, for iCloud tabs, Reading List and visit times). Probing up
+// front turns errors like -1743 into guided setup. Visit times alone don't
+// warrant asking for Full Disk Access, so it's only probed for iCloud tabs
+// or the Reading List.
+func CheckPermissions(sources []string) []Permission {
+	if len(sources) == 0 {
+		sources = Sources
+	}
+	var perms []Permission
+	if slices.Contains(sources, "local") {
+		perms = append(perms, checkAutomation())
+	}
+	if slices.Contains(sources, "icloud") || slices.Contains(sources, "readinglist") {
+		perms = append(perms, checkFullDiskAccess())
+	}
+	return perms
+}
+
+func checkAutomation() Permission {
+	p := Permission{
+		Name:        "Automation",
+		Purpose:     "lets shelf read open Safari tabs",
+		SettingsURL: automationSettingsURL,
+	}
+	out, err := exec.Command("osascript", "-l", "JavaScript", "-e", `Application("Safari").windows.length`).CombinedOutput()
+	switch {
+	case err == nil:
+		p.Granted = true
+	case strings.Contains(string(out), "-1743"):
+		p.Detail = "allow your terminal to control Safari under Privacy & Security > Automation"
+	default:
+		p.Detail = fmt.Sprintf("osascript: %s", strings.TrimSpace(string(out)))
+	}
+	return p
+}
+
+func checkFullDiskAccess() Permission {
+	p := Permission{
+		Name:        "Full Disk Access",
+		Purpose:     "lets shelf read iCloud tabs, Reading List and visit times",
+		SettingsURL: fullDiskAccessSettingsURL,
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		p.Detail = err.Error()
+		return p
+	}
+	// Stat succeeds without Full Disk Access; only reading is denied.
+	f, err := os.Open(filepath.Join(home, "Library", "Safari", "History.db"))
+	switch {
+	case err == nil:
+		f.Close()
+		p.Granted = true
+	case errors.Is(err, fs.ErrPermission):
+		p.Detail = "add your terminal under Privacy & Security > Full Disk Access, then restart it"
+	case errors.Is(err, fs.ErrNotExist):
+		p.Detail = "Safari's History.db not found"
+	default:
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+// OpenSettings opens the System Settings pane where p can be granted.
+func (p Permission) OpenSettings() error {
+	if err := exec.Command("open", p.SettingsURL).Run(); err != nil {
+		return fmt.Errorf("opening System Settings: %w", err)
+	}
+	return nil
+}
@@ -28,39 +28,49 @@ func appleTimeToGoTime(appleTS float64) time.Time {
 	return time.Unix(int64(appleTS)+appleEpochOffset, 0)
 }
 
-// GatherTabs collects tabs from all available Safari sources (local tabs,
-// iCloud tabs, Reading List). Each source is best-effort: failures are
-// returned as warnings rather than fatal errors. Tabs are deduplicated
-// within each source independently (keeping the most recently viewed on
-// URL collision).
-func GatherTabs() (map[string][]Tab, []error) {
+// Sources are the keys of the tab sources GatherTabs can read, in their
+// default order: open tabs, iCloud Tabs and the Reading List.
+var Sources = []string{"local", "icloud", "readinglist"}
+
+// GatherTabs collects tabs from the given Safari sources (see Sources), or
+// all of them if none are given; the rest aren't read at all. Each source is
+// best-effort: failures are returned as warnings rather than fatal errors.
+// Tabs are deduplicated within each source independently (keeping the most
+// recently viewed on URL collision).
+func GatherTabs(sources []string) (map[string][]Tab, []error) {
+	if len(sources) == 0 {
+		sources = Sources
+	}
 	result := make(map[string][]Tab)
 	var warnings []error
-
-	local, err := localTabs()
-	if err != nil {
-		warnings = append(warnings, fmt.Errorf("local tabs: %w", err))
-	}
-	if len(local) > 0 {
-		result["local"] = deduplicateByURL(local)
-	}
-
-	icloud, err := icloudTabs()
-	if err != nil {
-		warnings = append(warnings, fmt.Errorf("iCloud tabs: %w", err))
-	}
-	if len(icloud) > 0 {
-		result["icloud"] = deduplicateByURL(icloud)
-	}
-
-	reading, err := readingListTabs()
-	if err != nil {
-		warnings = append(warnings, fmt.Errorf("Reading List: %w", err))
-	}
-	if len(reading) > 0 {
-		result["readinglist"] = deduplicateByURL(reading)
+	for _, source := range sources {
+		var (
+			tabs []Tab
+			err  error
+		)
+		switch source {
+		case "local":
+			if tabs, err = localTabs(); err != nil {
+				err = fmt.Errorf("local tabs: %w", err)
+			}
+		case "icloud":
+			if tabs, err = icloudTabs(); err != nil {
+				err = fmt.Errorf("iCloud tabs: %w", err)
+			}
+		case "readinglist":
+			if tabs, err = readingListTabs(); err != nil {
+				err = fmt.Errorf("Reading List: %w", err)
+			}
+		default:
+			err = fmt.Errorf("unknown tab source %q", source)
+		}
+		if err != nil {
+			warnings = append(warnings, err)
+		}
+		if len(tabs) > 0 {
+			result[source] = deduplicateByURL(tabs)
+		}
 	}
-
 	return result, warnings
 }
 
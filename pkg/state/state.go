@@ -0,0 +1,170 @@
+// Package state persists small bits of shelf's runtime state between runs,
+// such as reading activity, paused imports and the last list filter. It
+// lives in state.json in the data directory, separate from the articles
+// themselves.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the state file within the data directory.
+const FileName = "state.json"
+
+// dayLayout keys Activity by local calendar day.
+const dayLayout = "2006-01-02"
+
+// State is shelf's persisted runtime state. Load it with Load, mutate it,
+// and call Save.
+type State struct {
+	path string
+
+	// Activity counts articles opened and finished per day, keyed by
+	// YYYY-MM-DD in local time.
+	Activity map[string]DayActivity `json:"activity,omitempty"`
+
+	// ImportQueue holds the URLs not yet fetched when a batch import was
+	// paused, so it can be resumed in a later session.
+	ImportQueue []QueuedImport `json:"import_queue,omitempty"`
+
+	// Session is the list filter in effect when shelf last exited, restored
+	// on the next launch if restore_session is set.
+	Session *Session `json:"session,omitempty"`
+
+	// EditorViews is where the reader left each article in vim, keyed by
+	// the article's file path, so reopening it restores the scroll
+	// position and column as well as the line.
+	EditorViews map[string]EditorView `json:"editor_views,omitempty"`
+
+	// LastVisit is when shelf was last started, so articles saved since can
+	// be marked as new.
+	LastVisit time.Time `json:"last_visit,omitzero"`
+}
+
+// EditorView is a cursor position and scroll offset in vim: 1-based line
+// and column, and the first line shown in the window.
+type EditorView struct {
+	Line    int `json:"line"`
+	Col     int `json:"col"`
+	TopLine int `json:"top_line"`
+}
+
+// Session is a list filter: a search query, possibly from a saved search,
+// and whether archived articles are shown.
+type Session struct {
+	Query        string `json:"query,omitempty"`
+	SavedSearch  string `json:"saved_search,omitempty"` // name of the applied saved search
+	ShowArchived bool   `json:"show_archived,omitempty"`
+}
+
+// QueuedImport is a URL waiting in a paused import, with the tags it was
+// given in the import buffer.
+type QueuedImport struct {
+	URL  string   `json:"url"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// DayActivity is a single day's reading activity.
+type DayActivity struct {
+	Opened   int `json:"opened,omitempty"`
+	Finished int `json:"finished,omitempty"`
+}
+
+// Load reads the state file at path. A missing file yields empty state.
+func Load(path string) (*State, error) {
+	s := &State{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("reading state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the state back to the file it was loaded from. State that
+// wasn't loaded from a file has nowhere to go, and is an error.
+func (s *State) Save() error {
+	if s.path == "" {
+		return errors.New("saving state: no state file loaded")
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing tmp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("renaming tmp file: %w", err)
+	}
+	return nil
+}
+
+// MoveArticle re-keys what's kept by article file path, the editor view,
+// from an article's old path to its new one. It reports whether there was
+// anything to move.
+func (s *State) MoveArticle(from, to string) bool {
+	view, ok := s.EditorViews[from]
+	if !ok {
+		return false
+	}
+	delete(s.EditorViews, from)
+	s.EditorViews[to] = view
+	return true
+}
+
+// RecordOpen counts an article opened at t.
+func (s *State) RecordOpen(t time.Time) {
+	s.updateDay(t, func(d *DayActivity) { d.Opened++ })
+}
+
+// RecordFinish counts an article finished at t.
+func (s *State) RecordFinish(t time.Time) {
+	s.updateDay(t, func(d *DayActivity) { d.Finished++ })
+}
+
+func (s *State) updateDay(t time.Time, fn func(*DayActivity)) {
+	if s.Activity == nil {
+		s.Activity = make(map[string]DayActivity)
+	}
+	day := t.Local().Format(dayLayout)
+	d := s.Activity[day]
+	fn(&d)
+	s.Activity[day] = d
+}
+
+// Day returns the activity recorded on t's local calendar day.
+func (s *State) Day(t time.Time) DayActivity {
+	return s.Activity[t.Local().Format(dayLayout)]
+}
+
+// Streak returns the number of consecutive days, ending today, on which at
+// least one article was opened. A streak isn't broken until a full day
+// passes without reading, so if nothing has been opened yet today the
+// streak through yesterday is returned.
+func (s *State) Streak(now time.Time) int {
+	day := now.Local()
+	if s.Day(day).Opened == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	n := 0
+	for s.Day(day).Opened > 0 {
+		n++
+		day = day.AddDate(0, 0, -1)
+	}
+	return n
+}
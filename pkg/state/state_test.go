@@ -0,0 +1,127 @@
+package state_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/state"
+)
+
+func TestStreak(t *testing.T) {
+	path := filepath.Join(t.TempDir(), state.FileName)
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	if got := s.Streak(now); got != 0 {
+		t.Fatalf("empty state: streak = %d, want 0", got)
+	}
+
+	// Read yesterday and the two days before; a gap before that.
+	for _, d := range []int{1, 2, 3, 5} {
+		s.RecordOpen(daysAgo(d))
+	}
+	s.RecordFinish(daysAgo(1))
+	if got := s.Streak(now); got != 3 {
+		t.Fatalf("nothing read today: streak = %d, want 3", got)
+	}
+
+	s.RecordOpen(now)
+	s.RecordOpen(now)
+	if got := s.Streak(now); got != 4 {
+		t.Fatalf("streak = %d, want 4", got)
+	}
+	if got := s.Streak(now.AddDate(0, 0, 2)); got != 0 {
+		t.Fatalf("after a missed day: streak = %d, want 0", got)
+	}
+
+	// Round-trip through disk.
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Day(now); got != (state.DayActivity{Opened: 2}) {
+		t.Fatalf("today after reload = %+v", got)
+	}
+	if got := s.Day(daysAgo(1)); got != (state.DayActivity{Opened: 1, Finished: 1}) {
+		t.Fatalf("yesterday after reload = %+v", got)
+	}
+}
+
+func TestImportQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), state.FileName)
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RecordOpen(time.Now())
+	s.ImportQueue = []state.QueuedImport{
+		{URL: "https://a.com/1"},
+		{URL: "https://b.com/2", Tags: []string{"rust", "async"}},
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.ImportQueue, s.ImportQueue) {
+		t.Fatalf("import queue = %+v, want %+v", loaded.ImportQueue, s.ImportQueue)
+	}
+	if loaded.Day(time.Now()).Opened != 1 {
+		t.Fatalf("activity lost alongside the import queue")
+	}
+
+	// Clearing the queue drops it from the file.
+	loaded.ImportQueue = nil
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "import_queue") {
+		t.Fatalf("cleared queue still saved:\n%s", data)
+	}
+}
+
+func TestSaveUnloaded(t *testing.T) {
+	// State that wasn't loaded from a file has nowhere to be saved, rather
+	// than being written next to the working directory.
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := (&state.State{}).Save(); err == nil {
+		t.Fatal("saved state with no file")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Fatalf("wrote %s", entries[0].Name())
+	}
+}
+
+func TestMoveArticle(t *testing.T) {
+	s := &state.State{}
+	if s.MoveArticle("articles/a.md", "articles/a/index.md") {
+		t.Errorf("moved an article with nothing kept for it")
+	}
+	view := state.EditorView{Line: 12, Col: 3, TopLine: 5}
+	s.EditorViews = map[string]state.EditorView{"articles/a.md": view}
+	if !s.MoveArticle("articles/a.md", "articles/a/index.md") {
+		t.Fatalf("editor view not moved")
+	}
+	if _, ok := s.EditorViews["articles/a.md"]; ok || s.EditorViews["articles/a/index.md"] != view {
+		t.Errorf("editor views after moving = %v", s.EditorViews)
+	}
+}
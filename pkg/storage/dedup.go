@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TrashDir is the directory, beside articles/, that soft-deleted articles
+// are moved to. Nothing in it is listed or searched.
+const TrashDir = "trash"
+
+// Duplicates is a group of articles that look like one article saved more
+// than once.
+type Duplicates struct {
+	Articles []ArticleMeta // oldest first
+	Matched  []string      // what they share: "url", "title" and/or "content"
+}
+
+// NormalizeURL reduces an article URL to a form that is the same for
+// trivially different spellings of it: the scheme, a leading "www.", a
+// trailing slash, the fragment and utm_* tracking parameters are ignored.
+// It returns "" for URLs that don't parse or have no host.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	normalized := bareHost(u.Host) + strings.TrimSuffix(u.EscapedPath(), "/")
+	if len(query) > 0 {
+		normalized += "?" + query.Encode()
+	}
+	return normalized
+}
+
+// normalizeTitle lowercases a title and drops everything but letters and
+// digits, so punctuation and spacing differences don't matter.
+func normalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	if sb.String() == "untitled" {
+		return ""
+	}
+	return sb.String()
+}
+
+// sourceHost returns the host of a source URL without a leading "www.", or
+// "" if it has none.
+func sourceHost(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return bareHost(u.Host)
+}
+
+// FindDuplicates groups articles that share a normalized source URL (see
+// NormalizeURL), the same body text, ignoring whitespace, or a normalized
+// title from the same site. A title alone is not enough: different sites
+// often use the same one ("Introduction", "Release notes"). Articles are
+// grouped transitively: if A and B share a URL and B and C their body, all
+// three are one group. Groups are ordered by their oldest article.
+func (s *Store) FindDuplicates() ([]Duplicates, error) {
+	articles := s.List()
+	keys := make([]map[string]string, len(articles)) // kind -> key, per article
+	for i, a := range articles {
+		article, err := s.Get(a.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.FilePath, err)
+		}
+		keys[i] = map[string]string{"url": NormalizeURL(a.SourceURL)}
+		if title, host := normalizeTitle(a.Title), sourceHost(a.SourceURL); title != "" && host != "" {
+			keys[i]["title"] = host + " " + title
+		}
+		if body := strings.Join(strings.Fields(article.Content), " "); body != "" {
+			keys[i]["content"] = fmt.Sprintf("%x", sha256.Sum256([]byte(body)))
+		}
+	}
+
+	// Union-find over article indices, joining articles with a key in
+	// common.
+	parent := make([]int, len(articles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	kinds := []string{"url", "title", "content"}
+	for _, kind := range kinds {
+		first := make(map[string]int) // key -> first article with it
+		for i := range articles {
+			key := keys[i][kind]
+			if key == "" {
+				continue
+			}
+			if j, ok := first[key]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				first[key] = i
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range articles {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+	var groups []Duplicates
+	for _, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		var d Duplicates
+		for _, kind := range kinds {
+			seen := make(map[string]bool)
+			for _, i := range idx {
+				key := keys[i][kind]
+				if key != "" && seen[key] {
+					d.Matched = append(d.Matched, kind)
+					break
+				}
+				seen[key] = true
+			}
+		}
+		for _, i := range idx {
+			d.Articles = append(d.Articles, articles[i])
+		}
+		sort.Slice(d.Articles, func(i, j int) bool {
+			a, b := d.Articles[i], d.Articles[j]
+			if !a.SavedAt.Equal(b.SavedAt) {
+				return a.SavedAt.Before(b.SavedAt)
+			}
+			return a.FilePath < b.FilePath
+		})
+		groups = append(groups, d)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].Articles[0], groups[j].Articles[0]
+		if !a.SavedAt.Equal(b.SavedAt) {
+			return a.SavedAt.Before(b.SavedAt)
+		}
+		return a.FilePath < b.FilePath
+	})
+	return groups, nil
+}
+
+// MergeDuplicates keeps the article at keep, adds to it any tags the others
+// have that it doesn't, and moves the others to the trash.
+func (s *Store) MergeDuplicates(keep string, others []string) error {
+	byPath := make(map[string]ArticleMeta)
+	for _, a := range s.List() {
+		byPath[a.FilePath] = a
+	}
+	kept, ok := byPath[keep]
+	if !ok {
+		return fmt.Errorf("no article %s", keep)
+	}
+	tags := slices.Clone(kept.Tags)
+	for _, p := range others {
+		other, ok := byPath[p]
+		if !ok {
+			return fmt.Errorf("no article %s", p)
+		}
+		for _, t := range other.Tags {
+			if !hasTag(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	if len(tags) != len(kept.Tags) {
+		if err := s.UpdateTags(keep, tags); err != nil {
+			return err
+		}
+	}
+	for _, p := range others {
+		if _, err := s.Trash(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Trash moves an article, with its images and notes, out of articles/ into
+// TrashDir, where it can be recovered by moving it back (see Untrash). It
+// returns where the article went, relative to the data directory.
+func (s *Store) Trash(filePath string) (string, error) {
+	src := filepath.Join(s.basePath, filePath)
+	if filepath.Base(filePath) == "index.md" {
+		src = filepath.Dir(src)
+	}
+	trashDir := filepath.Join(s.basePath, TrashDir)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return "", fmt.Errorf("creating trash directory: %w", err)
+	}
+	name := time.Now().Format("20060102-150405-") + filepath.Base(src)
+	dst := filepath.Join(trashDir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			break
+		}
+		dst = filepath.Join(trashDir, fmt.Sprintf("%s-%d", name, i))
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return "", fmt.Errorf("moving article to trash: %w", err)
+	}
+	trashed, err := filepath.Rel(s.basePath, dst)
+	if err != nil {
+		return "", err
+	}
+	return trashed, s.refresh(filePath)
+}
+
+// Untrash moves an article that Trash moved to trashed back to filePath,
+// where it was. It fails if something has been saved there since.
+func (s *Store) Untrash(trashed, filePath string) error {
+	dst := filepath.Join(s.basePath, filePath)
+	if filepath.Base(filePath) == "index.md" {
+		dst = filepath.Dir(dst)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("restoring article: %s already exists", filePath)
+	}
+	if err := os.Rename(filepath.Join(s.basePath, trashed), dst); err != nil {
+		return fmt.Errorf("restoring article from trash: %w", err)
+	}
+	return s.refresh(filePath)
+}
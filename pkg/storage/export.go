@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportArticle copies the article at filePath into dir, which is created
+// if needed: a directory-format article's whole directory, with its images
+// and notes, or a flat file as it is. It returns the path of the copy.
+func (s *Store) ExportArticle(filePath, dir string) (string, error) {
+	src := filepath.Join(s.basePath, filePath)
+	if filepath.Base(filePath) == "index.md" {
+		src = filepath.Dir(src)
+	}
+	dst := filepath.Join(dir, filepath.Base(src))
+	if _, err := os.Stat(dst); err == nil {
+		return "", fmt.Errorf("exporting %s: %s already exists", filePath, dst)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating export directory: %w", err)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", fmt.Errorf("exporting %s: %w", filePath, err)
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return "", fmt.Errorf("exporting %s: %w", filePath, err)
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return "", fmt.Errorf("exporting %s: %w", filePath, err)
+		}
+		return dst, nil
+	}
+
+	err = filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." && strings.HasPrefix(d.Name(), ".") {
+			// Hidden files, e.g. .DS_Store, aren't part of the article.
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, 0644)
+	})
+	if err != nil {
+		return "", fmt.Errorf("exporting %s: %w", filePath, err)
+	}
+	return dst, nil
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Front matter fences: YAML front matter is written between "---" lines, as
+// shelf writes it, and TOML front matter between "+++" lines, as Hugo does.
+const (
+	yamlFence = "---"
+	tomlFence = "+++"
+)
+
+// splitFrontMatter splits content into its front matter fence, the header
+// between the fences and the body after the closing one. ok is false if
+// content doesn't start with front matter.
+func splitFrontMatter(content string) (fence, header, body string, ok bool) {
+	for _, fence := range []string{yamlFence, tomlFence} {
+		parts := strings.SplitN(content, fence+"\n", 3)
+		if len(parts) == 3 && parts[0] == "" {
+			return fence, parts[1], parts[2], true
+		}
+	}
+	return "", "", content, false
+}
+
+// frontMatter holds the fields shelf reads from an article's front matter.
+type frontMatter struct {
+	title, author, source string
+	saved                 time.Time
+	tags                  []string
+	progress, order       int
+	locked                bool
+	warnings              []string
+	fetchTime             time.Duration
+	archiveNote           string
+}
+
+// parseTOMLHeader reads the fields shelf uses from a TOML front matter
+// header. Hugo's date and authors fields stand in for saved and author.
+func parseTOMLHeader(header string) (frontMatter, error) {
+	var fields map[string]any
+	if _, err := toml.Decode(header, &fields); err != nil {
+		return frontMatter{}, fmt.Errorf("parsing TOML front matter: %w", err)
+	}
+	str := func(key string) string {
+		s, _ := fields[key].(string)
+		return s
+	}
+	strs := func(key string) []string {
+		var out []string
+		switch v := fields[key].(type) {
+		case string:
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					out = append(out, s)
+				}
+			}
+		case []any:
+			for _, e := range v {
+				if s, ok := e.(string); ok && strings.TrimSpace(s) != "" {
+					out = append(out, strings.TrimSpace(s))
+				}
+			}
+		}
+		return out
+	}
+
+	var fm frontMatter
+	fm.title, fm.source = str("title"), str("source")
+	if fm.author = str("author"); fm.author == "" {
+		fm.author = strings.Join(strs("authors"), ", ")
+	}
+	fm.tags = strs("tags")
+	for _, key := range []string{"saved", "date"} {
+		switch v := fields[key].(type) {
+		case time.Time:
+			fm.saved = v
+		case string:
+			saved, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return frontMatter{}, fmt.Errorf("parsing %s time: %w", key, err)
+			}
+			fm.saved = saved
+		}
+		if !fm.saved.IsZero() {
+			break
+		}
+	}
+	switch v := fields["progress"].(type) {
+	case int64:
+		fm.progress = int(v)
+	case string:
+		fm.progress, _ = strconv.Atoi(strings.TrimPrefix(v, "L"))
+	}
+	if v, ok := fields["order"].(int64); ok {
+		fm.order = max(int(v), 0)
+	}
+	fm.locked, _ = fields["locked"].(bool)
+	fm.warnings = strs("warnings")
+	if v, ok := fields["fetch_ms"].(int64); ok {
+		fm.fetchTime = time.Duration(max(v, 0)) * time.Millisecond
+	}
+	fm.archiveNote = str("archive_note")
+	return fm, nil
+}
+
+// fillMissingMeta fills in the title and saved time of an article whose
+// front matter doesn't give them, as for a markdown file dropped into
+// articles/ by hand: the title from its first H1, or else its file name,
+// and the saved time from when the file was last modified.
+func (s *Store) fillMissingMeta(meta *ArticleMeta, body string) {
+	if meta.Title == "" {
+		if m := headingRe.FindStringSubmatch(body); m != nil {
+			meta.Title = strings.TrimSpace(m[1])
+		}
+	}
+	if meta.Title == "" {
+		name := filepath.Base(meta.FilePath)
+		if name == "index.md" {
+			name = filepath.Base(filepath.Dir(meta.FilePath))
+		}
+		meta.Title = fileTitle(name)
+	}
+	if meta.SavedAt.IsZero() {
+		if info, err := os.Stat(filepath.Join(s.basePath, meta.FilePath)); err == nil {
+			meta.SavedAt = info.ModTime()
+		}
+	}
+}
+
+// fileTitle makes a title from a file name, e.g. "release notes" for
+// "release-notes.md".
+func fileTitle(name string) string {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	}), " ")
+}
+
+// tomlKeyRe matches the start of a TOML key/value line, capturing the key.
+var tomlKeyRe = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*=`)
+
+// setField replaces the top-level field key in content's front matter with
+// the line line returns for its fence style, adding it if it's missing, or
+// removes it if line returns "". The fence style is kept.
+func setField(content, key string, line func(fence string) string) (string, error) {
+	fence, header, body, ok := splitFrontMatter(content)
+	if !ok {
+		return "", fmt.Errorf("invalid front matter")
+	}
+	newLine := line(fence)
+	if newLine != "" {
+		newLine += "\n"
+	}
+
+	var newHeader strings.Builder
+	found := false
+	if fence == tomlFence {
+		// A field can't be appended after a [table] header, which would
+		// put it in the table, so a new one goes before the first.
+		skipping := false
+		for _, l := range strings.Split(header, "\n") {
+			trimmed := strings.TrimSpace(l)
+			if skipping {
+				// The rest of a multi-line array being replaced.
+				skipping = !strings.HasSuffix(trimmed, "]")
+				continue
+			}
+			if m := tomlKeyRe.FindStringSubmatch(trimmed); m != nil && m[1] == key {
+				newHeader.WriteString(newLine)
+				found = true
+				_, v, _ := strings.Cut(trimmed, "=")
+				v = strings.TrimSpace(v)
+				skipping = strings.HasPrefix(v, "[") && strings.Count(v, "[") > strings.Count(v, "]")
+				continue
+			}
+			if !found && strings.HasPrefix(trimmed, "[") {
+				newHeader.WriteString(newLine)
+				found = true
+			}
+			if trimmed != "" {
+				newHeader.WriteString(l + "\n")
+			}
+		}
+	} else {
+		for _, l := range strings.Split(header, "\n") {
+			trimmed := strings.TrimSpace(l)
+			if strings.HasPrefix(trimmed, key+":") {
+				newHeader.WriteString(newLine)
+				found = true
+			} else if trimmed != "" {
+				newHeader.WriteString(l + "\n")
+			}
+		}
+	}
+	if !found {
+		newHeader.WriteString(newLine)
+	}
+
+	return fence + "\n" + newHeader.String() + fence + "\n" + body, nil
+}
+
+// frontMatterFields splits content's front matter into its top-level
+// fields, each as written, by key: its line, or lines for a multi-line
+// TOML array. Fields in a TOML [table] aren't top-level.
+func frontMatterFields(content string) map[string]string {
+	fence, header, _, ok := splitFrontMatter(content)
+	fields := make(map[string]string)
+	if !ok {
+		return fields
+	}
+	var key string
+	inArray := false
+	for _, l := range strings.Split(header, "\n") {
+		trimmed := strings.TrimSpace(l)
+		if fence != tomlFence {
+			if k, _, found := strings.Cut(l, ":"); found && l == trimmed {
+				fields[k] = l
+			}
+			continue
+		}
+		if inArray {
+			// The rest of a multi-line array.
+			fields[key] += "\n" + l
+			inArray = !strings.HasSuffix(trimmed, "]")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			break // a table
+		}
+		if m := tomlKeyRe.FindStringSubmatch(trimmed); m != nil {
+			key = m[1]
+			fields[key] = l
+			_, v, _ := strings.Cut(trimmed, "=")
+			v = strings.TrimSpace(v)
+			inArray = strings.HasPrefix(v, "[") && strings.Count(v, "[") > strings.Count(v, "]")
+		}
+	}
+	return fields
+}
+
+// tomlStrings formats ss as a TOML array of strings.
+func tomlStrings(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
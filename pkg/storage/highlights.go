@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// HighlightsFile is the name of the file, alongside a directory-format
+// article's index.md, that collects the passages highlighted in it.
+const HighlightsFile = "highlights.md"
+
+// highlightRe matches a highlighted passage, ==like this==, which may span
+// lines.
+var highlightRe = regexp.MustCompile(`==([^=\s](?:[^=]|=[^=])*?)==`)
+
+// Highlight is a passage the reader marked in an article's body.
+type Highlight struct {
+	Line int    // line in index.md where the passage starts
+	Text string // the passage, with line breaks collapsed to spaces
+}
+
+// Highlights returns the passages marked ==like this== in the article at
+// filePath, in order. Markers in the front matter and in fenced code blocks
+// don't count.
+func (s *Store) Highlights(filePath string) ([]Highlight, error) {
+	content, err := os.ReadFile(filepath.Join(s.basePath, filePath))
+	if err != nil {
+		return nil, fmt.Errorf("reading article: %w", err)
+	}
+	return parseHighlights(string(content)), nil
+}
+
+func parseHighlights(content string) []Highlight {
+	// Blank out the front matter and code blocks, keeping their line
+	// breaks so line numbers still line up.
+	lines := strings.Split(content, "\n")
+	fence := lines[0]
+	inFrontMatter := fence == yamlFence || fence == tomlFence
+	inFence := false
+	for i, line := range lines {
+		switch {
+		case inFrontMatter:
+			if i > 0 && line == fence {
+				inFrontMatter = false
+			}
+			lines[i] = ""
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			inFence = !inFence
+			lines[i] = ""
+		case inFence:
+			lines[i] = ""
+		}
+	}
+	body := strings.Join(lines, "\n")
+
+	var highlights []Highlight
+	for _, m := range highlightRe.FindAllStringSubmatchIndex(body, -1) {
+		highlights = append(highlights, Highlight{
+			Line: strings.Count(body[:m[0]], "\n") + 1,
+			Text: strings.Join(strings.Fields(body[m[2]:m[3]]), " "),
+		})
+	}
+	return highlights
+}
+
+// SyncHighlights rewrites the highlights.md beside the directory-format
+// article at filePath from the passages currently marked in it, removing
+// the file if there are none. Flat-file articles are left alone.
+func (s *Store) SyncHighlights(filePath string) error {
+	if filepath.Base(filePath) != "index.md" {
+		return nil
+	}
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
+	}
+	highlights := parseHighlights(string(content))
+	path := filepath.Join(filepath.Dir(fullPath), HighlightsFile)
+	if len(highlights) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing highlights: %w", err)
+		}
+		return nil
+	}
+
+	fm, _, _ := parseFrontMatter(string(content))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Highlights from %s\n", fm.title)
+	for _, h := range highlights {
+		fmt.Fprintf(&sb, "\n> %s\n", h.Text)
+	}
+	if existing, err := os.ReadFile(path); err == nil && string(existing) == sb.String() {
+		return nil
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("writing tmp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming tmp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/images"
+)
+
+// partSuffix marks an image LocalizeImages is still writing.
+const partSuffix = ".part"
+
+// imageRefRe matches markdown image references: ![alt](path "title").
+var imageRefRe = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?`)
+
+// DefaultImageDir is the directory, inside an article's directory, that
+// images are stored in unless the Store is given another with WithImageDir.
+// The extractor, local or remote, always names it this.
+const DefaultImageDir = "images"
+
+// extractedImageRe matches the start of a markdown or HTML image reference
+// into DefaultImageDir, capturing everything before the directory name.
+var extractedImageRe = regexp.MustCompile(`(!\[[^\]]*\]\(\s*<?|(?i:<img\s[^>]*\bsrc\s*=\s*["']?))` + DefaultImageDir + `/`)
+
+// relocateImages moves an extracted article's images from DefaultImageDir
+// to dir, rewriting the references to them in content to match.
+func relocateImages(content string, images []ImageFile, dir string) (string, []ImageFile) {
+	if dir == DefaultImageDir {
+		return content, images
+	}
+	relocated := make([]ImageFile, len(images))
+	for i, img := range images {
+		if rest, ok := strings.CutPrefix(img.Path, DefaultImageDir+"/"); ok {
+			img.Path = dir + "/" + rest
+		}
+		relocated[i] = img
+	}
+	return extractedImageRe.ReplaceAllString(content, "${1}"+dir+"/"), relocated
+}
+
+// ImageRef is an image referenced from an article's markdown.
+type ImageRef struct {
+	Alt  string
+	Path string // as written in the markdown, e.g. "images/figure-1.png"
+}
+
+// IsRemote reports whether the image is still a remote URL rather than a
+// file stored alongside the article.
+func (r ImageRef) IsRemote() bool {
+	return strings.HasPrefix(r.Path, "http://") || strings.HasPrefix(r.Path, "https://") ||
+		strings.HasPrefix(r.Path, "//")
+}
+
+// Images returns the images referenced by the article at filePath, in order
+// of first appearance.
+func (s *Store) Images(filePath string) ([]ImageRef, error) {
+	content, err := os.ReadFile(filepath.Join(s.basePath, filePath))
+	if err != nil {
+		return nil, fmt.Errorf("reading article: %w", err)
+	}
+	return parseImageRefs(string(content)), nil
+}
+
+// ImagePath returns the absolute path of a local image referenced by the
+// article at filePath.
+func (s *Store) ImagePath(filePath string, ref ImageRef) string {
+	return filepath.Join(filepath.Dir(s.GetFilePath(filePath)), filepath.FromSlash(ref.Path))
+}
+
+func parseImageRefs(content string) []ImageRef {
+	var refs []ImageRef
+	seen := make(map[string]bool)
+	for _, m := range imageRefRe.FindAllStringSubmatch(content, -1) {
+		if seen[m[2]] {
+			continue
+		}
+		seen[m[2]] = true
+		refs = append(refs, ImageRef{Alt: m[1], Path: m[2]})
+	}
+	return refs
+}
+
+// RemoteImages is an article that still references remote images.
+type RemoteImages struct {
+	Article ArticleMeta
+	Count   int // distinct remote images referenced
+	Total   int // distinct images referenced, local or remote
+}
+
+// RemoteImageReport returns the articles whose markdown still references
+// remote images, in List order. Such images break if the remote copy goes
+// away; articles with none are fully self-contained.
+func (s *Store) RemoteImageReport() ([]RemoteImages, error) {
+	var report []RemoteImages
+	for _, meta := range s.List() {
+		refs, err := s.Images(meta.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.FilePath, err)
+		}
+		r := RemoteImages{Article: meta, Total: len(refs)}
+		for _, ref := range refs {
+			if ref.IsRemote() {
+				r.Count++
+			}
+		}
+		if r.Count > 0 {
+			report = append(report, r)
+		}
+	}
+	return report, nil
+}
+
+// LocalizeImages downloads the remote images referenced by the
+// directory-format article at filePath into its image directory and points
+// the references at the local copies. New images are written before
+// index.md is replaced, so the article never references a missing file, and
+// each is written to a ".part" file first and renamed into place, so none is
+// ever left truncated. Images already downloaded by an interrupted run are
+// reused (see images.DownloadAndRewrite), and count as fetched. Images that
+// fail to download keep their remote references; they're returned alongside
+// the number fetched.
+func (s *Store) LocalizeImages(filePath string, client *http.Client) (fetched int, failed []images.Failure, err error) {
+	if filepath.Base(filePath) != "index.md" {
+		return 0, nil, fmt.Errorf("%s: images can only be stored with directory-format articles", filePath)
+	}
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading article: %w", err)
+	}
+	imageDir := filepath.Join(filepath.Dir(fullPath), s.imageDir)
+	existing := make(map[string]int64)
+	if entries, err := os.ReadDir(imageDir); err == nil {
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), partSuffix) {
+				// Left by an interrupted write; the image is fetched again.
+				os.Remove(filepath.Join(imageDir, e.Name()))
+				continue
+			}
+			if info, err := e.Info(); err == nil && !e.IsDir() {
+				existing[e.Name()] = info.Size()
+			}
+		}
+	}
+
+	result := images.DownloadAndRewrite(client, string(content), s.imageDir, existing)
+	if len(result.Images) == 0 && result.Reused == 0 {
+		return 0, result.Failed, nil
+	}
+
+	s.saving.Add(1)
+	defer s.saving.Done()
+	var written []string
+	cleanup := func() {
+		for _, p := range written {
+			os.Remove(p)
+		}
+	}
+	if err := os.MkdirAll(imageDir, 0755); err != nil {
+		return 0, nil, fmt.Errorf("creating image directory: %w", err)
+	}
+	for _, img := range result.Images {
+		p := filepath.Join(filepath.Dir(fullPath), filepath.FromSlash(img.Path))
+		if err := os.WriteFile(p+partSuffix, img.Data, 0644); err != nil {
+			os.Remove(p + partSuffix)
+			cleanup()
+			return 0, nil, fmt.Errorf("writing image %s: %w", img.Path, err)
+		}
+		if err := os.Rename(p+partSuffix, p); err != nil {
+			os.Remove(p + partSuffix)
+			cleanup()
+			return 0, nil, fmt.Errorf("writing image %s: %w", img.Path, err)
+		}
+		written = append(written, p)
+	}
+	tmpPath := fullPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(result.Markdown), 0644); err != nil {
+		cleanup()
+		return 0, nil, fmt.Errorf("writing tmp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		cleanup()
+		return 0, nil, fmt.Errorf("renaming tmp file: %w", err)
+	}
+	return len(result.Images) + result.Reused, result.Failed, s.refresh(filePath)
+}
+
+// Image references imageRefRe doesn't capture whole: the src of raw HTML
+// <img> tags, ![](<path with spaces>), and reference-style link
+// definitions ([id]: path), which ![alt][id] may point at.
+var (
+	htmlImageRe      = regexp.MustCompile(`(?i)<img\s[^>]*\bsrc\s*=\s*["']?([^"'\s>]+)`)
+	bracketedImageRe = regexp.MustCompile(`!\[[^\]]*\]\(\s*<([^>]+)>`)
+	linkDefRe        = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:\s*<?([^\s>]+)`)
+)
+
+// OrphanedImages returns the files in the image directory of the article
+// at filePath that its markdown no longer references, as paths relative to
+// the article directory, along with their total size. A file counts as
+// referenced however the reference spells it: with or without "./",
+// percent-encoded, with a query string, or in different case.
+func (s *Store) OrphanedImages(filePath string) (orphans []string, size int64, err error) {
+	fullPath := filepath.Join(s.basePath, filePath)
+	if filepath.Base(filePath) != "index.md" {
+		return nil, 0, nil // flat-file articles have no image directory
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading article: %w", err)
+	}
+	referenced := make(map[string]bool)
+	for _, ref := range parseImageRefs(string(content)) {
+		for _, p := range imagePathSpellings(ref.Path) {
+			referenced[p] = true
+		}
+	}
+	for _, re := range []*regexp.Regexp{htmlImageRe, bracketedImageRe, linkDefRe} {
+		for _, m := range re.FindAllStringSubmatch(string(content), -1) {
+			for _, p := range imagePathSpellings(m[1]) {
+				referenced[p] = true
+			}
+		}
+	}
+
+	articleDir := filepath.Dir(fullPath)
+	imageDir := filepath.Join(articleDir, s.imageDir)
+	err = filepath.WalkDir(imageDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == imageDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(articleDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if referenced[strings.ToLower(rel)] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, rel)
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing images: %w", err)
+	}
+	return orphans, size, nil
+}
+
+// PruneImages deletes the files OrphanedImages reports for the article at
+// filePath, returning how many were removed and the bytes freed.
+func (s *Store) PruneImages(filePath string) (removed int, freed int64, err error) {
+	orphans, _, err := s.OrphanedImages(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	articleDir := filepath.Dir(filepath.Join(s.basePath, filePath))
+	for _, rel := range orphans {
+		p := filepath.Join(articleDir, filepath.FromSlash(rel))
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			return removed, freed, fmt.Errorf("removing %s: %w", rel, err)
+		}
+		removed++
+		freed += info.Size()
+	}
+	if removed > 0 {
+		err = s.refresh(filePath)
+	}
+	return removed, freed, err
+}
+
+// imagePathSpellings returns the article-relative paths a local image
+// reference may name, lowercased: as written and percent-decoded, each
+// cleaned of "./" and the like, without any query or fragment. Remote and
+// absolute references name no local file.
+func imagePathSpellings(ref string) []string {
+	ref = strings.TrimSpace(ref)
+	if (ImageRef{Path: ref}).IsRemote() || strings.HasPrefix(ref, "/") {
+		return nil
+	}
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" {
+		return nil // e.g. data: URIs
+	}
+	var spellings []string
+	for _, p := range []string{ref, stripQuery(ref)} {
+		spellings = append(spellings, p)
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			spellings = append(spellings, unescaped)
+		}
+	}
+	for i, p := range spellings {
+		spellings[i] = strings.ToLower(path.Clean(p))
+	}
+	return spellings
+}
+
+// stripQuery removes a query string or fragment from ref.
+func stripQuery(ref string) string {
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		return ref[:i]
+	}
+	return ref
+}
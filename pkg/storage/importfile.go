@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/images"
+)
+
+// HTMLConverter converts an HTML page to markdown for ImportFile. It
+// returns the page's title, or "" if it has none, and markdown without
+// front matter, with image references left as written in the page.
+type HTMLConverter func(html string) (title, markdown string, err error)
+
+// WithHTMLConverter lets ImportFile import HTML files, converting them with
+// fn. Without one, only markdown and text files can be imported.
+func WithHTMLConverter(fn HTMLConverter) Option {
+	return func(s *Store) {
+		s.htmlConverter = fn
+	}
+}
+
+// headingRe matches a markdown H1 line.
+var headingRe = regexp.MustCompile(`(?m)^#[ \t]+(.+?)[ \t#]*$`)
+
+// ImportFile saves a markdown (.md, .markdown, .txt) or HTML (.html, .htm)
+// file from elsewhere on disk as an article. Front matter in a markdown
+// file is kept; otherwise the title is taken from the first H1, or failing
+// that the file name. The rest of the front matter comes from the note
+// template (see WithNoteTemplate). Images the file references by relative
+// path are copied into the article's image directory.
+func (s *Store) ImportFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	var title, author, source, body string
+	var saved time.Time
+	var tags []string
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".md", ".markdown", ".txt":
+		var fm frontMatter
+		fm, body, err = parseFrontMatter(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		title, author, source, saved, tags = fm.title, fm.author, fm.source, fm.saved, fm.tags
+	case ".html", ".htm":
+		if s.htmlConverter == nil {
+			return fmt.Errorf("%s: importing HTML files isn't supported here", filename)
+		}
+		title, body, err = s.htmlConverter(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	default:
+		return fmt.Errorf("%s: can't import %q files; want .md, .markdown, .txt, .html or .htm", filename, ext)
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%s: file is empty", filename)
+	}
+	if title == "" {
+		if m := headingRe.FindStringSubmatch(body); m != nil {
+			title = strings.TrimSpace(m[1])
+		}
+	}
+	if title == "" {
+		title = fileTitle(filepath.Base(filename))
+	}
+	if saved.IsZero() {
+		saved = time.Now()
+	}
+
+	body, files := copyLocalImages(body, filepath.Dir(filename))
+
+	// The front matter comes from the note template, with the file's own
+	// fields filled in and its tags added to the template's.
+	content := s.fillTemplate(title, saved)
+	tmpl, _, err := parseFrontMatter(content)
+	if err != nil {
+		return fmt.Errorf("note template: %w", err)
+	}
+	tmplTags := tmpl.tags
+	for _, t := range tags {
+		if !hasTag(tmplTags, t) {
+			tmplTags = append(tmplTags, t)
+		}
+	}
+	fields := []struct {
+		key, value string
+		quote      bool
+	}{
+		{"author", author, true},
+		{"source", source, false},
+		{"tags", strings.Join(tmplTags, ", "), false},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		content, err = setField(content, f.key, func(fence string) string {
+			switch {
+			case fence == tomlFence && f.key == "tags":
+				return "tags = " + tomlStrings(tmplTags)
+			case fence == tomlFence:
+				return fmt.Sprintf("%s = %q", f.key, f.value)
+			case f.quote:
+				return fmt.Sprintf("%s: %q", f.key, f.value)
+			}
+			return f.key + ": " + f.value
+		})
+		if err != nil {
+			return fmt.Errorf("note template: %w", err)
+		}
+	}
+	fence, header, _, _ := splitFrontMatter(content)
+	content = fence + "\n" + header + fence + "\n\n" + strings.TrimLeft(body, "\n")
+	return s.SaveContent(title, content, files)
+}
+
+// copyLocalImages reads the images body references by a path relative to
+// dir (or an absolute one), returning them as files in DefaultImageDir and
+// body with the references rewritten to match. References to images that
+// can't be read, or aren't images, are left as they are.
+func copyLocalImages(body, dir string) (string, []ImageFile) {
+	var files []ImageFile
+	renamed := make(map[string]string) // reference as written -> new path
+	used := make(map[string]bool)
+	for _, ref := range parseImageRefs(body) {
+		if _, ok := renamed[ref.Path]; ok || ref.IsRemote() || strings.Contains(ref.Path, ":") {
+			continue
+		}
+		p := stripQuery(ref.Path)
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			p = unescaped
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, filepath.FromSlash(p))
+		}
+		data, err := os.ReadFile(p)
+		if err != nil || images.Check(data) != nil {
+			continue
+		}
+		name := images.FileName(filepath.Base(p), used)
+		used[name] = true
+		renamed[ref.Path] = path.Join(DefaultImageDir, name)
+		files = append(files, ImageFile{Path: path.Join(DefaultImageDir, name), Data: data})
+	}
+	body = imageRefRe.ReplaceAllStringFunc(body, func(match string) string {
+		m := imageRefRe.FindStringSubmatch(match)
+		if to, ok := renamed[m[2]]; ok {
+			return strings.Replace(match, m[2], to, 1)
+		}
+		return match
+	})
+	return body, files
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsFlat reports whether the article is an older single-file article,
+// articles/<slug>.md, rather than a directory with an index.md. Flat files
+// have nowhere to keep images or notes; see MigrateFlat.
+func (m ArticleMeta) IsFlat() bool {
+	return filepath.Base(m.FilePath) != "index.md"
+}
+
+// MigrateFlat converts the flat-file article at filePath to directory
+// format, moving articles/<slug>.md to articles/<slug>/index.md, and
+// returns its new path. The file is copied as it is, so its progress,
+// source URL and the rest of its front matter carry over. The directory is
+// staged like a save, so an interrupted migration leaves the flat file in
+// place. It refuses if a directory of the same name already exists.
+func (s *Store) MigrateFlat(filePath string) (string, error) {
+	if filepath.Base(filePath) == "index.md" {
+		return "", fmt.Errorf("%s is already in directory format", filePath)
+	}
+	articlesDir := filepath.Join(s.basePath, "articles")
+	slug := strings.TrimSuffix(filepath.Base(filePath), ".md")
+	dir := filepath.Join(articlesDir, slug)
+	newPath := filepath.Join("articles", slug, "index.md")
+	if _, err := os.Stat(dir); err == nil {
+		var title string
+		for _, a := range s.List() {
+			if a.FilePath == newPath {
+				title = a.Title
+			}
+		}
+		return "", &ErrArticleExists{Slug: slug, Title: title}
+	}
+
+	src := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("reading article: %w", err)
+	}
+	staging, err := os.MkdirTemp(articlesDir, "."+slug+stagingInfix)
+	if err != nil {
+		return "", fmt.Errorf("creating staging directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, "index.md"), content, 0644); err != nil {
+		os.RemoveAll(staging)
+		return "", fmt.Errorf("writing article: %w", err)
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		os.RemoveAll(staging)
+		return "", fmt.Errorf("moving article into place: %w", err)
+	}
+	if err := os.Remove(src); err != nil {
+		return "", fmt.Errorf("removing flat file: %w", err)
+	}
+
+	if err := s.refresh(filePath); err != nil {
+		return "", err
+	}
+	return newPath, s.refresh(newPath)
+}
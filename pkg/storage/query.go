@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Article statuses accepted by the status: query field.
+const (
+	StatusUnread   = "unread"   // not yet opened (no saved progress)
+	StatusReading  = "reading"  // opened at least once
+	StatusArchived = "archived" // has the archive tag
+)
+
+// Query is a parsed search query. Terms of the form tag:x, domain:x and
+// status:x become filters; everything else is free text matched against
+// title, author, domain and tags.
+type Query struct {
+	Text    string
+	Tags    []string
+	Domains []string
+	Status  string
+}
+
+// ParseQuery splits a search query into field filters and free text. Field
+// names and values are case-insensitive. Double quotes group words into one
+// term, so tag:"machine learning" is a single filter.
+func ParseQuery(query string) Query {
+	var q Query
+	var text []string
+	for _, term := range queryTerms(strings.ToLower(query)) {
+		field, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			text = append(text, term)
+			continue
+		}
+		switch field {
+		case "tag":
+			q.Tags = append(q.Tags, value)
+		case "domain":
+			q.Domains = append(q.Domains, value)
+		case "status":
+			q.Status = value
+		default:
+			text = append(text, term)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// queryTerms splits a query at whitespace outside double quotes, dropping
+// the quotes.
+func queryTerms(query string) []string {
+	var terms []string
+	var term strings.Builder
+	inQuote, inTerm := false, false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuote, inTerm = !inQuote, true
+		case unicode.IsSpace(r) && !inQuote:
+			if inTerm {
+				terms = append(terms, term.String())
+				term.Reset()
+				inTerm = false
+			}
+		default:
+			term.WriteRune(r)
+			inTerm = true
+		}
+	}
+	if inTerm {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
+
+// TagQuery returns the query that filters by tag, quoting it if it has
+// spaces.
+func TagQuery(tag string) string {
+	if strings.ContainsFunc(tag, unicode.IsSpace) {
+		return `tag:"` + tag + `"`
+	}
+	return "tag:" + tag
+}
+
+// Matches reports whether an article satisfies every part of the query.
+func (q Query) Matches(meta ArticleMeta) bool {
+	for _, tag := range q.Tags {
+		if !hasTag(meta.Tags, tag) {
+			return false
+		}
+	}
+	for _, domain := range q.Domains {
+		if !strings.Contains(strings.ToLower(meta.SourceDomain), domain) {
+			return false
+		}
+	}
+	switch q.Status {
+	case "":
+	case StatusUnread:
+		if meta.IsArchived() || meta.Progress > 0 {
+			return false
+		}
+	case StatusReading:
+		if meta.IsArchived() || meta.Progress == 0 {
+			return false
+		}
+	case StatusArchived:
+		if !meta.IsArchived() {
+			return false
+		}
+	default:
+		return false
+	}
+	return q.Text == "" || q.MatchedField(meta) != ""
+}
+
+// Field is an article field that a query's free text is matched against.
+type Field string
+
+// The fields free text is matched against, in the order MatchedField
+// checks them.
+const (
+	FieldTitle  Field = "title"
+	FieldAuthor Field = "author"
+	FieldDomain Field = "domain"
+	FieldTags   Field = "tags"
+)
+
+// MatchedField returns the first field the query's free text matches in
+// meta, showing why an article is among the results, or "" if there's no
+// free text or it matches none. The filters aren't checked; see Matches.
+func (q Query) MatchedField(meta ArticleMeta) Field {
+	if q.Text == "" {
+		return ""
+	}
+	switch {
+	case strings.Contains(strings.ToLower(meta.Title), q.Text):
+		return FieldTitle
+	case strings.Contains(strings.ToLower(meta.Author), q.Text):
+		return FieldAuthor
+	case strings.Contains(strings.ToLower(meta.SourceDomain), q.Text):
+		return FieldDomain
+	case strings.Contains(strings.ToLower(strings.Join(meta.Tags, ",")), q.Text):
+		return FieldTags
+	}
+	return ""
+}
@@ -2,19 +2,34 @@ package storage
 
 import (
 	"fmt"
+	"io/fs"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 )
 
 var multiHyphenRe = regexp.MustCompile(`-+`)
 
+// ErrArticleLocked is returned when overwriting an article that is locked
+// (see SetLocked).
+type ErrArticleLocked struct {
+	Title string
+}
+
+func (e *ErrArticleLocked) Error() string {
+	return fmt.Sprintf("%q is locked", e.Title)
+}
+
 // ErrArticleExists is returned when saving an article whose slug already exists.
 type ErrArticleExists struct {
 	Slug  string
@@ -33,22 +48,55 @@ type Article struct {
 
 // ArticleMeta represents article metadata parsed from markdown front matter.
 type ArticleMeta struct {
-	Title        string
-	Author       string
-	SourceURL    string
-	SourceDomain string    // derived from SourceURL
-	SavedAt      time.Time
-	Tags         []string  // optional comma-separated tags
-	Progress     int       // last vim cursor line (from front matter)
-	TotalLines   int       // total lines in file (computed at scan time)
-	FilePath     string    // relative path, derived from disk
-	FileSize     int64     // derived from os.Stat
-	NoteCount    int       // number of [[note]] markers in content
-}
-
-// IsArchived returns true if the article has the "archived" tag.
+	Title         string
+	Author        string
+	SourceURL     string
+	SourceDomain  string // derived from SourceURL
+	SourceSection string // derived from SourceURL; see sourceSection
+	SavedAt       time.Time
+	Tags          []string // optional comma-separated tags
+	Progress      int      // last vim cursor line (from front matter)
+	TotalLines    int      // total lines in file (computed at scan time)
+	FilePath      string   // relative path, derived from disk
+	FileSize      int64    // flat files: from os.Stat; directories: unset (use Store.ArticleSize), unless WithSizeOrder
+	NoteCount     int      // number of [[note]] markers in content
+	HasNotes      bool     // directory format: has a non-empty notes.md
+	Archived      bool     // has the store's archive tag (see WithArchiveTag)
+	Pinned        bool     // has PinTag; listed before unpinned articles
+	Order         int      // manual sort weight (order: in front matter); 0 if unset
+	Locked        bool     // locked: true in front matter; never re-fetched or overwritten
+	Warnings      []string // doubts the extractor had about the article (warnings: in front matter)
+
+	// FetchTime is how long extraction took (fetch_ms: in front matter),
+	// or 0 if unknown.
+	FetchTime time.Duration
+
+	// ArchiveNote is why an archived article was archived (archive_note:
+	// in front matter), if the reader said.
+	ArchiveNote string
+
+	// sized is whether FileSize is the article's whole on-disk size, so
+	// ArticleSize needn't measure it. An empty article can have size 0.
+	sized bool
+}
+
+// PinTag is the tag that pins an article above the unpinned ones.
+const PinTag = "pinned"
+
+// IsArchived returns true if the article has the store's archive tag.
 func (m ArticleMeta) IsArchived() bool {
-	return hasTag(m.Tags, "archived")
+	return m.Archived
+}
+
+// IsPinned returns true if the article has PinTag.
+func (m ArticleMeta) IsPinned() bool {
+	return m.Pinned
+}
+
+// IsFinished returns true if the reader's saved position is on the article's
+// last line.
+func (m ArticleMeta) IsFinished() bool {
+	return m.TotalLines > 0 && m.Progress >= m.TotalLines-1
 }
 
 // ImageFile holds image data to be written to disk.
@@ -57,15 +105,109 @@ type ImageFile struct {
 	Data []byte
 }
 
-// Store manages article storage.
+// Store manages article storage. It is safe for concurrent use: batch
+// imports save articles from background commands while the TUI reads.
 type Store struct {
-	basePath string
-	articles []ArticleMeta // cached from scanning articles/ dir
+	basePath       string
+	archiveTag     string
+	archiveAliases []string // other tags that also count as archived
+	defaultTags    []string // added to new articles; see WithDefaultTags
+
+	mu       sync.Mutex
+	articles []ArticleMeta    // cached from scanning articles/ dir
+	scanned  bool             // articles has been filled in; see Open
+	sizes    map[string]int64 // lazily computed directory sizes, by FilePath
+	imageDir string           // name of each article's image directory
+
+	manualOrder bool // sort by ArticleMeta.Order; see WithManualOrder
+	sizeOrder   bool // sort by on-disk size; see WithSizeOrder
+
+	htmlConverter HTMLConverter // for ImportFile; see WithHTMLConverter
+	noteTemplate  string        // for SaveNote and ImportFile; see WithNoteTemplate
+
+	// saving tracks in-flight saves so that Wait can let them finish before
+	// the process exits.
+	saving sync.WaitGroup
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithArchiveTag sets the tag that marks an article as archived. The
+// default is "archived".
+func WithArchiveTag(tag string) Option {
+	return func(s *Store) {
+		s.archiveTag = tag
+	}
+}
+
+// WithArchiveAliases sets additional tags that count as archived for
+// filtering and sorting, e.g. "done" from an older convention. Archiving
+// an article always adds the archive tag itself.
+func WithArchiveAliases(tags ...string) Option {
+	return func(s *Store) {
+		s.archiveAliases = tags
+	}
+}
+
+// WithDefaultTags sets tags that SaveContent adds to every new article,
+// alongside those in its front matter. Overwriting an article, as a re-fetch
+// does with SaveContentForce, doesn't add them.
+func WithDefaultTags(tags ...string) Option {
+	return func(s *Store) {
+		s.defaultTags = tags
+	}
+}
+
+// WithImageDir sets the name of the directory, inside each article's
+// directory, that its images are stored in. The default is
+// DefaultImageDir.
+func WithImageDir(name string) Option {
+	return func(s *Store) {
+		s.imageDir = name
+	}
+}
+
+// WithManualOrder lists articles by the order: field in their front matter,
+// as set by Move, ahead of those without one. Archived and pinned articles
+// are still grouped as usual, each group ordered on its own.
+func WithManualOrder() Option {
+	return func(s *Store) {
+		s.manualOrder = true
+	}
+}
+
+// WithSizeOrder lists the largest articles first, counting the images of
+// directory-format articles, to find the ones taking up the most space.
+// Directory sizes are then computed when articles are scanned rather than
+// when first shown, so FileSize is filled in for every article. Archived
+// and pinned articles are still grouped as usual.
+func WithSizeOrder() Option {
+	return func(s *Store) {
+		s.sizeOrder = true
+	}
 }
 
 // New creates a new Store at the given base path.
-func New(basePath string) (*Store, error) {
-	s := &Store{basePath: basePath}
+func New(basePath string, opts ...Option) (*Store, error) {
+	s, err := Open(basePath, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Scan(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open is New without the initial scan: the store lists no articles until
+// Scan is called, e.g. in the background so that a large shelf doesn't hold
+// up starting the TUI.
+func Open(basePath string, opts ...Option) (*Store, error) {
+	s := &Store{basePath: basePath, archiveTag: "archived", imageDir: DefaultImageDir}
+	for _, opt := range opts {
+		opt(s)
+	}
 
 	// Ensure directories exist
 	articlesDir := filepath.Join(basePath, "articles")
@@ -73,14 +215,49 @@ func New(basePath string) (*Store, error) {
 		return nil, fmt.Errorf("creating articles directory: %w", err)
 	}
 
-	// Scan existing articles
-	if err := s.scan(); err != nil {
-		return nil, fmt.Errorf("scanning articles: %w", err)
+	// Remove staging directories left behind by a save that was
+	// interrupted (e.g. the process was killed mid-write).
+	if err := removeStaging(articlesDir); err != nil {
+		return nil, fmt.Errorf("cleaning up interrupted saves: %w", err)
 	}
 
 	return s, nil
 }
 
+// Scan reads the metadata of every article on disk, replacing what's
+// listed.
+func (s *Store) Scan() error {
+	if err := s.scan(); err != nil {
+		return fmt.Errorf("scanning articles: %w", err)
+	}
+	return nil
+}
+
+// Scanned reports whether the articles have been scanned, i.e. whether the
+// store came from New or Scan has since finished.
+func (s *Store) Scanned() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scanned
+}
+
+// EntryCount returns how many entries articles/ holds, roughly the number
+// of articles, without reading any of them: for showing progress before
+// Scan has finished.
+func (s *Store) EntryCount() int {
+	entries, err := os.ReadDir(filepath.Join(s.basePath, "articles"))
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), ".") {
+			n++
+		}
+	}
+	return n
+}
+
 func (s *Store) scan() error {
 	articlesDir := filepath.Join(s.basePath, "articles")
 	entries, err := os.ReadDir(articlesDir)
@@ -88,99 +265,256 @@ func (s *Store) scan() error {
 		return err
 	}
 
-	s.articles = nil
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Directory format: look for index.md inside.
-			indexPath := filepath.Join(articlesDir, entry.Name(), "index.md")
-			content, err := os.ReadFile(indexPath)
-			if err != nil {
-				continue
+	// Reading and parsing each article dominates startup on large shelves,
+	// so fan it out across a worker pool. Directory sizes are not computed
+	// here unless sorting by size; see ArticleSize.
+	metas := make([]ArticleMeta, len(entries))
+	found := make([]bool, len(entries))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(entries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				metas[i], found[i] = s.loadMeta(articlesDir, entries[i])
 			}
+		}()
+	}
+	for i := range entries {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
 
-			title, author, source, saved, tags, progress, _, err := parseFrontMatter(string(content))
-			if err != nil {
-				continue
-			}
+	var articles []ArticleMeta
+	for i, meta := range metas {
+		if found[i] {
+			articles = append(articles, meta)
+		}
+	}
 
-			relPath := filepath.Join("articles", entry.Name(), "index.md")
-			dirPath := filepath.Join(articlesDir, entry.Name())
-
-			meta := ArticleMeta{
-				Title:        title,
-				Author:       author,
-				SourceURL:    source,
-				SavedAt:      saved,
-				Tags:         tags,
-				Progress:     progress,
-				TotalLines:   strings.Count(string(content), "\n") + 1,
-				FilePath:     relPath,
-				FileSize:     calcDirSize(dirPath),
-				NoteCount:    strings.Count(string(content), "[[note]]"),
-			}
-			if source != "" {
-				if parsed, err := url.Parse(source); err == nil {
-					meta.SourceDomain = parsed.Host
-				}
-			}
-			s.articles = append(s.articles, meta)
-		} else if strings.HasSuffix(entry.Name(), ".md") {
-			// Flat file format (backward compat).
-			relPath := filepath.Join("articles", entry.Name())
-			fullPath := filepath.Join(s.basePath, relPath)
+	sort.Slice(articles, func(i, j int) bool {
+		return s.less(articles[i], articles[j])
+	})
 
-			content, err := os.ReadFile(fullPath)
-			if err != nil {
-				continue
-			}
+	s.mu.Lock()
+	s.articles = articles
+	s.scanned = true
+	s.sizes = nil // contents may have changed on disk
+	s.mu.Unlock()
+	return nil
+}
 
-			info, err := entry.Info()
-			if err != nil {
-				continue
-			}
+// less orders articles for listing: non-archived before archived,
+// pinned before unpinned within each, then newest first. With
+// WithManualOrder, articles with an Order come before the rest of their
+// group, lowest first, and with WithSizeOrder the largest come first. Ties
+// break on FilePath so the order is deterministic.
+func (s *Store) less(a, b ArticleMeta) bool {
+	if aa, ba := a.IsArchived(), b.IsArchived(); aa != ba {
+		return !aa // non-archived first
+	}
+	if ap, bp := a.IsPinned(), b.IsPinned(); ap != bp {
+		return ap // then pinned, within each group
+	}
+	if s.manualOrder && a.Order != b.Order {
+		if a.Order == 0 || b.Order == 0 {
+			return b.Order == 0 // ordered before unordered
+		}
+		return a.Order < b.Order
+	}
+	if s.sizeOrder && a.FileSize != b.FileSize {
+		return a.FileSize > b.FileSize
+	}
+	if !a.SavedAt.Equal(b.SavedAt) {
+		return a.SavedAt.After(b.SavedAt)
+	}
+	return a.FilePath < b.FilePath
+}
 
-			title, author, source, saved, tags, progress, _, err := parseFrontMatter(string(content))
-			if err != nil {
-				continue
-			}
+// refresh re-reads the single article at relPath and updates the in-memory
+// list in place, avoiding a full scan after a save, delete or metadata
+// update. An article that no longer exists (or no longer parses) is dropped.
+func (s *Store) refresh(relPath string) error {
+	articlesDir := filepath.Join(s.basePath, "articles")
+	name, _, _ := strings.Cut(strings.TrimPrefix(filepath.ToSlash(relPath), "articles/"), "/")
+
+	var (
+		meta  ArticleMeta
+		found bool
+	)
+	info, err := os.Stat(filepath.Join(articlesDir, name))
+	if err == nil {
+		meta, found = s.loadMeta(articlesDir, fs.FileInfoToDirEntry(info))
+	} else if !os.IsNotExist(err) {
+		return err
+	}
 
-			meta := ArticleMeta{
-				Title:        title,
-				Author:       author,
-				SourceURL:    source,
-				SavedAt:      saved,
-				Tags:         tags,
-				Progress:     progress,
-				TotalLines:   strings.Count(string(content), "\n") + 1,
-				FilePath:     relPath,
-				FileSize:     info.Size(),
-				NoteCount:    strings.Count(string(content), "[[note]]"),
-			}
-			if source != "" {
-				if parsed, err := url.Parse(source); err == nil {
-					meta.SourceDomain = parsed.Host
-				}
-			}
-			s.articles = append(s.articles, meta)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sizes, relPath)
+	for i, a := range s.articles {
+		if a.FilePath == relPath {
+			s.articles = slices.Delete(s.articles, i, i+1)
+			break
 		}
 	}
+	if found {
+		i := sort.Search(len(s.articles), func(i int) bool {
+			return s.less(meta, s.articles[i])
+		})
+		s.articles = slices.Insert(s.articles, i, meta)
+	}
+	return nil
+}
+
+// ArticleSize returns the on-disk size of an article, including its images
+// for directory-format articles. Walking a directory is comparatively slow,
+// so sizes are computed on first request (typically when the article is
+// first rendered) and cached until the next scan.
+func (s *Store) ArticleSize(meta ArticleMeta) int64 {
+	if meta.sized {
+		return meta.FileSize
+	}
+	s.mu.Lock()
+	size, ok := s.sizes[meta.FilePath]
+	s.mu.Unlock()
+	if ok {
+		return size
+	}
+
+	size = calcDirSize(filepath.Dir(filepath.Join(s.basePath, meta.FilePath)))
+	s.mu.Lock()
+	if s.sizes == nil {
+		s.sizes = make(map[string]int64)
+	}
+	s.sizes[meta.FilePath] = size
+	s.mu.Unlock()
+	return size
+}
+
+// TotalSize returns the on-disk size of all articles, including their
+// images; see ArticleSize.
+func (s *Store) TotalSize() int64 {
+	var total int64
+	for _, a := range s.List() {
+		total += s.ArticleSize(a)
+	}
+	return total
+}
+
+// FormatSize returns a human-readable size, such as ArticleSize's.
+func FormatSize(bytes int64) string {
+	const (
+		KB = 1024
+		MB = KB * 1024
+	)
+
+	switch {
+	case bytes >= MB:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/MB)
+	case bytes >= KB:
+		return fmt.Sprintf("%d KB", bytes/KB)
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
 
-	sort.Slice(s.articles, func(i, j int) bool {
-		ai, aj := s.articles[i].IsArchived(), s.articles[j].IsArchived()
-		if ai != aj {
-			return !ai // non-archived first
+// loadMeta reads the metadata for a single articles/ directory entry. It
+// reports false for entries that aren't articles or fail to parse.
+func (s *Store) loadMeta(articlesDir string, entry os.DirEntry) (ArticleMeta, bool) {
+	if strings.HasPrefix(entry.Name(), ".") {
+		// Hidden entries include in-progress saves (see saveContent).
+		return ArticleMeta{}, false
+	}
+
+	var (
+		relPath  string
+		content  []byte
+		size     int64
+		sized    bool
+		hasNotes bool
+	)
+	if entry.IsDir() {
+		// Directory format: look for index.md inside.
+		var err error
+		content, err = os.ReadFile(filepath.Join(articlesDir, entry.Name(), "index.md"))
+		if err != nil {
+			return ArticleMeta{}, false
 		}
-		return s.articles[i].SavedAt.After(s.articles[j].SavedAt)
-	})
+		relPath = filepath.Join("articles", entry.Name(), "index.md")
+		if info, err := os.Stat(filepath.Join(articlesDir, entry.Name(), NotesFile)); err == nil && info.Size() > 0 {
+			hasNotes = true
+		}
+		if s.sizeOrder {
+			size, sized = calcDirSize(filepath.Join(articlesDir, entry.Name())), true
+		}
+	} else if strings.HasSuffix(entry.Name(), ".md") {
+		// Flat file format (backward compat).
+		relPath = filepath.Join("articles", entry.Name())
+		var err error
+		content, err = os.ReadFile(filepath.Join(s.basePath, relPath))
+		if err != nil {
+			return ArticleMeta{}, false
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return ArticleMeta{}, false
+		}
+		size, sized = info.Size(), true
+	} else {
+		return ArticleMeta{}, false
+	}
 
-	return nil
+	fm, body, err := parseFrontMatter(string(content))
+	if err != nil {
+		return ArticleMeta{}, false
+	}
+
+	meta := ArticleMeta{
+		Title:      fm.title,
+		Author:     fm.author,
+		SourceURL:  fm.source,
+		SavedAt:    fm.saved,
+		Tags:       fm.tags,
+		Archived:   s.isArchiveTagged(fm.tags),
+		Pinned:     hasTag(fm.tags, PinTag),
+		Progress:   fm.progress,
+		Order:      fm.order,
+		Locked:     fm.locked,
+		Warnings:   fm.warnings,
+		FetchTime:  fm.fetchTime,
+		TotalLines: strings.Count(string(content), "\n") + 1,
+		FilePath:   relPath,
+		FileSize:   size,
+		sized:      sized,
+		NoteCount:  strings.Count(string(content), "[[note]]"),
+		HasNotes:   hasNotes,
+	}
+	if meta.Archived {
+		meta.ArchiveNote = fm.archiveNote
+	}
+	if fm.source != "" {
+		if parsed, err := url.Parse(fm.source); err == nil {
+			meta.SourceDomain = parsed.Host
+			meta.SourceSection = sourceSection(parsed)
+		}
+	}
+	s.fillMissingMeta(&meta, body)
+	return meta, true
 }
 
 // SaveContent stores article content and images. Content is the complete
-// index.md file (front matter + markdown). If an article with the same slug
-// already exists, it returns *ErrArticleExists. Use SaveContentForce to
-// overwrite.
+// index.md file (front matter + markdown), to which the default tags are
+// added (see WithDefaultTags). If an article with the same slug already
+// exists, it returns *ErrArticleExists. Use SaveContentForce to overwrite.
 func (s *Store) SaveContent(title, content string, images []ImageFile) error {
+	return s.saveNew(title, s.addDefaultTags(content), images)
+}
+
+// saveNew is SaveContent without the default tags.
+func (s *Store) saveNew(title, content string, images []ImageFile) error {
 	slug := generateDirName(title)
 	dirPath := filepath.Join(s.basePath, "articles", slug)
 
@@ -188,8 +522,8 @@ func (s *Store) SaveContent(title, content string, images []ImageFile) error {
 		// Directory already exists — find the title of the existing article.
 		existingTitle := slug
 		if data, err := os.ReadFile(filepath.Join(dirPath, "index.md")); err == nil {
-			if t, _, _, _, _, _, _, err := parseFrontMatter(string(data)); err == nil && t != "" {
-				existingTitle = t
+			if fm, _, err := parseFrontMatter(string(data)); err == nil && fm.title != "" {
+				existingTitle = fm.title
 			}
 		}
 		return &ErrArticleExists{Slug: slug, Title: existingTitle}
@@ -198,22 +532,177 @@ func (s *Store) SaveContent(title, content string, images []ImageFile) error {
 	return s.saveContent(slug, dirPath, content, images)
 }
 
+// addDefaultTags adds the default tags (see WithDefaultTags) that content's
+// front matter doesn't already have. Content without front matter is
+// returned as is.
+func (s *Store) addDefaultTags(content string) string {
+	if len(s.defaultTags) == 0 {
+		return content
+	}
+	fm, _, err := parseFrontMatter(content)
+	if err != nil {
+		return content
+	}
+	tags := fm.tags
+	merged := tags
+	for _, tag := range s.defaultTags {
+		if tag = strings.TrimSpace(tag); !hasTag(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	if len(merged) == len(tags) {
+		return content
+	}
+	if updated, err := replaceTags(content, merged); err == nil {
+		return updated
+	}
+	return content
+}
+
+// SaveNote creates an article for the reader's own writing rather than a
+// fetched page: front matter with the title, the time saved and no source,
+// then an empty body, or the note template if there is one (see
+// WithNoteTemplate). It returns the new article's path.
+func (s *Store) SaveNote(title string) (string, error) {
+	content := s.fillTemplate(title, time.Now())
+	if err := s.SaveContent(title, content, nil); err != nil {
+		return "", err
+	}
+	return s.ArticlePath(title), nil
+}
+
+// ArticlePath returns the relative path at which SaveContent stores an
+// article with the given title.
+func (s *Store) ArticlePath(title string) string {
+	return filepath.Join("articles", generateDirName(title), "index.md")
+}
+
 // SaveContentForce stores article content and images, overwriting any existing
-// article with the same slug.
+// article with the same slug unless it's locked, when it returns
+// *ErrArticleLocked.
 func (s *Store) SaveContentForce(title, content string, images []ImageFile) error {
 	slug := generateDirName(title)
 	dirPath := filepath.Join(s.basePath, "articles", slug)
+	if data, err := os.ReadFile(filepath.Join(dirPath, "index.md")); err == nil {
+		if fm, _, err := parseFrontMatter(string(data)); err == nil && fm.locked {
+			return &ErrArticleLocked{Title: fm.title}
+		}
+	}
 	return s.saveContent(slug, dirPath, content, images)
 }
 
+// Refetch replaces the article at filePath with content fetched again from
+// its source, saved under title. The article keeps its tags, without the
+// default tags being added back, and the reader's notes and highlights. A
+// changed title moves it to its new path, unless another article is there,
+// when it returns *ErrArticleExists and leaves the old one be.
+func (s *Store) Refetch(filePath, title, content string, images []ImageFile) error {
+	old, err := s.Get(filePath)
+	if err != nil {
+		return err
+	}
+	if old.Meta.Locked {
+		return &ErrArticleLocked{Title: old.Meta.Title}
+	}
+	if tagged, err := replaceTags(content, old.Meta.Tags); err == nil {
+		content = tagged
+	}
+	newPath := s.ArticlePath(title)
+	if newPath == filePath {
+		return s.SaveContentForce(title, content, images)
+	}
+	if err := s.saveNew(title, content, images); err != nil {
+		return err
+	}
+	if filepath.Base(filePath) == "index.md" {
+		if err := copyReaderFiles(filepath.Dir(s.GetFilePath(filePath)), filepath.Dir(s.GetFilePath(newPath))); err != nil {
+			return err
+		}
+		if err := s.refresh(newPath); err != nil {
+			return err
+		}
+	}
+	return s.Delete(filePath)
+}
+
+// saveContent writes the article into a hidden staging directory and renames
+// it into place once complete, so an interrupted or failed save never leaves
+// a half-written article behind. An existing article at dirPath is replaced.
 func (s *Store) saveContent(slug, dirPath, content string, images []ImageFile) error {
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
+	s.saving.Add(1)
+	defer s.saving.Done()
+
+	articlesDir := filepath.Dir(dirPath)
+	staging, err := os.MkdirTemp(articlesDir, "."+slug+stagingInfix)
+	if err != nil {
 		return fmt.Errorf("creating article directory: %w", err)
 	}
+	content, images = relocateImages(content, images, s.imageDir)
+	if err := writeArticleDir(staging, content, images); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Chmod(staging, 0755); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("creating article directory: %w", err)
+	}
+
+	// Move any existing article aside before swapping in the new one,
+	// keeping what the reader added to it.
+	var old string
+	if _, err := os.Stat(dirPath); err == nil {
+		if err := copyReaderFiles(dirPath, staging); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+		suffix := strings.TrimPrefix(filepath.Base(staging), "."+slug+stagingInfix)
+		old = filepath.Join(articlesDir, "."+slug+replacedInfix+suffix)
+		if err := os.Rename(dirPath, old); err != nil {
+			os.RemoveAll(staging)
+			return fmt.Errorf("replacing article directory: %w", err)
+		}
+	}
+	if err := os.Rename(staging, dirPath); err != nil {
+		os.RemoveAll(staging)
+		if old != "" {
+			_ = os.Rename(old, dirPath)
+		}
+		return fmt.Errorf("finalizing article directory: %w", err)
+	}
+	if old != "" {
+		os.RemoveAll(old)
+	}
 
+	return s.refresh(filepath.Join("articles", slug, "index.md"))
+}
+
+// readerFiles are the files the reader adds alongside an article's
+// index.md, which survive it being overwritten.
+var readerFiles = []string{NotesFile, HighlightsFile}
+
+// copyReaderFiles copies the readerFiles in the article directory from, if
+// any, into to.
+func copyReaderFiles(from, to string) error {
+	for _, name := range readerFiles {
+		data, err := os.ReadFile(filepath.Join(from, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("keeping %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(to, name), data, 0644); err != nil {
+			return fmt.Errorf("keeping %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// writeArticleDir writes index.md and images into dir.
+func writeArticleDir(dir, content string, images []ImageFile) error {
 	// Write images.
 	for _, img := range images {
-		imgPath := filepath.Join(dirPath, img.Path)
+		imgPath := filepath.Join(dir, img.Path)
 		if err := os.MkdirAll(filepath.Dir(imgPath), 0755); err != nil {
 			return fmt.Errorf("creating image directory: %w", err)
 		}
@@ -223,16 +712,50 @@ func (s *Store) saveContent(slug, dirPath, content string, images []ImageFile) e
 	}
 
 	// Write index.md.
-	indexPath := filepath.Join(dirPath, "index.md")
+	indexPath := filepath.Join(dir, "index.md")
 	if err := os.WriteFile(indexPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("writing article file: %w", err)
 	}
+	return nil
+}
 
-	return s.scan()
+// Staging directory names: ".<slug>.tmp-<rand>" holds a save in progress and
+// ".<slug>.old-<rand>" holds an article being replaced.
+const (
+	stagingInfix  = ".tmp-"
+	replacedInfix = ".old-"
+)
+
+// removeStaging deletes staging directories left by interrupted saves.
+func removeStaging(articlesDir string) error {
+	entries, err := os.ReadDir(articlesDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(name, ".") {
+			continue
+		}
+		if strings.Contains(name, stagingInfix) || strings.Contains(name, replacedInfix) {
+			if err := os.RemoveAll(filepath.Join(articlesDir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Wait blocks until any in-progress saves have finished. Call it before
+// exiting so a cancelled import doesn't abandon a save mid-write.
+func (s *Store) Wait() {
+	s.saving.Wait()
 }
 
 // List returns all article metadata, sorted by saved date (newest first).
 func (s *Store) List() []ArticleMeta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	result := make([]ArticleMeta, len(s.articles))
 	copy(result, s.articles)
 	return result
@@ -246,29 +769,57 @@ func (s *Store) Get(filePath string) (*Article, error) {
 		return nil, fmt.Errorf("reading article file: %w", err)
 	}
 
-	title, author, source, saved, tags, progress, body, err := parseFrontMatter(string(content))
+	article, err := parseArticle(string(content), s.isArchiveTagged)
+	if err != nil {
+		return nil, err
+	}
+	article.Meta.FilePath = filePath
+	if info, err := os.Stat(fullPath); err == nil {
+		article.Meta.FileSize = info.Size()
+		// For directory articles that's just index.md, not the images.
+		article.Meta.sized = filepath.Base(filePath) != "index.md"
+	}
+	s.fillMissingMeta(&article.Meta, article.Content)
+	return article, nil
+}
+
+// ParseArticle parses the complete contents of an index.md, as produced by
+// the extractor, into an Article. It has no FilePath or FileSize, and is
+// archived if tagged "archived", the default archive tag.
+func ParseArticle(content string) (*Article, error) {
+	return parseArticle(content, func(tags []string) bool { return hasTag(tags, "archived") })
+}
+
+func parseArticle(content string, isArchived func(tags []string) bool) (*Article, error) {
+	fm, body, err := parseFrontMatter(content)
 	if err != nil {
 		return nil, fmt.Errorf("parsing front matter: %w", err)
 	}
 
 	meta := ArticleMeta{
-		Title:        title,
-		Author:       author,
-		SourceURL:    source,
-		SavedAt:      saved,
-		Tags:         tags,
-		Progress:     progress,
-		TotalLines:   strings.Count(string(content), "\n") + 1,
-		FilePath:     filePath,
-	}
-	if source != "" {
-		if parsed, err := url.Parse(source); err == nil {
+		Title:      fm.title,
+		Author:     fm.author,
+		SourceURL:  fm.source,
+		SavedAt:    fm.saved,
+		Tags:       fm.tags,
+		Archived:   isArchived(fm.tags),
+		Pinned:     hasTag(fm.tags, PinTag),
+		Progress:   fm.progress,
+		Order:      fm.order,
+		Locked:     fm.locked,
+		Warnings:   fm.warnings,
+		FetchTime:  fm.fetchTime,
+		TotalLines: strings.Count(content, "\n") + 1,
+	}
+	if meta.Archived {
+		meta.ArchiveNote = fm.archiveNote
+	}
+	if fm.source != "" {
+		if parsed, err := url.Parse(fm.source); err == nil {
 			meta.SourceDomain = parsed.Host
+			meta.SourceSection = sourceSection(parsed)
 		}
 	}
-	if info, err := os.Stat(fullPath); err == nil {
-		meta.FileSize = info.Size()
-	}
 
 	return &Article{
 		Meta:    meta,
@@ -276,6 +827,20 @@ func (s *Store) Get(filePath string) (*Article, error) {
 	}, nil
 }
 
+// NotesFile is the name of the reader's own notes on a directory-format
+// article, kept alongside its index.md.
+const NotesFile = "notes.md"
+
+// NotesPath returns the full path of the notes file for the article at
+// filePath, whether or not it exists yet. Flat-file articles have nowhere
+// to keep notes, so for them it returns an error.
+func (s *Store) NotesPath(filePath string) (string, error) {
+	if filepath.Base(filePath) != "index.md" {
+		return "", fmt.Errorf("notes need a directory-format article; %s is a single file", filePath)
+	}
+	return filepath.Join(filepath.Dir(s.GetFilePath(filePath)), NotesFile), nil
+}
+
 // GetFilePath returns the full file path for an article given its relative path.
 func (s *Store) GetFilePath(relPath string) string {
 	return filepath.Join(s.basePath, relPath)
@@ -298,36 +863,93 @@ func (s *Store) Delete(filePath string) error {
 		}
 	}
 
-	return s.scan()
+	return s.refresh(filePath)
 }
 
-// Search filters articles by query (matches title, author, or domain).
+// Search filters articles by query. See ParseQuery for the query syntax;
+// plain text matches title, author, domain or tags.
 func (s *Store) Search(query string) []ArticleMeta {
 	if query == "" {
 		return s.List()
 	}
 
-	query = strings.ToLower(query)
+	q := ParseQuery(query)
 	var results []ArticleMeta
+	for _, meta := range s.List() {
+		if q.Matches(meta) {
+			results = append(results, meta)
+		}
+	}
+	return results
+}
 
-	for _, meta := range s.articles {
-		if strings.Contains(strings.ToLower(meta.Title), query) ||
-			strings.Contains(strings.ToLower(meta.Author), query) ||
-			strings.Contains(strings.ToLower(meta.SourceDomain), query) ||
-			strings.Contains(strings.ToLower(strings.Join(meta.Tags, ",")), query) {
+// ListByTag returns the articles tagged tag, in List order. Unlike a tag:
+// search, the tag must match exactly (ignoring case), not as a substring of
+// another field.
+func (s *Store) ListByTag(tag string) []ArticleMeta {
+	var results []ArticleMeta
+	for _, meta := range s.List() {
+		if hasTag(meta.Tags, tag) {
 			results = append(results, meta)
 		}
 	}
+	return results
+}
 
-	sort.Slice(results, func(i, j int) bool {
-		ai, aj := results[i].IsArchived(), results[j].IsArchived()
-		if ai != aj {
-			return !ai // non-archived first
+// ListByDomain returns the articles saved from domain, in List order. The
+// domain must match the source's host exactly (ignoring case and a leading
+// "www."); subdomains don't match their parent.
+func (s *Store) ListByDomain(domain string) []ArticleMeta {
+	domain = bareHost(domain)
+	var results []ArticleMeta
+	for _, meta := range s.List() {
+		if meta.SourceDomain != "" && bareHost(meta.SourceDomain) == domain {
+			results = append(results, meta)
 		}
-		return results[i].SavedAt.After(results[j].SavedAt)
+	}
+	return results
+}
+
+// TagCount is a tag and the number of articles carrying it.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// Tags returns every tag in use with its article count, most used first and
+// then alphabetically. Tags differing only in case are counted together,
+// under the spelling seen first. The archive tag and its aliases are left
+// out; archived articles are shown with X, not filtered by tag.
+func (s *Store) Tags() []TagCount {
+	var tags []TagCount
+	index := make(map[string]int) // lowercased tag -> index into tags
+	for _, meta := range s.List() {
+		for _, tag := range meta.Tags {
+			if tag == "" || s.isArchiveTag(tag) {
+				continue
+			}
+			key := strings.ToLower(tag)
+			i, ok := index[key]
+			if !ok {
+				i = len(tags)
+				index[key] = i
+				tags = append(tags, TagCount{Tag: tag})
+			}
+			tags[i].Count++
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return strings.ToLower(tags[i].Tag) < strings.ToLower(tags[j].Tag)
 	})
+	return tags
+}
 
-	return results
+// bareHost lowercases host and strips a leading "www.".
+func bareHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
 }
 
 // Reload rescans the articles directory and refreshes the cache.
@@ -335,8 +957,20 @@ func (s *Store) Reload() error {
 	return s.scan()
 }
 
+// SetListOrder changes how articles are listed after the store is
+// created, as WithManualOrder and WithSizeOrder do (neither is the
+// default, newest first), and rescans to reorder them.
+func (s *Store) SetListOrder(manual, size bool) error {
+	s.mu.Lock()
+	s.manualOrder, s.sizeOrder = manual, size
+	s.mu.Unlock()
+	return s.scan()
+}
+
 // Count returns the total number of articles.
 func (s *Store) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return len(s.articles)
 }
 
@@ -381,15 +1015,22 @@ func slugify(s string) string {
 	return slug
 }
 
-func parseFrontMatter(content string) (title, author, source string, saved time.Time, tags []string, progress int, body string, err error) {
-	// Front matter is delimited by "---\n" at start and "---\n" to close.
-	parts := strings.SplitN(content, "---\n", 3)
-	if len(parts) < 3 || parts[0] != "" {
-		return "", "", "", time.Time{}, nil, 0, content, nil
+// parseFrontMatter splits content into the fields shelf reads from its
+// front matter and the body that follows it.
+func parseFrontMatter(content string) (fm frontMatter, body string, err error) {
+	// Front matter is delimited by "---\n" at start and "---\n" to close,
+	// or by "+++\n" lines for TOML.
+	fence, header, body, ok := splitFrontMatter(content)
+	if !ok {
+		return frontMatter{}, content, nil
+	}
+	body = strings.TrimPrefix(body, "\n")
+	if fence == tomlFence {
+		if fm, err = parseTOMLHeader(header); err != nil {
+			return frontMatter{}, "", err
+		}
+		return fm, body, nil
 	}
-
-	header := parts[1]
-	body = strings.TrimPrefix(parts[2], "\n")
 
 	for _, line := range strings.Split(header, "\n") {
 		line = strings.TrimSpace(line)
@@ -406,29 +1047,45 @@ func parseFrontMatter(content string) (title, author, source string, saved time.
 
 		switch key {
 		case "title":
-			title = value
+			fm.title = value
 		case "author":
-			author = value
+			fm.author = value
 		case "source":
-			source = value
+			fm.source = value
 		case "saved":
-			saved, err = time.Parse(time.RFC3339, value)
+			fm.saved, err = time.Parse(time.RFC3339, value)
 			if err != nil {
-				return "", "", "", time.Time{}, nil, 0, "", fmt.Errorf("parsing saved time: %w", err)
+				return frontMatter{}, "", fmt.Errorf("parsing saved time: %w", err)
 			}
 		case "tags":
 			for _, t := range strings.Split(value, ",") {
 				t = strings.TrimSpace(t)
 				if t != "" {
-					tags = append(tags, t)
+					fm.tags = append(fm.tags, t)
 				}
 			}
 		case "progress":
-			progress, _ = strconv.Atoi(strings.TrimPrefix(value, "L"))
+			fm.progress, _ = strconv.Atoi(strings.TrimPrefix(value, "L"))
+		case "order":
+			fm.order, _ = strconv.Atoi(value)
+			fm.order = max(fm.order, 0)
+		case "locked":
+			fm.locked = value == "true"
+		case "warnings":
+			for _, w := range strings.Split(value, ";") {
+				if w = strings.TrimSpace(w); w != "" {
+					fm.warnings = append(fm.warnings, w)
+				}
+			}
+		case "fetch_ms":
+			ms, _ := strconv.Atoi(value)
+			fm.fetchTime = time.Duration(max(ms, 0)) * time.Millisecond
+		case "archive_note":
+			fm.archiveNote = value
 		}
 	}
 
-	return
+	return fm, body, nil
 }
 
 func unescapeYAML(s string) string {
@@ -449,31 +1106,189 @@ func hasTag(tags []string, tag string) bool {
 	return false
 }
 
-// UpdateTags rewrites the tags line in an article's front matter on disk.
-func (s *Store) UpdateTags(filePath string, tags []string) error {
+// ArchiveTag returns the tag that marks an article as archived.
+func (s *Store) ArchiveTag() string {
+	return s.archiveTag
+}
+
+// isArchiveTag reports whether tag is the archive tag or one of its aliases.
+func (s *Store) isArchiveTag(tag string) bool {
+	return strings.EqualFold(tag, s.archiveTag) || hasTag(s.archiveAliases, tag)
+}
+
+// isArchiveTagged reports whether any of tags marks an article archived.
+func (s *Store) isArchiveTagged(tags []string) bool {
+	for _, t := range tags {
+		if s.isArchiveTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetArchived archives an article by adding the archive tag, or unarchives
+// it by removing the archive tag and any of its aliases.
+func (s *Store) SetArchived(filePath string, archived bool) error {
+	article, err := s.Get(filePath)
+	if err != nil {
+		return err
+	}
+	var newTags []string
+	for _, t := range article.Meta.Tags {
+		if !s.isArchiveTag(t) {
+			newTags = append(newTags, t)
+		}
+	}
+	if archived {
+		newTags = append(newTags, s.archiveTag)
+	}
+	if err := s.UpdateTags(filePath, newTags); err != nil {
+		return err
+	}
+	if !archived {
+		// Why it was archived no longer applies.
+		return s.SetArchiveNote(filePath, "")
+	}
+	return nil
+}
+
+// SetArchiveNote records why an article was archived as archive_note: in
+// its front matter, or removes it if note is "".
+func (s *Store) SetArchiveNote(filePath, note string) error {
 	fullPath := filepath.Join(s.basePath, filePath)
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return fmt.Errorf("reading article: %w", err)
 	}
 
-	updated, err := replaceTags(string(content), tags)
+	note = strings.Join(strings.Fields(note), " ")
+	updated, err := setField(string(content), "archive_note", func(fence string) string {
+		switch {
+		case note == "":
+			return ""
+		case fence == tomlFence:
+			return "archive_note = " + strconv.Quote(note)
+		}
+		return `archive_note: "` + strings.ReplaceAll(note, `"`, `\"`) + `"`
+	})
 	if err != nil {
 		return err
 	}
+	if updated == string(content) {
+		return nil
+	}
 
-	tmpPath := fullPath + ".tmp"
-	if err := os.WriteFile(tmpPath, []byte(updated), 0644); err != nil {
-		return fmt.Errorf("writing tmp file: %w", err)
+	return s.writeArticle(filePath, updated)
+}
+
+// SetPinned pins an article by adding PinTag, or unpins it by removing it.
+func (s *Store) SetPinned(filePath string, pinned bool) error {
+	article, err := s.Get(filePath)
+	if err != nil {
+		return err
 	}
-	if err := os.Rename(tmpPath, fullPath); err != nil {
-		return fmt.Errorf("renaming tmp file: %w", err)
+	var newTags []string
+	for _, t := range article.Meta.Tags {
+		if !strings.EqualFold(t, PinTag) {
+			newTags = append(newTags, t)
+		}
+	}
+	if pinned {
+		newTags = append(newTags, PinTag)
 	}
+	return s.UpdateTags(filePath, newTags)
+}
 
-	return s.scan()
+// SetLocked locks an article against being re-fetched or overwritten, by
+// setting locked: true in its front matter, or unlocks it.
+func (s *Store) SetLocked(filePath string, locked bool) error {
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
+	}
+
+	updated, err := setField(string(content), "locked", func(fence string) string {
+		switch {
+		case !locked:
+			return ""
+		case fence == tomlFence:
+			return "locked = true"
+		}
+		return "locked: true"
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.writeArticle(filePath, updated)
 }
 
-// UpdateProgress rewrites the progress field in an article's front matter.
+// ClearWarnings removes the extractor's warnings from an article's front
+// matter, once the reader has checked the article or doesn't mind.
+func (s *Store) ClearWarnings(filePath string) error {
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
+	}
+
+	updated, err := setField(string(content), "warnings", func(string) string { return "" })
+	if err != nil {
+		return err
+	}
+
+	return s.writeArticle(filePath, updated)
+}
+
+// FrontMatterFields returns the top-level fields of an article's front
+// matter as they are written on disk, by key, for restoring later with
+// SetFrontMatterFields.
+func (s *Store) FrontMatterFields(filePath string) (map[string]string, error) {
+	content, err := os.ReadFile(filepath.Join(s.basePath, filePath))
+	if err != nil {
+		return nil, fmt.Errorf("reading article: %w", err)
+	}
+	return frontMatterFields(string(content)), nil
+}
+
+// SetFrontMatterFields sets the given fields of an article's front matter
+// to the text FrontMatterFields returned for them, removing those given as
+// "", and leaves its other fields and body as they are.
+func (s *Store) SetFrontMatterFields(filePath string, fields map[string]string) error {
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
+	}
+	updated := string(content)
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if updated, err = setField(updated, key, func(string) string { return fields[key] }); err != nil {
+			return err
+		}
+	}
+
+	return s.writeArticle(filePath, updated)
+}
+
+// UpdateTags rewrites the tags line in an article's front matter on disk.
+func (s *Store) UpdateTags(filePath string, tags []string) error {
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
+	}
+
+	updated, err := replaceTags(string(content), tags)
+	if err != nil {
+		return err
+	}
+
+	return s.writeArticle(filePath, updated)
+}
+
+// UpdateProgress rewrites the progress field in an article's front matter;
+// zero clears it, so the article reads as unstarted.
 func (s *Store) UpdateProgress(filePath string, line int) error {
 	fullPath := filepath.Join(s.basePath, filePath)
 	content, err := os.ReadFile(fullPath)
@@ -486,6 +1301,14 @@ func (s *Store) UpdateProgress(filePath string, line int) error {
 		return err
 	}
 
+	return s.writeArticle(filePath, updated)
+}
+
+// writeArticle replaces the article at filePath with updated, by way of a
+// temporary file so that it's never left half written, and refreshes its
+// entry in the list.
+func (s *Store) writeArticle(filePath, updated string) error {
+	fullPath := filepath.Join(s.basePath, filePath)
 	tmpPath := fullPath + ".tmp"
 	if err := os.WriteFile(tmpPath, []byte(updated), 0644); err != nil {
 		return fmt.Errorf("writing tmp file: %w", err)
@@ -493,69 +1316,211 @@ func (s *Store) UpdateProgress(filePath string, line int) error {
 	if err := os.Rename(tmpPath, fullPath); err != nil {
 		return fmt.Errorf("renaming tmp file: %w", err)
 	}
+	return s.refresh(filePath)
+}
 
-	return s.scan()
+// ResetTagProgress clears the progress of every article tagged tag,
+// returning how many had any to clear.
+func (s *Store) ResetTagProgress(tag string) (int, error) {
+	reset := 0
+	for _, a := range s.ListByTag(tag) {
+		if a.Progress == 0 {
+			continue
+		}
+		if err := s.UpdateProgress(a.FilePath, 0); err != nil {
+			return reset, err
+		}
+		reset++
+	}
+	return reset, nil
 }
 
-// replaceProgress splices the progress: field in front matter text.
-func replaceProgress(content string, line int) (string, error) {
-	parts := strings.SplitN(content, "---\n", 3)
-	if len(parts) < 3 || parts[0] != "" {
-		return "", fmt.Errorf("invalid front matter")
+// RenameTag renames oldTag to newTag (matching oldTag ignoring case) on
+// every article carrying it, returning how many were changed. Articles
+// already tagged newTag keep a single copy of it. Like DeleteTag, it
+// changes every article or none.
+func (s *Store) RenameTag(oldTag, newTag string) (int, error) {
+	oldTag, newTag = strings.TrimSpace(oldTag), strings.TrimSpace(newTag)
+	switch {
+	case oldTag == "" || newTag == "":
+		return 0, fmt.Errorf("tag names can't be empty")
+	case strings.ContainsAny(newTag, ",;\n"):
+		return 0, fmt.Errorf("invalid tag %q: tags can't contain commas or semicolons", newTag)
+	case s.IsStatusTag(oldTag) || s.IsStatusTag(newTag):
+		return 0, fmt.Errorf("the %s and %s tags can't be renamed", s.archiveTag, PinTag)
+	case oldTag == newTag:
+		return 0, nil
 	}
+	return s.retag(oldTag, func(tags []string) []string {
+		var renamed []string
+		for _, t := range tags {
+			if strings.EqualFold(t, oldTag) {
+				t = newTag
+			}
+			if !hasTag(renamed, t) {
+				renamed = append(renamed, t)
+			}
+		}
+		return renamed
+	})
+}
 
-	header := parts[1]
-	body := parts[2]
+// DeleteTag removes tag (ignoring case) from every article carrying it,
+// returning how many were changed. The archive tag, its aliases and PinTag
+// say something about an article rather than what it's about, so removing
+// them en masse is refused unless force is set.
+func (s *Store) DeleteTag(tag string, force bool) (int, error) {
+	tag = strings.TrimSpace(tag)
+	switch {
+	case tag == "":
+		return 0, fmt.Errorf("tag names can't be empty")
+	case s.IsStatusTag(tag) && !force:
+		return 0, fmt.Errorf("%q marks articles %s; remove it from all of them only if forced", tag, s.statusOf(tag))
+	}
+	return s.retag(tag, func(tags []string) []string {
+		var kept []string
+		for _, t := range tags {
+			if !strings.EqualFold(t, tag) {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
 
-	newLine := fmt.Sprintf("progress: L%d\n", line)
+// IsStatusTag reports whether tag archives or pins an article, which
+// DeleteTag only removes when forced.
+func (s *Store) IsStatusTag(tag string) bool {
+	return s.isArchiveTag(tag) || strings.EqualFold(tag, PinTag)
+}
 
-	var newHeader strings.Builder
-	found := false
-	for _, l := range strings.Split(header, "\n") {
-		trimmed := strings.TrimSpace(l)
-		if strings.HasPrefix(trimmed, "progress:") {
-			newHeader.WriteString(newLine)
-			found = true
-		} else if trimmed != "" {
-			newHeader.WriteString(l + "\n")
+// statusOf returns what status tag marks an article as, for messages.
+func (s *Store) statusOf(tag string) string {
+	if s.isArchiveTag(tag) {
+		return "archived"
+	}
+	return "pinned"
+}
+
+// retag rewrites the tags of every article tagged tag with edit, returning
+// how many were rewritten. If rewriting any article fails, the ones already
+// rewritten get their old tags back, so the shelf isn't left half changed.
+func (s *Store) retag(tag string, edit func(tags []string) []string) (int, error) {
+	var done []ArticleMeta // rewritten so far, with their old tags
+	for _, a := range s.ListByTag(tag) {
+		if err := s.UpdateTags(a.FilePath, edit(a.Tags)); err != nil {
+			for _, d := range done {
+				_ = s.UpdateTags(d.FilePath, d.Tags)
+			}
+			return 0, fmt.Errorf("retagging %s: %w", a.FilePath, err)
 		}
+		done = append(done, a)
 	}
-	if !found {
-		newHeader.WriteString(newLine)
+	return len(done), nil
+}
+
+// Move moves the article at filePath delta places down the list (up, if
+// negative) among the articles grouped with it, i.e. those with the same
+// archived and pinned state, and persists the new order. Articles from the
+// top of the group down to the one moved past are numbered 1, 2, ... in
+// their new order, along with any ordered articles further down that would
+// otherwise sort above them; the rest keep no order, and stay below sorted
+// by date. It requires WithManualOrder.
+func (s *Store) Move(filePath string, delta int) error {
+	if !s.manualOrder {
+		return fmt.Errorf("articles can only be reordered with manual sorting")
 	}
+	articles := s.List()
+	i := slices.IndexFunc(articles, func(a ArticleMeta) bool { return a.FilePath == filePath })
+	if i < 0 {
+		return fmt.Errorf("no article %s", filePath)
+	}
+	moved := articles[i]
+	var group []ArticleMeta
+	from := 0
+	for _, a := range articles {
+		if a.IsArchived() == moved.IsArchived() && a.IsPinned() == moved.IsPinned() {
+			if a.FilePath == filePath {
+				from = len(group)
+			}
+			group = append(group, a)
+		}
+	}
+	to := min(max(from+delta, 0), len(group)-1)
+	if to == from {
+		return nil
+	}
+	group = slices.Insert(slices.Delete(group, from, from+1), to, moved)
 
-	return "---\n" + newHeader.String() + "---\n" + body, nil
+	for i, a := range group {
+		order := i + 1
+		if i > max(from, to) && (a.Order == 0 || a.Order > i) {
+			break
+		}
+		if a.Order != order {
+			if err := s.SetOrder(a.FilePath, order); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// replaceTags splices the tags: line in front matter text.
-func replaceTags(content string, tags []string) (string, error) {
-	parts := strings.SplitN(content, "---\n", 3)
-	if len(parts) < 3 || parts[0] != "" {
-		return "", fmt.Errorf("invalid front matter")
+// SetOrder rewrites the order field in an article's front matter; zero
+// removes it.
+func (s *Store) SetOrder(filePath string, order int) error {
+	fullPath := filepath.Join(s.basePath, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("reading article: %w", err)
 	}
 
-	header := parts[1]
-	body := parts[2]
+	updated, err := replaceOrder(string(content), order)
+	if err != nil {
+		return err
+	}
 
-	tagValue := strings.Join(tags, ", ")
-	newLine := "tags: " + tagValue + "\n"
+	return s.writeArticle(filePath, updated)
+}
 
-	var newHeader strings.Builder
-	found := false
-	for _, line := range strings.Split(header, "\n") {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "tags:") {
-			newHeader.WriteString(newLine)
-			found = true
-		} else if trimmed != "" {
-			newHeader.WriteString(line + "\n")
+// replaceOrder splices the order field in front matter text, dropping it
+// if order is zero.
+func replaceOrder(content string, order int) (string, error) {
+	return setField(content, "order", func(fence string) string {
+		switch {
+		case order <= 0:
+			return ""
+		case fence == tomlFence:
+			return fmt.Sprintf("order = %d", order)
 		}
-	}
-	if !found {
-		newHeader.WriteString(newLine)
-	}
+		return fmt.Sprintf("order: %d", order)
+	})
+}
 
-	return "---\n" + newHeader.String() + "---\n" + body, nil
+// replaceProgress splices the progress field in front matter text. Zero
+// leaves it empty, or drops it from TOML, which has no empty values.
+func replaceProgress(content string, line int) (string, error) {
+	return setField(content, "progress", func(fence string) string {
+		switch {
+		case fence == tomlFence && line <= 0:
+			return ""
+		case fence == tomlFence:
+			return fmt.Sprintf("progress = %d", line)
+		case line <= 0:
+			return "progress:"
+		}
+		return fmt.Sprintf("progress: L%d", line)
+	})
+}
+
+// replaceTags splices the tags field in front matter text.
+func replaceTags(content string, tags []string) (string, error) {
+	return setField(content, "tags", func(fence string) string {
+		if fence == tomlFence {
+			return "tags = " + tomlStrings(tags)
+		}
+		return "tags: " + strings.Join(tags, ", ")
+	})
 }
 
 func calcDirSize(dir string) int64 {
@@ -569,3 +1534,17 @@ func calcDirSize(dir string) int64 {
 	})
 	return size
 }
+
+// sourceSection returns the section of a site an article's URL is in: the
+// first segment of a path with more than one, e.g. "blog" for
+// example.com/blog/post. Numeric segments, usually years, aren't sections.
+func sourceSection(u *url.URL) string {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	if _, err := strconv.Atoi(segments[0]); err == nil {
+		return ""
+	}
+	return segments[0]
+}
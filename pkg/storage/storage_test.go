@@ -0,0 +1,2246 @@
+package storage_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func articleContent(title string) string {
+	return "---\ntitle: " + title + "\nauthor:\nsource: https://example.com/" + title +
+		"\nsaved: 2024-01-02T03:04:05Z\ntags:\nprogress:\n---\n\nBody of " + title + ".\n"
+}
+
+// pngSignature starts every PNG file; test servers put it in front of the
+// fake images they serve so they pass as images.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// listArticleDirs returns the names of all entries under articles/,
+// including hidden ones.
+func listArticleDirs(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, "articles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// TestSaveInterruptedLeavesNoPartialDirs simulates an import that is cut
+// short mid-save: one save fails partway through writing its images, and
+// another was abandoned by a killed process. Neither may leave a
+// half-written article directory behind.
+func TestSaveInterruptedLeavesNoPartialDirs(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SaveContent("kept", articleContent("kept"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second image can't be written because its parent is a file, so
+	// the save fails after the first image is already on disk.
+	images := []storage.ImageFile{
+		{Path: "images/a.png", Data: []byte("png")},
+		{Path: "images/a.png/b.png", Data: []byte("png")},
+	}
+	if err := s.SaveContent("broken", articleContent("broken"), images); err == nil {
+		t.Fatal("expected save to fail")
+	}
+	if got := listArticleDirs(t, dir); len(got) != 1 || got[0] != "kept" {
+		t.Fatalf("expected only the kept article on disk, found %v", got)
+	}
+
+	// Overwriting must not lose the existing article if the new save fails.
+	if err := s.SaveContentForce("kept", articleContent("kept"), images); err == nil {
+		t.Fatal("expected save to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles", "kept", "index.md")); err != nil {
+		t.Fatalf("existing article lost by failed overwrite: %v", err)
+	}
+
+	// A staging directory abandoned by a killed process is invisible to scan
+	// and removed the next time the store is opened.
+	abandoned := filepath.Join(dir, "articles", ".abandoned.tmp-123")
+	if err := os.MkdirAll(abandoned, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(abandoned, "index.md"), []byte("---\ntitle: half"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.Count(); n != 1 {
+		t.Fatalf("expected 1 article after reload, found %d", n)
+	}
+
+	s, err = storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+	for _, name := range listArticleDirs(t, dir) {
+		if strings.HasPrefix(name, ".") {
+			t.Fatalf("staging directory %s not cleaned up", name)
+		}
+	}
+	if n := s.Count(); n != 1 {
+		t.Fatalf("expected 1 article, found %d", n)
+	}
+}
+
+func TestSaveContentForceReplaces(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("a", articleContent("a"), []storage.ImageFile{{Path: "images/old.png", Data: []byte("x")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContentForce("a", articleContent("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles", "a", "images", "old.png")); !os.IsNotExist(err) {
+		t.Fatalf("expected old images to be replaced, stat err = %v", err)
+	}
+	if got := listArticleDirs(t, dir); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("unexpected entries after overwrite: %v", got)
+	}
+}
+
+func TestSaveContentForceKeepsNotes(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("a", articleContent("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	articleDir := filepath.Join(dir, "articles", "a")
+	for name, data := range map[string]string{
+		storage.NotesFile:      "My thoughts.\n",
+		storage.HighlightsFile: "> A passage.\n",
+	} {
+		if err := os.WriteFile(filepath.Join(articleDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Re-fetching replaces the article but not what the reader added.
+	if err := s.SaveContentForce("a", articleContent("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustRead(t, filepath.Join(articleDir, storage.NotesFile)); got != "My thoughts.\n" {
+		t.Errorf("notes after re-fetch = %q", got)
+	}
+	if got := mustRead(t, filepath.Join(articleDir, storage.HighlightsFile)); got != "> A passage.\n" {
+		t.Errorf("highlights after re-fetch = %q", got)
+	}
+	if a := s.List()[0]; !a.HasNotes {
+		t.Errorf("article lost its notes marker after re-fetch")
+	}
+}
+
+// newBenchStore creates a store with n directory-format articles, each with
+// a few images, and returns it.
+func newBenchStore(b *testing.B, n int) *storage.Store {
+	b.Helper()
+	dir := b.TempDir()
+	body := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit.\n", 200)
+	for i := 0; i < n; i++ {
+		title := "article-" + strconv.Itoa(i)
+		imagesDir := filepath.Join(dir, "articles", title, "images")
+		if err := os.MkdirAll(imagesDir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for _, name := range []string{"1.png", "2.png", "3.png"} {
+			if err := os.WriteFile(filepath.Join(imagesDir, name), make([]byte, 4096), 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+		index := filepath.Join(dir, "articles", title, "index.md")
+		if err := os.WriteFile(index, []byte(articleContent(title)+body), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	s, err := storage.New(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return s
+}
+
+func BenchmarkScan(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			s := newBenchStore(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := s.Reload(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScanAndSize measures a scan followed by sizing every article,
+// which is what scanning cost before sizes were computed lazily.
+func BenchmarkScanAndSize(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			s := newBenchStore(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := s.Reload(); err != nil {
+					b.Fatal(err)
+				}
+				for _, a := range s.List() {
+					s.ArticleSize(a)
+				}
+			}
+		})
+	}
+}
+
+// TestArticleSize checks that a directory article's size includes its
+// images, even from an Article's Meta, whose FileSize is just index.md's,
+// and that an empty flat file's measured size of 0 is taken as is.
+func TestArticleSize(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := storage.ImageFile{Path: "images/a.png", Data: make([]byte, 1000)}
+	if err := s.SaveContent("a", articleContent("a"), []storage.ImageFile{image}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "articles", "empty.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := s.Get(filepath.Join("articles", "a", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := int64(len(articleContent("a")) + len(image.Data))
+	if got := s.ArticleSize(a.Meta); got != want {
+		t.Errorf("ArticleSize(Get().Meta) = %d, want %d", got, want)
+	}
+	var found bool
+	for _, meta := range s.List() {
+		if meta.FilePath != filepath.Join("articles", "empty.md") {
+			continue
+		}
+		found = true
+		if got := s.ArticleSize(meta); got != 0 {
+			t.Errorf("ArticleSize(empty.md) = %d, want 0", got)
+		}
+	}
+	if !found {
+		t.Errorf("empty.md not listed")
+	}
+}
+
+// TestIncrementalMatchesFullScan applies saves, overwrites, tag and progress
+// updates, and deletes, and checks after each that the incrementally
+// maintained list matches what a full scan of the same directory produces.
+func TestIncrementalMatchesFullScan(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := func(title, saved string) string {
+		return strings.Replace(articleContent(title), "2024-01-02T03:04:05Z", saved, 1)
+	}
+	// A legacy flat-format article alongside directory-format ones.
+	flat := filepath.Join(dir, "articles", "legacy.md")
+	if err := os.WriteFile(flat, []byte(content("legacy", "2024-01-03T00:00:00Z")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(step string) {
+		t.Helper()
+		full, err := storage.New(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := s.List(), full.List(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: incremental list differs from full scan\n got: %+v\nwant: %+v", step, got, want)
+		}
+	}
+
+	for i, saved := range []string{"2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z", "2024-01-02T00:00:00Z", "2024-01-05T00:00:00Z"} {
+		title := "article-" + strconv.Itoa(i)
+		if err := s.SaveContent(title, content(title, saved), nil); err != nil {
+			t.Fatal(err)
+		}
+		check("save " + title)
+	}
+
+	if err := s.SaveContentForce("article-0", content("article-0", "2024-02-01T00:00:00Z"), nil); err != nil {
+		t.Fatal(err)
+	}
+	check("overwrite")
+
+	if err := s.UpdateTags(filepath.Join("articles", "article-1", "index.md"), []string{"archived"}); err != nil {
+		t.Fatal(err)
+	}
+	check("archive")
+
+	if err := s.UpdateTags(filepath.Join("articles", "legacy.md"), []string{"go", "archived"}); err != nil {
+		t.Fatal(err)
+	}
+	check("archive flat")
+
+	if err := s.UpdateTags(filepath.Join("articles", "article-1", "index.md"), nil); err != nil {
+		t.Fatal(err)
+	}
+	check("unarchive")
+
+	if err := s.UpdateProgress(filepath.Join("articles", "article-2", "index.md"), 7); err != nil {
+		t.Fatal(err)
+	}
+	check("progress")
+
+	if err := s.Delete(filepath.Join("articles", "article-3", "index.md")); err != nil {
+		t.Fatal(err)
+	}
+	check("delete")
+
+	if err := s.Delete(filepath.Join("articles", "legacy.md")); err != nil {
+		t.Fatal(err)
+	}
+	check("delete flat")
+
+	if got := s.Count(); got != 3 {
+		t.Fatalf("expected 3 articles, got %d", got)
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(title, source string, tags []string, progress int) {
+		t.Helper()
+		content := strings.Replace(articleContent(title), "https://example.com/"+title, source, 1)
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("articles", title, "index.md")
+		if err := s.UpdateTags(path, tags); err != nil {
+			t.Fatal(err)
+		}
+		if progress > 0 {
+			if err := s.UpdateProgress(path, progress); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	save("ownership", "https://blog.rust-lang.org/ownership", []string{"rust"}, 0)
+	save("lifetimes", "https://blog.rust-lang.org/lifetimes", []string{"rust"}, 4)
+	save("goroutines", "https://go.dev/goroutines", []string{"go", "deep dive"}, 0)
+	save("old-rust", "https://example.com/old", []string{"rust", "archived"}, 0)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"goroutines", "lifetimes", "old-rust", "ownership"}},
+		{"own", []string{"ownership"}},
+		{"tag:rust", []string{"lifetimes", "old-rust", "ownership"}},
+		{"tag:rust status:unread", []string{"ownership"}},
+		{"TAG:Rust Status:Reading", []string{"lifetimes"}},
+		{"status:archived", []string{"old-rust"}},
+		{"domain:rust-lang tag:rust", []string{"lifetimes", "ownership"}},
+		{"domain:go.dev own", nil},
+		{"tag:rust tag:go", nil},
+		{`tag:"Deep Dive"`, []string{"goroutines"}},
+		{storage.TagQuery("deep dive") + " go", []string{"goroutines"}},
+		{"tag:deep dive", nil},
+		{"status:bogus", nil},
+		{"http:", nil},
+	} {
+		var got []string
+		for _, a := range s.Search(tc.query) {
+			got = append(got, a.Title)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Search(%q) = %v, want %v", tc.query, got, tc.want)
+		}
+	}
+
+	// Each result of free text says which field it matched first.
+	for _, tc := range []struct {
+		query string
+		want  map[string]storage.Field
+	}{
+		{"rust", map[string]storage.Field{"old-rust": storage.FieldTitle, "ownership": storage.FieldDomain, "lifetimes": storage.FieldDomain}},
+		{"tag:go go", map[string]storage.Field{"goroutines": storage.FieldTitle}},
+		{"tag:go", map[string]storage.Field{"goroutines": ""}},
+	} {
+		q := storage.ParseQuery(tc.query)
+		got := make(map[string]storage.Field)
+		for _, a := range s.Search(tc.query) {
+			got[a.Title] = q.MatchedField(a)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("fields matched by %q = %v, want %v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestListByTagAndDomain(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(title, source string, tags []string) {
+		t.Helper()
+		content := strings.Replace(articleContent(title), "https://example.com/"+title, source, 1)
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateTags(filepath.Join("articles", title, "index.md"), tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("ownership", "https://blog.rust-lang.org/ownership", []string{"Rust"})
+	save("lifetimes", "https://www.rust-lang.org/lifetimes", []string{"rust", "archived"})
+	save("rustic", "https://rust-lang.org/rustic", []string{"rustic"})
+	save("goroutines", "https://go.dev/goroutines", []string{"go"})
+
+	titles := func(articles []storage.ArticleMeta) []string {
+		var got []string
+		for _, a := range articles {
+			got = append(got, a.Title)
+		}
+		sort.Strings(got)
+		return got
+	}
+	for _, tc := range []struct {
+		tag  string
+		want []string
+	}{
+		{"rust", []string{"lifetimes", "ownership"}},
+		{"RUST", []string{"lifetimes", "ownership"}},
+		{"rus", nil},
+		{"archived", []string{"lifetimes"}},
+		{"", nil},
+	} {
+		if got := titles(s.ListByTag(tc.tag)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ListByTag(%q) = %v, want %v", tc.tag, got, tc.want)
+		}
+	}
+	for _, tc := range []struct {
+		domain string
+		want   []string
+	}{
+		{"rust-lang.org", []string{"lifetimes", "rustic"}},
+		{"www.Rust-Lang.org", []string{"lifetimes", "rustic"}},
+		{"blog.rust-lang.org", []string{"ownership"}},
+		{"lang.org", nil},
+		{"", nil},
+	} {
+		if got := titles(s.ListByDomain(tc.domain)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ListByDomain(%q) = %v, want %v", tc.domain, got, tc.want)
+		}
+	}
+
+	// Tags counts case-insensitively and leaves out the archive tag.
+	wantTags := []storage.TagCount{{Tag: "Rust", Count: 2}, {Tag: "go", Count: 1}, {Tag: "rustic", Count: 1}}
+	if got := s.Tags(); !reflect.DeepEqual(got, wantTags) {
+		t.Errorf("Tags() = %v, want %v", got, wantTags)
+	}
+
+	// Results keep List's order.
+	var want []string
+	for _, a := range s.List() {
+		if a.Title != "goroutines" && a.Title != "ownership" {
+			want = append(want, a.Title)
+		}
+	}
+	var got []string
+	for _, a := range s.ListByDomain("rust-lang.org") {
+		got = append(got, a.Title)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByDomain order = %v, want %v", got, want)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(title string, tags []string) {
+		t.Helper()
+		if err := s.SaveContent(title, articleContent(title), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateTags(filepath.Join("articles", title, "index.md"), tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("channels", []string{"golang", "concurrency"})
+	save("generics", []string{"Golang", "go", "types"})
+	save("borrowck", []string{"rust"})
+	tags := func(title string) []string {
+		t.Helper()
+		for _, a := range s.List() {
+			if a.Title == title {
+				return a.Tags
+			}
+		}
+		t.Fatalf("%q not listed", title)
+		return nil
+	}
+
+	n, err := s.RenameTag("golang", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("RenameTag changed %d articles, want 2", n)
+	}
+	// The tag keeps its place; an article already tagged go keeps one copy.
+	if got, want := tags("channels"), []string{"go", "concurrency"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("channels tagged %q, want %q", got, want)
+	}
+	if got, want := tags("generics"), []string{"go", "types"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("generics tagged %q, want %q", got, want)
+	}
+	if got, want := tags("borrowck"), []string{"rust"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("borrowck tagged %q, want %q", got, want)
+	}
+	if content := mustRead(t, filepath.Join(dir, "articles", "generics", "index.md")); !strings.Contains(content, "\ntags: go, types\n") {
+		t.Errorf("generics front matter not rewritten:\n%s", content)
+	}
+
+	// Renaming a tag nothing carries changes nothing.
+	if n, err := s.RenameTag("golang", "go"); err != nil || n != 0 {
+		t.Errorf("second rename: %d changed, err %v; want 0", n, err)
+	}
+	// Tags that mean something to the shelf, or that wouldn't parse back,
+	// are refused.
+	for _, tc := range [][2]string{{"archived", "done"}, {"go", "pinned"}, {"go", "go, rust"}, {"go", " "}} {
+		if _, err := s.RenameTag(tc[0], tc[1]); err == nil {
+			t.Errorf("RenameTag(%q, %q) succeeded, want an error", tc[0], tc[1])
+		}
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(title string, tags []string) {
+		t.Helper()
+		if err := s.SaveContent(title, articleContent(title), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateTags(filepath.Join("articles", title, "index.md"), tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("channels", []string{"todo", "go"})
+	save("generics", []string{"Todo", "archived"})
+	save("borrowck", []string{"rust", "pinned"})
+	tags := func(title string) []string {
+		t.Helper()
+		for _, a := range s.List() {
+			if a.Title == title {
+				return a.Tags
+			}
+		}
+		t.Fatalf("%q not listed", title)
+		return nil
+	}
+
+	n, err := s.DeleteTag("todo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("DeleteTag changed %d articles, want 2", n)
+	}
+	if got, want := tags("channels"), []string{"go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("channels tagged %q, want %q", got, want)
+	}
+	if got, want := tags("generics"), []string{"archived"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("generics tagged %q, want %q", got, want)
+	}
+	if n, err := s.DeleteTag("todo", false); err != nil || n != 0 {
+		t.Errorf("second delete: %d changed, err %v; want 0", n, err)
+	}
+
+	// Status tags need forcing.
+	for _, tag := range []string{"archived", "Pinned"} {
+		if _, err := s.DeleteTag(tag, false); err == nil {
+			t.Errorf("DeleteTag(%q) succeeded without force", tag)
+		}
+	}
+	if !s.List()[0].IsPinned() {
+		t.Fatalf("borrowck unpinned by a refused delete")
+	}
+	if n, err := s.DeleteTag("pinned", true); err != nil || n != 1 {
+		t.Fatalf("forced delete: %d changed, err %v; want 1", n, err)
+	}
+	if got, want := tags("borrowck"), []string{"rust"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("borrowck tagged %q after forcing, want %q", got, want)
+	}
+}
+
+func TestResetProgress(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct {
+		title    string
+		tags     []string
+		progress int
+	}{
+		{"ownership", []string{"rust"}, 12},
+		{"lifetimes", []string{"rust"}, 0},
+		{"goroutines", []string{"go"}, 5},
+	} {
+		if err := s.SaveContent(a.title, articleContent(a.title), nil); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("articles", a.title, "index.md")
+		if err := s.UpdateTags(path, a.tags); err != nil {
+			t.Fatal(err)
+		}
+		if a.progress > 0 {
+			if err := s.UpdateProgress(path, a.progress); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	progress := func(title string) int {
+		t.Helper()
+		a, err := s.Get(filepath.Join("articles", title, "index.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.Meta.Progress
+	}
+
+	n, err := s.ResetTagProgress("Rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || progress("ownership") != 0 || progress("goroutines") != 5 {
+		t.Errorf("after resetting rust: reset %d, ownership at %d, goroutines at %d", n, progress("ownership"), progress("goroutines"))
+	}
+
+	path := filepath.Join("articles", "goroutines", "index.md")
+	if err := s.UpdateProgress(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\nprogress:\n") {
+		t.Errorf("progress not cleared:\n%s", data)
+	}
+	for _, a := range s.List() {
+		if a.Progress != 0 {
+			t.Errorf("%s still at line %d", a.Title, a.Progress)
+		}
+	}
+}
+
+func TestSourceSection(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, want := range map[string]string{
+		"https://example.com/blog/post":          "blog",
+		"https://example.com/docs/guide/intro/":  "docs",
+		"https://example.com/post":               "",
+		"https://example.com/2024/05/post":       "",
+		"https://example.com/caf%C3%A9/post?x=1": "café",
+	} {
+		name := fmt.Sprintf("article-%d", len(s.List()))
+		content := strings.Replace(articleContent(name), "https://example.com/"+name, title, 1)
+		if err := s.SaveContent(name, content, nil); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("articles", name, "index.md")
+		article, err := s.Get(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := article.Meta.SourceSection; got != want {
+			t.Errorf("%s: Get section = %q, want %q", title, got, want)
+		}
+		for _, a := range s.List() {
+			if a.FilePath == path && a.SourceSection != want {
+				t.Errorf("%s: List section = %q, want %q", title, a.SourceSection, want)
+			}
+		}
+	}
+}
+
+func TestSaveNote(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := s.SaveNote(`Notes: "on" Go`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, err := s.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := article.Meta
+	if meta.Title != `Notes: "on" Go` || meta.SourceURL != "" || meta.SourceDomain != "" || meta.SavedAt.IsZero() {
+		t.Errorf("meta = %+v", meta)
+	}
+	if strings.TrimSpace(article.Content) != "" {
+		t.Errorf("content = %q, want empty", article.Content)
+	}
+	if _, err := s.SaveNote(`Notes: "on" Go`); !errors.As(err, new(*storage.ErrArticleExists)) {
+		t.Errorf("saving the same title again: err = %v", err)
+	}
+}
+
+func TestNoteTemplate(t *testing.T) {
+	src := t.TempDir()
+	imported := filepath.Join(src, "c.md")
+	if err := os.WriteFile(imported, []byte("---\ntitle: Imported\nauthor: Jane\ntags: go\n---\n\nText.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "---\ntitle: {{title}}\nsaved: {{date}}\ntags: journal\nmood:\n---\n\n# {{title}}\n\nWritten {{date}}.\n"
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithNoteTemplate(tmpl))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := s.SaveNote(`Day: "one"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := s.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	today := time.Now().Format("2006-01-02")
+	if note.Meta.Title != `Day: "one"` || !slices.Equal(note.Meta.Tags, []string{"journal"}) || note.Meta.SavedAt.Format("2006-01-02") != today {
+		t.Errorf("note meta = %+v", note.Meta)
+	}
+	if want := "# Day: \"one\"\n\nWritten " + today + ".\n"; note.Content != want {
+		t.Errorf("note body = %q, want %q", note.Content, want)
+	}
+
+	// Imported files take the template's front matter, but not its body.
+	if err := s.ImportFile(imported); err != nil {
+		t.Fatal(err)
+	}
+	article, err := s.Get(filepath.Join("articles", "imported", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Meta.Author != "Jane" || !slices.Equal(article.Meta.Tags, []string{"journal", "go"}) || article.Content != "Text.\n" {
+		t.Errorf("imported = %+v, content %q", article.Meta, article.Content)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "articles", "imported", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\nmood:\n") {
+		t.Errorf("template field dropped:\n%s", data)
+	}
+}
+
+func TestImportFile(t *testing.T) {
+	src := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		p := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	write("figs/plot one.png", pngSignature+"plot")
+	write("fake.png", "not an image")
+	plain := write("my-notes.md", "Some notes.\n\n![plot](figs/plot%20one.png) ![fake](fake.png) ![gone](missing.png)\n")
+	headed := write("b.markdown", "Intro line.\n\n# Headed Title\n\nText.\n")
+	withMeta := write("c.md", "---\ntitle: Kept Title\nauthor: Jane\nsource: https://example.com/c\nsaved: 2024-01-02T03:04:05Z\ntags: go, notes\n---\n\n# Other Heading\n")
+	page := write("page.html", "<html><title>Page</title><body><p>Hi</p></body></html>")
+
+	s, err := storage.New(t.TempDir(), storage.WithHTMLConverter(func(html string) (string, string, error) {
+		return "Converted Page", "Converted.\n", nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{plain, headed, withMeta, page} {
+		if err := s.ImportFile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	byTitle := make(map[string]storage.ArticleMeta)
+	for _, a := range s.List() {
+		byTitle[a.Title] = a
+	}
+	for _, title := range []string{"my notes", "Headed Title", "Kept Title", "Converted Page"} {
+		if _, ok := byTitle[title]; !ok {
+			t.Errorf("no article titled %q; have %v", title, byTitle)
+		}
+	}
+	if a := byTitle["Kept Title"]; a.Author != "Jane" || a.SourceURL != "https://example.com/c" ||
+		!a.SavedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || strings.Join(a.Tags, ",") != "go,notes" {
+		t.Errorf("front matter not kept: %+v", a)
+	}
+
+	article, err := s.Get(byTitle["my notes"].FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "![plot](images/plotone.png) ![fake](fake.png) ![gone](missing.png)"
+	if !strings.Contains(article.Content, want) {
+		t.Errorf("content missing %q:\n%s", want, article.Content)
+	}
+	dir := filepath.Dir(s.GetFilePath(byTitle["my notes"].FilePath))
+	if data, err := os.ReadFile(filepath.Join(dir, "images", "plotone.png")); err != nil || string(data) != pngSignature+"plot" {
+		t.Errorf("image not copied: %q, %v", data, err)
+	}
+
+	if err := s.ImportFile(write("data.csv", "a,b\n")); err == nil {
+		t.Errorf("importing a .csv succeeded")
+	}
+	if err := s.ImportFile(plain); !errors.As(err, new(*storage.ErrArticleExists)) {
+		t.Errorf("importing twice: err = %v", err)
+	}
+}
+
+func TestImages(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("figures") +
+		"![Figure 1](images/fig-1.png)\n" +
+		"![](images/fig-2.jpg \"caption\")\n" +
+		"![again](images/fig-1.png)\n" +
+		"![chart](https://cdn.example.com/chart.svg)\n" +
+		"[not an image](images/fig-3.png)\n"
+	if err := s.SaveContent("figures", content, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("articles", "figures", "index.md")
+	refs, err := s.Images(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.ImageRef{
+		{Alt: "Figure 1", Path: "images/fig-1.png"},
+		{Alt: "", Path: "images/fig-2.jpg"},
+		{Alt: "chart", Path: "https://cdn.example.com/chart.svg"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Fatalf("Images() = %+v, want %+v", refs, want)
+	}
+	if refs[0].IsRemote() || !refs[2].IsRemote() {
+		t.Fatalf("unexpected IsRemote for %+v", refs)
+	}
+	if got, want := s.ImagePath(path, refs[0]), filepath.Join(dir, "articles", "figures", "images", "fig-1.png"); got != want {
+		t.Fatalf("ImagePath() = %s, want %s", got, want)
+	}
+}
+
+func TestRemoteImageReport(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, images := range map[string]string{
+		"local":  "![a](images/a.png)\n",
+		"mixed":  "![a](images/a.png)\n![b](https://cdn.example.com/b.png)\n![c](//cdn.example.com/c.png)\n![b again](https://cdn.example.com/b.png)\n",
+		"remote": "![x](http://example.com/x.gif)\n",
+		"none":   "[a link](https://example.com/)\n",
+	} {
+		if err := s.SaveContent(title, articleContent(title)+images, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := s.RemoteImageReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string][2]int)
+	for _, r := range report {
+		got[r.Article.Title] = [2]int{r.Count, r.Total}
+	}
+	want := map[string][2]int{"mixed": {2, 3}, "remote": {1, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("RemoteImageReport() = %v, want %v (remote, total)", got, want)
+	}
+}
+
+func TestLocalizeImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(pngSignature + r.URL.Path))
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("figures") +
+		"![local](images/fig.png)\n" +
+		"![a](" + srv.URL + "/a/fig.png)\n" +
+		"![b](" + srv.URL + "/b.jpg \"title\")\n" +
+		"![a again](" + srv.URL + "/a/fig.png)\n" +
+		"![gone](" + srv.URL + "/missing.png)\n"
+	images := []storage.ImageFile{{Path: "images/fig.png", Data: []byte("existing")}}
+	if err := s.SaveContent("figures", content, images); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "figures", "index.md")
+
+	fetched, failed, err := s.LocalizeImages(path, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched != 2 || len(failed) != 1 || failed[0].URL != srv.URL+"/missing.png" {
+		t.Fatalf("fetched %d, failed %v; want 2 fetched, missing.png failed", fetched, failed)
+	}
+	refs, err := s.Images(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.ImageRef{
+		{Alt: "local", Path: "images/fig.png"},
+		{Alt: "a", Path: "images/fig-2.png"},
+		{Alt: "b", Path: "images/b.jpg"},
+		{Alt: "gone", Path: "http://" + host + "/missing.png"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Fatalf("Images() = %+v, want %+v", refs, want)
+	}
+	for name, want := range map[string]string{"fig.png": "existing", "fig-2.png": pngSignature + "/a/fig.png", "b.jpg": pngSignature + "/b.jpg"} {
+		data, err := os.ReadFile(filepath.Join(dir, "articles", "figures", "images", name))
+		if err != nil || string(data) != want {
+			t.Errorf("images/%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+
+	// Only the failed image is left to fetch.
+	if fetched, failed, err := s.LocalizeImages(path, srv.Client()); err != nil || fetched != 0 || len(failed) != 1 {
+		t.Fatalf("second run: fetched %d, failed %v, err %v", fetched, failed, err)
+	}
+}
+
+// TestLocalizeImagesAfterInterruption reruns LocalizeImages over what an
+// interrupted run leaves: one image downloaded, one half-written, and the
+// markdown still pointing at the remote copies.
+func TestLocalizeImagesAfterInterruption(t *testing.T) {
+	var mu sync.Mutex
+	gets := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			gets[r.URL.Path]++
+			mu.Unlock()
+		}
+		w.Write([]byte(pngSignature + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("figures") +
+		"![a](" + srv.URL + "/a.png)\n" +
+		"![b](" + srv.URL + "/b.png)\n"
+	if err := s.SaveContent("figures", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "figures", "index.md")
+	imageDir := filepath.Join(dir, "articles", "figures", "images")
+	if err := os.MkdirAll(imageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"a.png": pngSignature + "/a.png", "b.png.part": pngSignature} {
+		if err := os.WriteFile(filepath.Join(imageDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fetched, failed, err := s.LocalizeImages(path, srv.Client())
+	if err != nil || fetched != 2 || len(failed) != 0 {
+		t.Fatalf("LocalizeImages() = %d, %v, %v; want 2 fetched", fetched, failed, err)
+	}
+	if want := map[string]int{"/b.png": 1}; !reflect.DeepEqual(gets, want) {
+		t.Errorf("downloads = %v, want %v; a.png was already there", gets, want)
+	}
+	refs, err := s.Images(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []storage.ImageRef{{Alt: "a", Path: "images/a.png"}, {Alt: "b", Path: "images/b.png"}}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("Images() = %+v, want %+v", refs, want)
+	}
+	var names []string
+	entries, err := os.ReadDir(imageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{"a.png", "b.png"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("image directory holds %v, want %v", names, want)
+	}
+}
+
+func TestLocalizeImagesRejectsNonImages(t *testing.T) {
+	var realPNG bytes.Buffer
+	if err := png.Encode(&realPNG, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/real.png":
+			w.Write(realPNG.Bytes())
+		case "/logo.svg":
+			w.Write([]byte(`<?xml version="1.0"?> <svg xmlns="http://www.w3.org/2000/svg"></svg>`))
+		default:
+			// A 200 with an error page, as some servers answer.
+			w.Write([]byte("<!DOCTYPE html><html><body>Please sign in</body></html>"))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("figures") +
+		"![real](" + srv.URL + "/real.png)\n" +
+		"![svg](" + srv.URL + "/logo.svg)\n" +
+		"![html](" + srv.URL + "/error.png)\n"
+	if err := s.SaveContent("figures", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "figures", "index.md")
+
+	fetched, failed, err := s.LocalizeImages(path, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched != 2 || len(failed) != 1 || failed[0].URL != srv.URL+"/error.png" {
+		t.Fatalf("fetched %d, failed %v; want 2 fetched, error.png failed", fetched, failed)
+	}
+	refs, err := s.Images(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.ImageRef{
+		{Alt: "real", Path: "images/real.png"},
+		{Alt: "svg", Path: "images/logo.svg"},
+		{Alt: "html", Path: srv.URL + "/error.png"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("Images() = %+v, want %+v", refs, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles", "figures", "images", "error.png")); !os.IsNotExist(err) {
+		t.Errorf("error page saved as an image (stat error %v)", err)
+	}
+}
+
+func TestPruneImages(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("figures") +
+		"![plain](images/plain.png)\n" +
+		"![dotted](./images/dotted.png)\n" +
+		"![escaped](images/with%20space.png)\n" +
+		"![bracketed](<images/other space.png>)\n" +
+		"![query](images/query.png?v=2)\n" +
+		"![case](images/Case.PNG)\n" +
+		"<img alt=\"html\" src=\"images/html.png\">\n" +
+		"![ref][fig]\n\n[fig]: images/refdef.png\n" +
+		"![remote](https://example.com/images/orphan.png)\n"
+	var files []storage.ImageFile
+	for _, name := range []string{
+		"plain.png", "dotted.png", "with space.png", "other space.png", "query.png",
+		"case.png", "html.png", "refdef.png", "orphan.png", "nested/orphan.gif",
+	} {
+		files = append(files, storage.ImageFile{Path: "images/" + name, Data: []byte("12345")})
+	}
+	if err := s.SaveContent("figures", content, files); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "figures", "index.md")
+
+	orphans, size, err := s.OrphanedImages(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"images/nested/orphan.gif", "images/orphan.png"}
+	if !reflect.DeepEqual(orphans, want) || size != 10 {
+		t.Fatalf("OrphanedImages() = %v, %d bytes; want %v, 10 bytes", orphans, size, want)
+	}
+
+	removed, freed, err := s.PruneImages(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 || freed != 10 {
+		t.Fatalf("PruneImages() = %d, %d bytes; want 2, 10 bytes", removed, freed)
+	}
+	for _, f := range files {
+		_, err := os.Stat(filepath.Join(dir, "articles", "figures", f.Path))
+		if orphaned := strings.Contains(f.Path, "orphan"); orphaned != os.IsNotExist(err) {
+			t.Errorf("%s: stat error %v after pruning", f.Path, err)
+		}
+	}
+}
+
+func TestCustomImageDir(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(pngSignature + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithImageDir("assets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Extracted articles name their images images/...; saving moves them.
+	content := articleContent("figures") +
+		"![fig](images/fig.png)\n" +
+		"<img src=\"images/html.png\">\n" +
+		"![remote](" + srv.URL + "/remote.png)\n" +
+		"![elsewhere](" + srv.URL + "/images/x.png)\n"
+	images := []storage.ImageFile{
+		{Path: "images/fig.png", Data: []byte("fig")},
+		{Path: "images/html.png", Data: []byte("html")},
+	}
+	if err := s.SaveContent("figures", content, images); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "figures", "index.md")
+	articleDir := filepath.Join(dir, "articles", "figures")
+	if _, err := os.Stat(filepath.Join(articleDir, "images")); !os.IsNotExist(err) {
+		t.Errorf("images/ exists (err %v); want everything in assets/", err)
+	}
+
+	if _, _, err := s.LocalizeImages(path, srv.Client()); err != nil {
+		t.Fatal(err)
+	}
+	refs, err := s.Images(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.ImageRef{
+		{Alt: "fig", Path: "assets/fig.png"},
+		{Alt: "remote", Path: "assets/remote.png"},
+		{Alt: "elsewhere", Path: "assets/x.png"}, // remote images/ paths aren't moved on save
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("Images() = %+v, want %+v", refs, want)
+	}
+	for name, want := range map[string]string{"fig.png": "fig", "html.png": "html", "remote.png": pngSignature + "/remote.png"} {
+		data, err := os.ReadFile(filepath.Join(articleDir, "assets", name))
+		if err != nil || string(data) != want {
+			t.Errorf("assets/%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+
+	// Every image is referenced, so none count as orphaned.
+	if orphans, _, err := s.OrphanedImages(path); err != nil || len(orphans) != 0 {
+		t.Errorf("OrphanedImages() = %v, %v; want none", orphans, err)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("annotated", articleContent("annotated"), nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "annotated", "index.md")
+	notes, err := s.NotesPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "articles", "annotated", storage.NotesFile); notes != want {
+		t.Fatalf("NotesPath() = %s, want %s", notes, want)
+	}
+	hasNotes := func() bool {
+		t.Helper()
+		if err := s.Reload(); err != nil {
+			t.Fatal(err)
+		}
+		return s.List()[0].HasNotes
+	}
+	if hasNotes() {
+		t.Fatalf("HasNotes before notes.md exists")
+	}
+	if err := os.WriteFile(notes, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hasNotes() {
+		t.Fatalf("HasNotes with an empty notes.md")
+	}
+	if err := os.WriteFile(notes, []byte("My thoughts.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !hasNotes() {
+		t.Fatalf("HasNotes false with notes.md written")
+	}
+
+	// Deleting the article takes its notes with it.
+	if err := s.Delete(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(notes); !os.IsNotExist(err) {
+		t.Fatalf("notes.md survived Delete: %v", err)
+	}
+
+	// Flat-file articles have nowhere to keep notes.
+	if _, err := s.NotesPath(filepath.Join("articles", "flat.md")); err == nil {
+		t.Fatalf("NotesPath() succeeded for a flat-file article")
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := articleContent("marked") +
+		"A ==first passage== in a paragraph.\n" +
+		"\n" +
+		"A ==second passage\nacross lines== and a == spaced == pair.\n" +
+		"\n" +
+		"```\nif a ==b== c {}\n```\n" +
+		"\n" +
+		"Heading\n=======\n" +
+		"Last ==x=y== one.\n"
+	if err := s.SaveContent("marked", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "marked", "index.md")
+
+	got, err := s.Highlights(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.Highlight{
+		{Line: 11, Text: "first passage"},
+		{Line: 13, Text: "second passage across lines"},
+		{Line: 22, Text: "x=y"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Highlights() = %+v, want %+v", got, want)
+	}
+
+	if err := s.SyncHighlights(path); err != nil {
+		t.Fatal(err)
+	}
+	highlightsPath := filepath.Join(dir, "articles", "marked", storage.HighlightsFile)
+	data, err := os.ReadFile(highlightsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFile := "# Highlights from marked\n\n> first passage\n\n> second passage across lines\n\n> x=y\n"
+	if string(data) != wantFile {
+		t.Fatalf("highlights.md = %q, want %q", data, wantFile)
+	}
+
+	// Unmarking everything removes the file.
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(articleContent("marked")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SyncHighlights(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(highlightsPath); !os.IsNotExist(err) {
+		t.Fatalf("highlights.md left behind: %v", err)
+	}
+}
+
+// TestCustomArchiveTag checks that a configured archive tag, rather than the
+// literal "archived", drives IsArchived, list order and status:archived.
+func TestCustomArchiveTag(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithArchiveTag("done"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Newest first: the archived article would lead the list if the tag
+	// didn't move it into the archived group.
+	for i, title := range []string{"read-it", "old-convention", "new"} {
+		saved := "2024-01-0" + strconv.Itoa(i+1) + "T00:00:00Z"
+		content := strings.Replace(articleContent(title), "2024-01-02T03:04:05Z", saved, 1)
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.UpdateTags(filepath.Join("articles", "old-convention", "index.md"), []string{"archived"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetArchived(filepath.Join("articles", "new", "index.md"), true); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, a := range s.List() {
+		order = append(order, a.Title)
+		if a.IsArchived() != (a.Title == "new") {
+			t.Errorf("%s: IsArchived() = %t", a.Title, a.IsArchived())
+		}
+	}
+	if want := []string{"old-convention", "read-it", "new"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("List() order = %v, want %v", order, want)
+	}
+	if got := s.Search("status:archived"); len(got) != 1 || got[0].Title != "new" {
+		t.Fatalf("status:archived = %v", got)
+	}
+	if got := s.List()[2].Tags; !reflect.DeepEqual(got, []string{"done"}) {
+		t.Fatalf("archived tags = %v, want [done]", got)
+	}
+
+	if err := s.SetArchived(filepath.Join("articles", "new", "index.md"), false); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.List()[0]; got.Title != "new" || got.IsArchived() || len(got.Tags) != 0 {
+		t.Fatalf("after unarchiving: %+v", got)
+	}
+}
+
+func TestNoFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, "articles", rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("dropped.md", "Intro.\n\n# Hand Written\n\nText.\n")
+	write(filepath.Join("reading_list", "index.md"), "Just text.\n")
+
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		filepath.Join("articles", "dropped.md"):               "Hand Written",
+		filepath.Join("articles", "reading_list", "index.md"): "reading list",
+	}
+	for _, a := range s.List() {
+		if a.Title != want[a.FilePath] || !a.SavedAt.Equal(modTime) {
+			t.Errorf("%s: title %q, saved %v; want %q, %v", a.FilePath, a.Title, a.SavedAt, want[a.FilePath], modTime)
+		}
+		full, err := s.Get(a.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if full.Meta.Title != a.Title || !full.Meta.SavedAt.Equal(a.SavedAt) {
+			t.Errorf("%s: Get gave %q, %v; List gave %q, %v", a.FilePath, full.Meta.Title, full.Meta.SavedAt, a.Title, a.SavedAt)
+		}
+	}
+	if got := s.Count(); got != 2 {
+		t.Errorf("expected 2 articles, got %d", got)
+	}
+}
+
+func TestFrontMatterFences(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithManualOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	articles := map[string]string{
+		"yaml": "---\ntitle: YAML Post\nauthor: Jane\nsource: https://example.com/yaml\nsaved: 2024-01-02T03:04:05Z\ntags: go\nprogress:\n---\n\nBody.\n",
+		"toml": "+++\ntitle = \"TOML Post\"\nauthors = [\"Jane\"]\ndate = 2024-01-02T03:04:05Z\ntags = [\n  \"go\",\n]\ndraft = false\n\n[params]\nsource = \"elsewhere\"\n+++\n\nBody.\n",
+	}
+	for name, content := range articles {
+		if err := s.SaveContent(name, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, content := range articles {
+		path := filepath.Join("articles", name, "index.md")
+		fence := content[:4]
+		check := func(when string, wantTags []string, wantProgress, wantOrder int) {
+			t.Helper()
+			a, err := s.Get(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			meta := a.Meta
+			if meta.Title != strings.ToUpper(name)+" Post" || meta.Author != "Jane" ||
+				!meta.SavedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) ||
+				!slices.Equal(meta.Tags, wantTags) || meta.Progress != wantProgress || meta.Order != wantOrder {
+				t.Errorf("%s %s: got %+v", name, when, meta)
+			}
+			if a.Content != "Body.\n" {
+				t.Errorf("%s %s: body = %q", name, when, a.Content)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), fence) || strings.Count(string(data), fence) != 2 {
+				t.Errorf("%s %s: fence not kept:\n%s", name, when, data)
+			}
+		}
+
+		check("as saved", []string{"go"}, 0, 0)
+		if err := s.UpdateTags(path, []string{"go", "later"}); err != nil {
+			t.Fatal(err)
+		}
+		check("after tagging", []string{"go", "later"}, 0, 0)
+		if err := s.UpdateProgress(path, 3); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetOrder(path, 2); err != nil {
+			t.Fatal(err)
+		}
+		check("after reading", []string{"go", "later"}, 3, 2)
+		if err := s.UpdateProgress(path, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateTags(path, nil); err != nil {
+			t.Fatal(err)
+		}
+		check("after resetting", nil, 0, 2)
+	}
+
+	// New TOML fields go above any table, where they stay top-level.
+	data, err := os.ReadFile(filepath.Join(dir, "articles", "toml", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "order = 2\n[params]\nsource = \"elsewhere\"\n+++\n"; !strings.Contains(string(data), want) {
+		t.Errorf("TOML front matter:\n%s\nwant it to contain:\n%s", data, want)
+	}
+}
+
+func TestPinned(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saved oldest first, so unpinned they list in reverse.
+	for i, title := range []string{"old", "middle", "archived", "new"} {
+		content := strings.Replace(articleContent(title), "2024-01-02", "2024-01-0"+strconv.Itoa(i+1), 1)
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(title string) string { return filepath.Join("articles", title, "index.md") }
+	if err := s.SetArchived(path("archived"), true); err != nil {
+		t.Fatal(err)
+	}
+	titles := func(articles []storage.ArticleMeta) []string {
+		var got []string
+		for _, a := range articles {
+			got = append(got, a.Title)
+		}
+		return got
+	}
+
+	// Pinned articles come first, whatever their age, but an archived one
+	// stays in the archived group.
+	for _, title := range []string{"old", "archived"} {
+		if err := s.SetPinned(path(title), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := titles(s.List()), []string{"old", "new", "middle", "archived"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if got, want := titles(s.Search("d")), []string{"old", "middle", "archived"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() = %v, want %v", got, want)
+	}
+
+	// Pins survive a rescan, and unpinning removes just the pin tag.
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.List()[0]; got.Title != "old" || !got.IsPinned() {
+		t.Errorf("after reload: first is %q, pinned %t", got.Title, got.IsPinned())
+	}
+	if err := s.SetPinned(path("archived"), false); err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Get(path("archived"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Meta.IsPinned() || !reflect.DeepEqual(a.Meta.Tags, []string{"archived"}) {
+		t.Errorf("after unpinning: pinned=%t tags=%v", a.Meta.IsPinned(), a.Meta.Tags)
+	}
+}
+
+func TestLocked(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("curated", articleContent("curated"), nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "curated", "index.md")
+	if err := s.SetLocked(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if !s.List()[0].Locked {
+		t.Fatal("not locked after SetLocked")
+	}
+	if a, err := s.Get(path); err != nil || !a.Meta.Locked {
+		t.Fatalf("Get: locked %v, err %v", a != nil && a.Meta.Locked, err)
+	}
+
+	var lockedErr *storage.ErrArticleLocked
+	err = s.SaveContentForce("curated", articleContent("curated"), nil)
+	if !errors.As(err, &lockedErr) || lockedErr.Title != "curated" {
+		t.Fatalf("overwriting a locked article: err = %v", err)
+	}
+
+	if err := s.SetLocked(path, false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.List()[0].Locked || strings.Contains(string(data), "locked") {
+		t.Errorf("still locked after unlocking:\n%s", data)
+	}
+	if err := s.SaveContentForce("curated", articleContent("curated"), nil); err != nil {
+		t.Errorf("overwriting an unlocked article: %v", err)
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Replace(articleContent("patchy"), "progress:\n",
+		"progress:\nwarnings: \"1 image couldn't be downloaded; the article may be incomplete\"\n", 1)
+	if err := s.SaveContent("patchy", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1 image couldn't be downloaded", "the article may be incomplete"}
+	if got := s.List()[0].Warnings; !slices.Equal(got, want) {
+		t.Fatalf("warnings = %q, want %q", got, want)
+	}
+
+	path := filepath.Join("articles", "patchy", "index.md")
+	if err := s.ClearWarnings(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.List()[0].Warnings) > 0 || strings.Contains(string(data), "warnings") {
+		t.Errorf("warnings left after clearing:\n%s", data)
+	}
+}
+
+func TestSizeOrder(t *testing.T) {
+	dir := t.TempDir()
+	// A small flat file saved most recently, and an older directory-format
+	// article whose image makes it the largest.
+	flat := strings.Replace(articleContent("small"), "2024-01-02", "2024-06-01", 1)
+	if err := os.MkdirAll(filepath.Join(dir, "articles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "articles", "small.md"), []byte(flat), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := storage.ImageFile{Path: "images/photo.png", Data: []byte(pngSignature + strings.Repeat("x", 4096))}
+	if err := setup.SaveContent("photos", articleContent("photos"), []storage.ImageFile{image}); err != nil {
+		t.Fatal(err)
+	}
+	if got := setup.List()[0].Title; got != "small" {
+		t.Fatalf("by date, first article = %q, want the newer small", got)
+	}
+
+	s, err := storage.New(dir, storage.WithSizeOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := s.List()
+	if list[0].Title != "photos" || list[1].Title != "small" {
+		t.Errorf("by size, order = %q, %q; want photos first", list[0].Title, list[1].Title)
+	}
+	if list[0].FileSize <= int64(len(image.Data)) {
+		t.Errorf("directory size %d doesn't include its image", list[0].FileSize)
+	}
+	if got, want := s.TotalSize(), list[0].FileSize+list[1].FileSize; got != want {
+		t.Errorf("TotalSize = %d, want %d", got, want)
+	}
+}
+
+func TestExportArticle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "articles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "articles", "flat.md"), []byte(articleContent("flat")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := storage.ImageFile{Path: "images/photo.png", Data: []byte(pngSignature + "photo")}
+	if err := s.SaveContent("photos", articleContent("photos"), []storage.ImageFile{image}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := t.TempDir()
+	for _, path := range []string{filepath.Join("articles", "photos", "index.md"), filepath.Join("articles", "flat.md")} {
+		if _, err := s.ExportArticle(path, out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]string{
+		"photos/index.md":         articleContent("photos"),
+		"photos/images/photo.png": pngSignature + "photo",
+		"flat.md":                 articleContent("flat"),
+	} {
+		if got, err := os.ReadFile(filepath.Join(out, name)); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := s.ExportArticle(filepath.Join("articles", "flat.md"), out); err == nil {
+		t.Errorf("exporting over an earlier copy succeeded")
+	}
+}
+
+func TestManualOrder(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithManualOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saved oldest first, so they start out listed in reverse.
+	for i, title := range []string{"a", "b", "c", "d", "pinned"} {
+		content := strings.Replace(articleContent(title), "2024-01-02", "2024-01-0"+strconv.Itoa(i+1), 1)
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(title string) string { return filepath.Join("articles", title, "index.md") }
+	if err := s.SetPinned(path("pinned"), true); err != nil {
+		t.Fatal(err)
+	}
+	list := func() string {
+		var got []string
+		for _, a := range s.List() {
+			entry := a.Title
+			if a.Order > 0 {
+				entry += "=" + strconv.Itoa(a.Order)
+			}
+			got = append(got, entry)
+		}
+		return strings.Join(got, " ")
+	}
+	if got, want := list(), "pinned d c b a"; got != want {
+		t.Fatalf("List() = %q, want %q", got, want)
+	}
+
+	for _, step := range []struct {
+		title string
+		delta int
+		want  string
+	}{
+		// Only the articles down to the one moved past are numbered.
+		{"b", -1, "pinned d=1 b=2 c=3 a"},
+		// Moving an unordered article up numbers it in place.
+		{"a", -2, "pinned d=1 a=2 b=3 c=4"},
+		{"d", 2, "pinned a=1 b=2 d=3 c=4"},
+		// The pinned article stays above the rest, and moving past the
+		// ends of the group does nothing.
+		{"a", -1, "pinned a=1 b=2 d=3 c=4"},
+		{"c", 5, "pinned a=1 b=2 d=3 c=4"},
+	} {
+		if err := s.Move(path(step.title), step.delta); err != nil {
+			t.Fatal(err)
+		}
+		if got := list(); got != step.want {
+			t.Errorf("after moving %s by %d: List() = %q, want %q", step.title, step.delta, got, step.want)
+		}
+	}
+
+	// The order is kept in front matter, so survives a rescan.
+	reopened, err := storage.New(dir, storage.WithManualOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = reopened
+	if got, want := list(), "pinned a=1 b=2 d=3 c=4"; got != want {
+		t.Errorf("after reopening: List() = %q, want %q", got, want)
+	}
+
+	// Without manual sorting, order: is ignored.
+	byDate, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := byDate.Move(path("c"), -1); err == nil {
+		t.Error("Move() without manual sorting succeeded")
+	}
+	s = byDate
+	if got, want := list(), "pinned d=3 c=4 b=2 a=1"; got != want {
+		t.Errorf("sorted by date: List() = %q, want %q", got, want)
+	}
+}
+
+func TestArchiveAliases(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithArchiveAliases("done", "Read"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged := map[string][]string{
+		"plain":    {"go"},
+		"archived": {"go", "archived"},
+		"done":     {"done"},
+		"read":     {"read", "rust"},
+		"reading":  {"reading"}, // not an alias, just similar
+	}
+	for title, tags := range tagged {
+		if err := s.SaveContent(title, articleContent(title), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateTags(filepath.Join("articles", title, "index.md"), tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archived := map[string]bool{"archived": true, "done": true, "read": true}
+	list := s.List()
+	for i, a := range list {
+		if a.IsArchived() != archived[a.Title] {
+			t.Errorf("%s: IsArchived() = %t", a.Title, a.IsArchived())
+		}
+		// The archived group comes last.
+		if i > 0 && list[i-1].IsArchived() && !a.IsArchived() {
+			t.Errorf("%s listed after archived %s", a.Title, list[i-1].Title)
+		}
+	}
+
+	// Unarchiving removes every archive tag; archiving adds the canonical one.
+	path := filepath.Join("articles", "read", "index.md")
+	if err := s.SetArchived(path, false); err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Meta.IsArchived() || !reflect.DeepEqual(a.Meta.Tags, []string{"rust"}) {
+		t.Fatalf("after unarchiving: archived=%t tags=%v", a.Meta.IsArchived(), a.Meta.Tags)
+	}
+	if err := s.SetArchived(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if a, err = s.Get(path); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Meta.IsArchived() || !reflect.DeepEqual(a.Meta.Tags, []string{"rust", "archived"}) {
+		t.Fatalf("after archiving: archived=%t tags=%v", a.Meta.IsArchived(), a.Meta.Tags)
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir, storage.WithDefaultTags("inbox", "unread"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := func(title string) []string {
+		a, err := s.Get(filepath.Join("articles", title, "index.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.Meta.Tags
+	}
+
+	// New articles get the default tags, after any they're saved with, and
+	// without doubling one they already have.
+	if err := s.SaveContent("plain", articleContent("plain"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tags("plain"); !reflect.DeepEqual(got, []string{"inbox", "unread"}) {
+		t.Errorf("new article tags = %v, want [inbox unread]", got)
+	}
+	content := strings.Replace(articleContent("tagged"), "tags:", "tags: go, Inbox", 1)
+	if err := s.SaveContent("tagged", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tags("tagged"); !reflect.DeepEqual(got, []string{"go", "Inbox", "unread"}) {
+		t.Errorf("tagged article tags = %v, want [go Inbox unread]", got)
+	}
+
+	// Re-fetching an article, once the reader has sorted it, doesn't add
+	// them back.
+	path := filepath.Join("articles", "plain", "index.md")
+	if err := s.UpdateTags(path, []string{"go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refetch(path, "plain", articleContent("plain"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tags("plain"); !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("re-fetched article tags = %v, want [go]", got)
+	}
+
+	// Nor when the re-fetched title moves it.
+	if err := s.Refetch(path, "renamed", articleContent("renamed"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tags("renamed"); !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("re-fetched and renamed article tags = %v, want [go]", got)
+	}
+	if _, err := s.Get(path); err == nil {
+		t.Errorf("old path still listed after the title changed")
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, a := range []struct{ name, title, source, tags, body string }{
+		{"url-1", "First", "https://www.example.com/post/?utm_source=feed", "rust", "One."},
+		{"url-2", "Second", "http://example.com/post#intro", "go, rust", "Two."},
+		{"title-1", "Hello, World!", "https://a.com/1", "", "Three."},
+		{"title-2", "hello world", "https://www.a.com/2", "", "Four."},
+		{"title-3", "Hello world", "https://b.com/3", "", "Other site."},
+		{"chain-1", "Alpha", "https://c.com/1", "", "Same  text\nhere."},
+		{"chain-2", "Beta", "https://d.com/2", "", "Same text here."},
+		{"chain-3", "Beta.", "https://e.com/3", "", "Different."},
+		{"solo", "Solo", "https://example.com/post/other", "", "Five."},
+	} {
+		content := "---\ntitle: " + a.title + "\nsource: " + a.source +
+			"\nsaved: 2024-01-0" + strconv.Itoa(i+1) + "T00:00:00Z\ntags: " + a.tags + "\n---\n\n" + a.body + "\n"
+		if err := s.SaveContent(a.name, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join("articles", name, "index.md") }
+
+	groups, err := s.FindDuplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, g := range groups {
+		var names []string
+		for _, a := range g.Articles {
+			names = append(names, filepath.Base(filepath.Dir(a.FilePath)))
+		}
+		got = append(got, strings.Join(names, " ")+" ("+strings.Join(g.Matched, ", ")+")")
+	}
+	want := []string{
+		"url-1 url-2 (url)",
+		"title-1 title-2 (title)",
+		"chain-1 chain-2 (content)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates() = %q, want %q", got, want)
+	}
+
+	// Merging keeps the chosen article with the union of the tags, and
+	// moves the rest out of the list into the trash.
+	if err := s.MergeDuplicates(path("url-1"), []string{path("url-2")}); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.Get(path("url-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"rust", "go"}; !reflect.DeepEqual(kept.Meta.Tags, want) {
+		t.Errorf("kept tags = %v, want %v", kept.Meta.Tags, want)
+	}
+	for _, a := range s.List() {
+		if a.FilePath == path("url-2") {
+			t.Errorf("merged article %s still listed", a.FilePath)
+		}
+	}
+	trashed, err := filepath.Glob(filepath.Join(dir, storage.TrashDir, "*-url-2", "index.md"))
+	if err != nil || len(trashed) != 1 {
+		t.Errorf("url-2 not in trash: %v %v", trashed, err)
+	}
+	if groups, err := s.FindDuplicates(); err != nil || len(groups) != 2 {
+		t.Errorf("after merging: %d groups, %v", len(groups), err)
+	}
+}
+
+// TestStatusTagsUnlisted checks that archiving or pinning an article the
+// store hasn't listed yet, such as before its scan, keeps the tags it has
+// on disk.
+func TestStatusTagsUnlisted(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("post", articleContent("post"), nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "post", "index.md")
+	if err := s.UpdateTags(path, []string{"go", "rust"}); err != nil {
+		t.Fatal(err)
+	}
+
+	unscanned, err := storage.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unscanned.SetArchived(path, true); err != nil {
+		t.Fatal(err)
+	}
+	a, err := unscanned.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"go", "rust", "archived"}; !reflect.DeepEqual(a.Meta.Tags, want) {
+		t.Errorf("tags = %v, want %v", a.Meta.Tags, want)
+	}
+
+	unscanned, err = storage.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unscanned.SetPinned(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if a, err = unscanned.Get(path); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"go", "rust", "archived", storage.PinTag}; !reflect.DeepEqual(a.Meta.Tags, want) {
+		t.Errorf("tags after pinning = %v, want %v", a.Meta.Tags, want)
+	}
+	if err := unscanned.SetArchived(filepath.Join("articles", "gone", "index.md"), true); err == nil {
+		t.Errorf("archiving a missing article succeeded")
+	}
+	if err := unscanned.SetPinned(filepath.Join("articles", "gone", "index.md"), true); err == nil {
+		t.Errorf("pinning a missing article succeeded")
+	}
+}
+
+func TestArchiveNote(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tomlContent := "+++\ntitle = \"notes\"\ntags = [\"go\"]\n+++\n\nBody.\n"
+	if err := s.SaveContent("notes", tomlContent, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("essay", articleContent("essay"), nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		filepath.Join("articles", "essay", "index.md"),
+		filepath.Join("articles", "notes", "index.md"),
+	} {
+		meta := func() storage.ArticleMeta {
+			t.Helper()
+			for _, a := range s.List() {
+				if a.FilePath == path {
+					return a
+				}
+			}
+			t.Fatalf("%s not listed", path)
+			return storage.ArticleMeta{}
+		}
+
+		if err := s.SetArchived(path, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetArchiveNote(path, `read, "not useful"`); err != nil {
+			t.Fatal(err)
+		}
+		if a := meta(); !a.IsArchived() || a.ArchiveNote != `read, "not useful"` {
+			t.Fatalf("%s: archived %v, note %q", path, a.IsArchived(), a.ArchiveNote)
+		}
+		if a, err := s.Get(path); err != nil || a.Meta.ArchiveNote != `read, "not useful"` {
+			t.Fatalf("%s: Get: note %q, err %v", path, a.Meta.ArchiveNote, err)
+		}
+
+		// Unarchiving drops the note.
+		if err := s.SetArchived(path, false); err != nil {
+			t.Fatal(err)
+		}
+		if data := mustRead(t, filepath.Join(dir, path)); strings.Contains(data, "archive_note") || meta().ArchiveNote != "" {
+			t.Errorf("%s: note kept after unarchiving:\n%s", path, data)
+		}
+	}
+}
+
+func TestUndoHelpers(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("essay", articleContent("essay"), nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("articles", "essay", "index.md")
+
+	// Fields saved before a change restore it, keeping the body and the
+	// other fields as they are now.
+	before, err := s.FrontMatterFields(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetArchived(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateProgress(path, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(strings.Replace(mustRead(t, filepath.Join(dir, path)), "Body of", "Edited body of", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetFrontMatterFields(path, map[string]string{"tags": before["tags"]}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer("Body of", "Edited body of", "progress:", "progress: L7").Replace(articleContent("essay"))
+	if got := mustRead(t, filepath.Join(dir, path)); got != want {
+		t.Errorf("after restoring front matter:\n%s\nwant:\n%s", got, want)
+	}
+	if s.List()[0].IsArchived() {
+		t.Errorf("still archived after restoring front matter")
+	}
+
+	// A trashed article is moved back where it was.
+	trashed, err := s.Trash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.List()) != 0 || !strings.HasPrefix(trashed, storage.TrashDir+string(filepath.Separator)) {
+		t.Fatalf("after trashing: %d articles, trashed to %q", len(s.List()), trashed)
+	}
+	if err := s.Untrash(trashed, path); err != nil {
+		t.Fatal(err)
+	}
+	if list := s.List(); len(list) != 1 || list[0].FilePath != path {
+		t.Fatalf("after untrashing: %v", list)
+	}
+	if _, err := os.Stat(filepath.Join(dir, trashed)); !os.IsNotExist(err) {
+		t.Errorf("still in the trash: %v", err)
+	}
+}
+
+func mustRead(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFetchTime(t *testing.T) {
+	s, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	yaml := strings.Replace(articleContent("yaml"), "progress:\n", "progress:\nfetch_ms: 1500\n", 1)
+	toml := "+++\ntitle = \"toml\"\nfetch_ms = 250\n+++\n\nBody.\n"
+	for title, content := range map[string]string{"yaml": yaml, "toml": toml, "untimed": articleContent("untimed")} {
+		if err := s.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]time.Duration{"yaml": 1500 * time.Millisecond, "toml": 250 * time.Millisecond, "untimed": 0}
+	for _, a := range s.List() {
+		if a.FetchTime != want[a.Title] {
+			t.Errorf("%s: fetch time %v, want %v", a.Title, a.FetchTime, want[a.Title])
+		}
+	}
+}
+
+func TestMigrateFlat(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "articles", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "articles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "---\ntitle: Legacy\nsource: https://example.com/legacy\nsaved: 2024-01-02T03:04:05Z\ntags: go\nprogress: L12\n---\n\nBody.\n"
+	write("legacy.md", legacy)
+	write("clash.md", articleContent("Clash"))
+	s, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveContent("clash", articleContent("clash"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	byTitle := func(title string) storage.ArticleMeta {
+		t.Helper()
+		for _, a := range s.List() {
+			if a.Title == title {
+				return a
+			}
+		}
+		t.Fatalf("%q not listed", title)
+		return storage.ArticleMeta{}
+	}
+	if a := byTitle("Legacy"); !a.IsFlat() {
+		t.Fatalf("%s not flat", a.FilePath)
+	}
+
+	newPath, err := s.MigrateFlat(filepath.Join("articles", "legacy.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("articles", "legacy", "index.md"); newPath != want {
+		t.Errorf("migrated to %s, want %s", newPath, want)
+	}
+	// The file moves as it is, front matter and all.
+	if got := mustRead(t, filepath.Join(dir, newPath)); got != legacy {
+		t.Errorf("migrated article:\n%s\nwant:\n%s", got, legacy)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles", "legacy.md")); !os.IsNotExist(err) {
+		t.Errorf("flat file left behind: %v", err)
+	}
+	a := byTitle("Legacy")
+	if a.IsFlat() || a.FilePath != newPath || a.Progress != 12 || a.SourceURL != "https://example.com/legacy" || len(a.Tags) != 1 {
+		t.Errorf("after migrating: %+v", a)
+	}
+	if _, err := s.NotesPath(a.FilePath); err != nil {
+		t.Errorf("migrated article can't hold notes: %v", err)
+	}
+	if names := listArticleDirs(t, dir); slices.ContainsFunc(names, func(n string) bool { return strings.HasPrefix(n, ".") }) {
+		t.Errorf("staging left in articles/: %v", names)
+	}
+
+	// A directory already using the slug is left alone, as is the flat file.
+	var exists *storage.ErrArticleExists
+	if _, err := s.MigrateFlat(filepath.Join("articles", "clash.md")); !errors.As(err, &exists) || exists.Title != "clash" {
+		t.Errorf("migrating onto an existing directory: %v", err)
+	}
+	if !byTitle("Clash").IsFlat() {
+		t.Errorf("clashing flat file moved")
+	}
+	if _, err := s.MigrateFlat(newPath); err == nil {
+		t.Errorf("migrating a directory-format article succeeded")
+	}
+	if got := s.Count(); got != 3 {
+		t.Errorf("%d articles after migrating, want 3", got)
+	}
+}
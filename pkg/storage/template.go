@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultNoteTemplate is the starting content of a note or imported file
+// without WithNoteTemplate.
+const defaultNoteTemplate = "---\ntitle: {{title}}\nauthor:\nsource:\nsaved: {{date}}\ntags:\nprogress:\n---\n\n"
+
+// WithNoteTemplate sets the content new notes (SaveNote) start from, and
+// the front matter files brought in by ImportFile are given, e.g. to add
+// default tags or fields of the reader's own. {{title}} and {{date}} are
+// replaced by the article's title and the time it was saved: quoted and in
+// RFC 3339 in the front matter, and as written and as a plain date in the
+// body. A template without front matter is given shelf's usual one.
+func WithNoteTemplate(tmpl string) Option {
+	return func(s *Store) {
+		s.noteTemplate = tmpl
+	}
+}
+
+// fillTemplate returns the note template with its placeholders replaced.
+func (s *Store) fillTemplate(title string, saved time.Time) string {
+	tmpl := s.noteTemplate
+	if tmpl == "" {
+		tmpl = defaultNoteTemplate
+	}
+	fence, header, body, ok := splitFrontMatter(tmpl)
+	if !ok {
+		_, header, _, _ = splitFrontMatter(defaultNoteTemplate)
+		fence, body = yamlFence, "\n"+tmpl
+	}
+	header = strings.NewReplacer(
+		"{{title}}", fmt.Sprintf("%q", title),
+		"{{date}}", saved.Format(time.RFC3339),
+	).Replace(header)
+	body = strings.NewReplacer(
+		"{{title}}", title,
+		"{{date}}", saved.Format("2006-01-02"),
+	).Replace(body)
+	return fence + "\n" + header + fence + "\n" + body
+}
@@ -0,0 +1,141 @@
+// Package termimg renders images inline in terminals that support a
+// graphics protocol: the Kitty graphics protocol, or iTerm2's inline image
+// escape (also understood by WezTerm).
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoders for image.Decode
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol.
+type Protocol int
+
+const (
+	// None means the terminal can't display images inline.
+	None Protocol = iota
+	// Kitty is the Kitty graphics protocol.
+	Kitty
+	// ITerm is iTerm2's inline image protocol.
+	ITerm
+)
+
+// String returns the protocol's name.
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case ITerm:
+		return "iterm"
+	default:
+		return "none"
+	}
+}
+
+// Detect returns the graphics protocol supported by the current terminal,
+// judged from the environment. Inside tmux escapes are not passed through,
+// so Detect reports None there.
+func Detect() Protocol {
+	return detect(os.Getenv)
+}
+
+func detect(getenv func(string) string) Protocol {
+	if getenv("TMUX") != "" {
+		return None
+	}
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", getenv("TERM") == "xterm-kitty":
+		return Kitty
+	case getenv("TERM_PROGRAM") == "iTerm.app", getenv("TERM_PROGRAM") == "WezTerm",
+		getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm
+	}
+	return None
+}
+
+// kittyChunk is the maximum base64 payload per Kitty escape sequence.
+const kittyChunk = 4096
+
+// Encode returns the escape sequence that draws an image scaled to fit in
+// cols×rows terminal cells at the cursor position. The cursor is left where
+// it was, so callers reserve the rows below it themselves. data may be PNG,
+// JPEG or GIF; Kitty only accepts PNG directly, so other formats are
+// re-encoded.
+func Encode(p Protocol, data []byte, cols, rows int) (string, error) {
+	switch p {
+	case Kitty:
+		if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return "", fmt.Errorf("decoding image: %w", err)
+			}
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return "", fmt.Errorf("encoding png: %w", err)
+			}
+			data = buf.Bytes()
+		}
+		return encodeKitty(data, cols, rows), nil
+	case ITerm:
+		return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1;doNotMoveCursor=1:%s\a",
+			len(data), cols, rows, base64.StdEncoding.EncodeToString(data)), nil
+	default:
+		return "", fmt.Errorf("terminal does not support inline images")
+	}
+}
+
+// EncodeFile is like Encode but reads the image from path.
+func EncodeFile(p Protocol, path string, cols, rows int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading image: %w", err)
+	}
+	return Encode(p, data, cols, rows)
+}
+
+// encodeKitty transmits and displays PNG data, split into chunks as the
+// protocol requires. q=2 suppresses the terminal's replies, which would
+// otherwise arrive as input.
+func encodeKitty(data []byte, cols, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(data)
+	var sb strings.Builder
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(kittyChunk, len(payload))]
+		payload = payload[len(chunk):]
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return sb.String()
+}
+
+// Clear returns the escape sequence that removes images drawn with p.
+// iTerm2 images are ordinary cells and are cleared by overwriting them.
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
+
+// Placeholder is the text shown in place of an image when the terminal
+// can't display it.
+func Placeholder(alt string) string {
+	if alt == "" {
+		return "[image]"
+	}
+	return "[image: " + alt + "]"
+}
@@ -0,0 +1,91 @@
+package termimg_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/jpeg"
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/irfansharif/shelf/pkg/termimg"
+)
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want termimg.Protocol
+	}{
+		{map[string]string{}, termimg.None},
+		{map[string]string{"TERM": "xterm-256color"}, termimg.None},
+		{map[string]string{"TERM": "xterm-kitty"}, termimg.Kitty},
+		{map[string]string{"KITTY_WINDOW_ID": "1"}, termimg.Kitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, termimg.ITerm},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, termimg.ITerm},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app", "TMUX": "/tmp/tmux-501/default,1,0"}, termimg.None},
+	} {
+		for _, k := range []string{"TMUX", "TERM", "KITTY_WINDOW_ID", "TERM_PROGRAM", "LC_TERMINAL"} {
+			t.Setenv(k, tc.env[k])
+		}
+		if got := termimg.Detect(); got != tc.want {
+			t.Errorf("Detect() with %v = %s, want %s", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeKitty(t *testing.T) {
+	// A JPEG is re-encoded as PNG; its size forces several chunks.
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	rng := rand.New(rand.NewSource(1))
+	for i := range img.Pix {
+		img.Pix[i] = byte(rng.Intn(256))
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	out, err := termimg.Encode(termimg.Kitty, buf.Bytes(), 40, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := regexp.MustCompile("\x1b_G([^;]*);([^\x1b]*)\x1b\\\\").FindAllStringSubmatch(out, -1)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	var payload strings.Builder
+	for i, c := range chunks {
+		more := "m=1"
+		if i == len(chunks)-1 {
+			more = "m=0"
+		}
+		if !strings.HasSuffix(c[1], more) {
+			t.Errorf("chunk %d control %q, want suffix %q", i, c[1], more)
+		}
+		if len(c[2]) > 4096 {
+			t.Errorf("chunk %d has %d bytes of payload", i, len(c[2]))
+		}
+		payload.WriteString(c[2])
+	}
+	if want := "a=T,f=100,q=2,C=1,c=40,r=10,m=1"; chunks[0][1] != want {
+		t.Errorf("first chunk control %q, want %q", chunks[0][1], want)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+		t.Errorf("payload is not a PNG")
+	}
+}
+
+func TestEncodeUnsupported(t *testing.T) {
+	if _, err := termimg.Encode(termimg.None, []byte("\x89PNG"), 1, 1); err == nil {
+		t.Fatal("expected an error for None")
+	}
+	if _, err := termimg.Encode(termimg.Kitty, []byte("<svg/>"), 1, 1); err == nil {
+		t.Fatal("expected an error for an undecodable image")
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checkpointMsg asks for the reading position vim last checkpointed to be
+// saved as progress.
+type checkpointMsg struct{}
+
+// checkpointProgress saves the position vim/nvim writes every checkpoint
+// while an article is open in a tmux pane, so a crash of shelf or the
+// editor loses at most that much reading. Outside tmux shelf is suspended
+// while the editor runs, and the last checkpoint is picked up when it
+// exits.
+func (m Model) checkpointProgress() tea.Cmd {
+	return tea.Tick(m.checkpoint, func(time.Time) tea.Msg {
+		return checkpointMsg{}
+	})
+}
+
+// handleCheckpoint saves the latest checkpoint, and keeps checking while
+// an editor pane is open.
+func (m Model) handleCheckpoint() (tea.Model, tea.Cmd) {
+	if m.checkpoint <= 0 || (m.tmuxPaneID == "" && len(m.tmuxPanes) == 0) {
+		m.checkpointing = false
+		return m, nil
+	}
+	m.savePositionFromFile()
+	return m, m.checkpointProgress()
+}
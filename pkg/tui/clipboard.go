@@ -0,0 +1,16 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+)
+
+// copyToClipboard writes text to the system clipboard (pbcopy on macOS,
+// xclip/xsel/wl-copy on Linux).
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf("copying to clipboard: %w", err)
+	}
+	return nil
+}
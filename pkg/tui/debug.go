@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+)
+
+// debugHeaders are the response headers shown by the debug view, chosen for
+// diagnosing bot blocks, paywalls, redirects and caching.
+var debugHeaders = []string{
+	"Content-Type", "Content-Length", "Content-Encoding", "Server", "Location",
+	"Cache-Control", "Age", "X-Cache", "CF-Ray", "CF-Cache-Status",
+	"Retry-After", "WWW-Authenticate", "X-Robots-Tag",
+}
+
+// debugProbedMsg carries the result of fetching a URL for the debug view.
+type debugProbedMsg struct {
+	url    string
+	result *extractor.ProbeResult
+	err    error
+}
+
+// probeURL fetches url in the background and shows what came back. Only
+// available with SHELF_DEBUG=1.
+func (m Model) probeURL(url string) (tea.Model, tea.Cmd) {
+	if url == "" {
+		m.statusMsg = m.msgs.text("status.debug_no_url")
+		return m, nil
+	}
+	m.statusMsg = m.msgs.format("status.debugging", url)
+	ext := m.extract
+	return m, func() tea.Msg {
+		result, err := ext.Probe(url)
+		return debugProbedMsg{url: url, result: result, err: err}
+	}
+}
+
+// debugTarget is the URL the debug key inspects: the last one that failed
+// to extract, or else the selected article's source.
+func (m Model) debugTarget() string {
+	if m.debugURL != "" {
+		return m.debugURL
+	}
+	if m.cursor < len(m.articles) {
+		return m.articles[m.cursor].SourceURL
+	}
+	return ""
+}
+
+// handleDebugProbed shows the probe's report in the scrollable preview,
+// returning to whichever screen was showing.
+func (m Model) handleDebugProbed(msg debugProbedMsg) (tea.Model, tea.Cmd) {
+	if m.state != stateList && m.state != stateImportFailures {
+		return m, nil // moved on in the meantime
+	}
+	m.statusMsg = ""
+	return m.showPreview(m.msgs.format("view.debug_title", msg.url), formatProbe(msg.result, msg.err)), nil
+}
+
+// formatProbe renders a probe result as plain text.
+func formatProbe(result *extractor.ProbeResult, err error) string {
+	var sb strings.Builder
+	if err != nil {
+		fmt.Fprintf(&sb, "Error: %v\n\n", err)
+	}
+	if result == nil {
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "GET %s\n", result.URL)
+	for _, r := range result.Redirects {
+		fmt.Fprintf(&sb, "  → %s\n", r)
+	}
+	if result.Status == "" {
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "\nStatus: %s (%s)\n\n", result.Status, result.Elapsed.Round(1e6))
+	for _, h := range debugHeaders {
+		if v := result.Header.Values(h); len(v) > 0 {
+			fmt.Fprintf(&sb, "%s: %s\n", h, strings.Join(v, ", "))
+		}
+	}
+	if n := len(result.Header.Values("Set-Cookie")); n > 0 {
+		fmt.Fprintf(&sb, "Set-Cookie: %d cookies\n", n)
+	}
+	fmt.Fprintf(&sb, "\nBody (first %d bytes):\n\n%s\n", len(result.Body), result.Body)
+	return sb.String()
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+)
+
+// lockedError refuses to re-fetch or overwrite a locked article.
+type lockedError struct {
+	text string
+}
+
+func (e *lockedError) Error() string { return e.text }
+
+// errLocked returns a lockedError saying how to unlock the article.
+func (m Model) errLocked() error {
+	return &lockedError{text: m.msgs.format("status.article_locked", m.keys.Lock.Help().Key)}
+}
+
+// fetchError is an error fetching or extracting an article, which retrying
+// may fix.
+type fetchError struct {
+	err error
+}
+
+func (e *fetchError) Error() string { return e.err.Error() }
+func (e *fetchError) Unwrap() error { return e.err }
+
+// saveError is an error writing an article to disk, which retrying the
+// fetch won't fix.
+type saveError struct {
+	err error
+}
+
+func (e *saveError) Error() string { return e.err.Error() }
+func (e *saveError) Unwrap() error { return e.err }
+
+// errorText returns the status line text for err, with advice on what to
+// do about fetch and save errors.
+func (msgs messages) errorText(err error) string {
+	var fetchErr *fetchError
+	var saveErr *saveError
+	switch {
+	case errors.As(err, &saveErr) && (errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)):
+		return msgs.format("status.save_denied", saveErr.err)
+	case errors.As(err, &saveErr) && errors.Is(err, syscall.ENOSPC):
+		return msgs.format("status.save_no_space", saveErr.err)
+	case errors.As(err, &saveErr):
+		return msgs.format("status.save_failed", saveErr.err)
+	case errors.As(err, &fetchErr):
+		return msgs.format("status.fetch_failed", fetchErr.err)
+	}
+	return msgs.format("status.error", err)
+}
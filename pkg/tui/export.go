@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportedMsg reports that one article of an export has been copied.
+type exportedMsg struct {
+	err error
+	gen uint64
+}
+
+// exportView copies every article in the list as it's currently filtered
+// (by search, saved search, tag and archive visibility) into a new folder
+// under the data directory's exports/, one article at a time so progress
+// can be shown and the export stopped.
+func (m Model) exportView() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 {
+		return m, nil
+	}
+
+	m.exportPaths = m.exportPaths[:0]
+	for _, a := range m.articles {
+		m.exportPaths = append(m.exportPaths, a.FilePath)
+	}
+	m.exportDir = filepath.Join(m.exportRoot, time.Now().Format("2006-01-02-150405"))
+	m.exportDone = 0
+	m.exportGen++
+	m.state = stateExporting
+	return m, tea.Batch(m.spinner.Tick, m.exportNext())
+}
+
+// exportNext copies the next article of the export.
+func (m Model) exportNext() tea.Cmd {
+	store, filePath, dir, gen := m.store, m.exportPaths[m.exportDone], m.exportDir, m.exportGen
+	return func() tea.Msg {
+		_, err := store.ExportArticle(filePath, dir)
+		return exportedMsg{err: err, gen: gen}
+	}
+}
+
+// handleExported moves an export on to its next article, or finishes it.
+func (m Model) handleExported(msg exportedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.exportGen || m.state != stateExporting {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.state = stateList
+		m.err = msg.err
+		return m, nil
+	}
+	m.exportDone++
+	if m.exportDone < len(m.exportPaths) {
+		return m, m.exportNext()
+	}
+	m.state = stateList
+	m.statusMsg = m.msgs.format("status.exported", m.exportDone, m.exportDir)
+	return m, nil
+}
+
+// handleExportingKeys stops an export with esc, q or ctrl+c, keeping the
+// articles already copied.
+func (m Model) handleExportingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Cancel) || key.Matches(msg, m.keys.Quit) {
+		m.exportGen++ // the copy in flight finishes, but isn't followed up
+		m.state = stateList
+		m.suppressQuit = true
+		m.statusMsg = m.msgs.format("status.export_stopped", m.exportDone, len(m.exportPaths), m.exportDir)
+	}
+	return m, nil
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// extractCacheSize is how many extractions the session keeps.
+const extractCacheSize = 16
+
+// extractCache keeps the last successful extraction of recently fetched
+// URLs for the rest of the session, so adding a URL again after cancelling
+// or deleting it doesn't hit the network. It holds at most max results,
+// dropping the least recently used, and is keyed by storage.NormalizeURL.
+// It is safe for concurrent use.
+type extractCache struct {
+	max int
+
+	mu      sync.Mutex
+	order   *list.List // of *extractCacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type extractCacheEntry struct {
+	key    string
+	result extractor.ExtractResult
+}
+
+// newExtractCache returns a cache holding up to max results.
+func newExtractCache(max int) *extractCache {
+	return &extractCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns a copy of the cached result for url, if there is one.
+func (c *extractCache) get(url string) (*extractor.ExtractResult, bool) {
+	key := storage.NormalizeURL(url)
+	if c == nil || key == "" {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	result := el.Value.(*extractCacheEntry).result
+	return &result, true
+}
+
+// put caches result as url's latest extraction.
+func (c *extractCache) put(url string, result *extractor.ExtractResult) {
+	key := storage.NormalizeURL(url)
+	if c == nil || key == "" || c.max <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*extractCacheEntry).result = *result
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&extractCacheEntry{key: key, result: *result})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*extractCacheEntry).key)
+	}
+}
+
+// forget drops url's cached extraction, so the next fetch goes to the
+// network.
+func (c *extractCache) forget(url string) {
+	key := storage.NormalizeURL(url)
+	if c == nil || key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+)
+
+func TestExtractCache(t *testing.T) {
+	c := newExtractCache(2)
+	c.put("https://example.com/a?utm_source=feed", &extractor.ExtractResult{Title: "A"})
+	c.put("https://example.com/b", &extractor.ExtractResult{Title: "B"})
+
+	// Trivially different spellings of a URL share an entry.
+	if r, ok := c.get("http://www.example.com/a/"); !ok || r.Title != "A" {
+		t.Fatalf("get(a) = %v, %v", r, ok)
+	}
+	// a was used more recently than b, so b is dropped to make room.
+	c.put("https://example.com/c", &extractor.ExtractResult{Title: "C"})
+	if _, ok := c.get("https://example.com/b"); ok {
+		t.Errorf("least recently used entry kept past the bound")
+	}
+	for _, url := range []string{"https://example.com/a", "https://example.com/c"} {
+		if _, ok := c.get(url); !ok {
+			t.Errorf("%s dropped", url)
+		}
+	}
+
+	c.forget("https://example.com/a")
+	if _, ok := c.get("https://example.com/a"); ok {
+		t.Errorf("entry kept after forget")
+	}
+
+	// A nil cache, as in models built without New, caches nothing.
+	var nilCache *extractCache
+	nilCache.put("https://example.com/a", &extractor.ExtractResult{})
+	if _, ok := nilCache.get("https://example.com/a"); ok {
+		t.Errorf("nil cache returned a result")
+	}
+}
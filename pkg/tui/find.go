@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// findTimeout is how long a find-as-you-type prefix waits for the next
+// letter before starting over.
+const findTimeout = time.Second
+
+// startFind starts find-as-you-type: letters typed after f move the cursor
+// to the next article whose title starts with them.
+func (m Model) startFind() (tea.Model, tea.Cmd) {
+	m.finding = true
+	m.findPrefix = ""
+	m.statusMsg = m.msgs.format("status.find", "")
+	return m, nil
+}
+
+// handleFindKey handles a key while finding. Letters extend the prefix, or
+// start a new one after findTimeout, and jump to the next match; typing
+// the same letter again moves on to the match after. Any other key stops
+// finding, and is handled as usual unless it's Esc, so e.g. Enter opens
+// the article found.
+func (m Model) handleFindKey(msg tea.KeyMsg) (Model, bool) {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || msg.Alt {
+		m.finding = false
+		m.statusMsg = ""
+		return m, msg.Type == tea.KeyEsc
+	}
+
+	letter := strings.ToLower(string(msg.Runes))
+	from := m.cursor
+	switch {
+	case m.findPrefix == "" || time.Since(m.findAt) > findTimeout:
+		m.findPrefix = letter
+		from = m.cursor + 1
+	case m.findPrefix == letter:
+		// Cycle through the articles starting with this letter.
+		from = m.cursor + 1
+	default:
+		m.findPrefix += letter
+	}
+	m.findAt = time.Now()
+
+	if i, ok := m.findFrom(m.findPrefix, from); ok {
+		m.cursor = i
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		m.statusMsg = m.msgs.format("status.find", m.findPrefix)
+	} else {
+		m.statusMsg = m.msgs.format("status.find_none", m.findPrefix)
+	}
+	return m, true
+}
+
+// findFrom returns the index of the first article at or after from,
+// wrapping around, whose title starts with prefix, or failing that
+// contains it.
+func (m Model) findFrom(prefix string, from int) (int, bool) {
+	n := len(m.articles)
+	for _, match := range []func(title string) bool{
+		func(title string) bool { return strings.HasPrefix(title, prefix) },
+		func(title string) bool { return strings.Contains(title, prefix) },
+	} {
+		for j := range n {
+			i := (from + j) % n
+			if match(strings.ToLower(m.articles[i].Title)) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"os"
+	"strings"
+)
+
+// searchHistory is a bounded list of recent search queries, persisted one
+// per line in the data directory. Up/Down in the search input walk through
+// it like shell history. A nil *searchHistory is valid and records nothing.
+type searchHistory struct {
+	path    string
+	limit   int
+	entries []string // oldest first
+
+	// Recall position while browsing: len(entries) means "not browsing",
+	// in which case draft holds whatever was typed before the first Up.
+	pos   int
+	draft string
+}
+
+// loadSearchHistory reads the history file at path, keeping at most limit
+// entries. A limit of zero or less disables history and removes any
+// previously saved file.
+func loadSearchHistory(path string, limit int) *searchHistory {
+	if limit <= 0 {
+		os.Remove(path)
+		return nil
+	}
+	h := &searchHistory{path: path, limit: limit}
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				h.entries = append(h.entries, line)
+			}
+		}
+	}
+	if len(h.entries) > limit {
+		h.entries = h.entries[len(h.entries)-limit:]
+	}
+	h.pos = len(h.entries)
+	return h
+}
+
+// add records a submitted query as the most recent entry and writes the
+// history back to disk. Repeating an earlier query moves it to the end.
+func (h *searchHistory) add(query string) error {
+	if h == nil {
+		return nil
+	}
+	query = strings.TrimSpace(query)
+	defer h.reset()
+	if query == "" {
+		return nil
+	}
+	for i, e := range h.entries {
+		if e == query {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, query)
+	if len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0644)
+}
+
+// prev steps back to the previous query. current is the input's value,
+// remembered when browsing starts so that next can restore it.
+func (h *searchHistory) prev(current string) (string, bool) {
+	if h == nil || h.pos == 0 {
+		return "", false
+	}
+	if h.pos == len(h.entries) {
+		h.draft = current
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// next steps forward to a more recent query, ending at the original draft.
+func (h *searchHistory) next() (string, bool) {
+	if h == nil || h.pos == len(h.entries) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.pos], true
+}
+
+// reset ends browsing, so the next prev starts from the most recent query.
+func (h *searchHistory) reset() {
+	if h == nil {
+		return
+	}
+	h.pos = len(h.entries)
+	h.draft = ""
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/images"
+	"github.com/irfansharif/shelf/pkg/storage"
+	"github.com/irfansharif/shelf/pkg/termimg"
+)
+
+// imagesLocalizedMsg reports the result of downloading an article's remote
+// images.
+type imagesLocalizedMsg struct {
+	filePath string
+	fetched  int
+	failed   []images.Failure
+	err      error
+}
+
+// openImageList shows the images referenced by the selected article.
+func (m Model) openImageList() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+	article := m.articles[m.cursor]
+	refs, err := m.store.Images(article.FilePath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if len(refs) == 0 {
+		m.statusMsg = m.msgs.format("status.no_images", article.Title)
+		return m, nil
+	}
+	m.state = stateImages
+	m.imageArticle = article
+	m.images = refs
+	m.imageCursor = 0
+	m.imageScroll = 0
+	m.imagePreview = ""
+	return m, nil
+}
+
+func (m Model) handleImagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.imageCursor > 0 {
+			m.imageCursor--
+		}
+		if m.imagePreview != "" {
+			m.imagePreview = m.renderImagePreview()
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.imageCursor < len(m.images)-1 {
+			m.imageCursor++
+		}
+		if m.imagePreview != "" {
+			m.imagePreview = m.renderImagePreview()
+		}
+	case msg.String() == "p", msg.String() == " ":
+		if m.imagePreview != "" {
+			m.imagePreview = ""
+			return m, tea.ClearScreen // drop the drawn image
+		}
+		m.imagePreview = m.renderImagePreview()
+	case msg.String() == "l":
+		return m.localizeImages()
+	case key.Matches(msg, m.keys.Submit):
+		ref := m.images[m.imageCursor]
+		target := ref.Path
+		if !ref.IsRemote() {
+			target = m.store.ImagePath(m.imageArticle.FilePath, ref)
+		}
+		return m, openExternal(target)
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+		m.images = nil
+		if m.imagePreview != "" {
+			m.imagePreview = ""
+			return m, tea.ClearScreen
+		}
+		return m, nil
+	}
+	m.imageScroll = clampScroll(m.imageCursor, m.imageScroll, m.imageVisibleItems(), len(m.images))
+	return m, nil
+}
+
+// localizeImages downloads the remote images of the article in the image
+// list in the background, storing them with the article.
+func (m Model) localizeImages() (tea.Model, tea.Cmd) {
+	remote := 0
+	for _, ref := range m.images {
+		if ref.IsRemote() {
+			remote++
+		}
+	}
+	if remote == 0 {
+		m.statusMsg = m.msgs.text("status.no_remote_images")
+		return m, nil
+	}
+	m.statusMsg = m.msgs.format("status.downloading_images", remote)
+	store, filePath := m.store, m.imageArticle.FilePath
+	return m, func() tea.Msg {
+		fetched, failed, err := store.LocalizeImages(filePath, &http.Client{Timeout: 30 * time.Second})
+		return imagesLocalizedMsg{filePath: filePath, fetched: fetched, failed: failed, err: err}
+	}
+}
+
+func (m Model) handleImagesLocalized(msg imagesLocalizedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMsg = ""
+		m.err = msg.err
+		return m, nil
+	}
+	for _, f := range msg.failed {
+		m.logger.Warn("downloading image", "url", f.URL, "err", f.Err)
+	}
+	m.statusMsg = m.msgs.format("status.downloaded_images", msg.fetched)
+	if len(msg.failed) > 0 {
+		m.statusMsg += ", " + m.msgs.format("status.images_failed", len(msg.failed))
+	}
+	if m.state == stateImages && m.imageArticle.FilePath == msg.filePath {
+		if refs, err := m.store.Images(msg.filePath); err == nil {
+			m.images = refs
+			m.imageCursor = min(m.imageCursor, max(0, len(refs)-1))
+			m.imageScroll = clampScroll(m.imageCursor, m.imageScroll, m.imageVisibleItems(), len(m.images))
+		}
+	}
+	m.refreshArticles()
+	return m, m.measureTotalSize()
+}
+
+// renderImagePreview renders the selected image for display in place of the
+// image list: inline via the terminal's graphics protocol when it has one,
+// otherwise as an [image: alt] placeholder.
+func (m Model) renderImagePreview() string {
+	ref := m.images[m.imageCursor]
+	cols, rows := m.width-4, m.imagePreviewRows()
+	if m.imageProtocol != termimg.None && !ref.IsRemote() {
+		seq, err := termimg.EncodeFile(m.imageProtocol, m.store.ImagePath(m.imageArticle.FilePath, ref), cols, rows)
+		if err == nil {
+			return seq + strings.Repeat("\n", rows-1)
+		}
+		m.logger.Debug("rendering image inline", "path", ref.Path, "err", err)
+	}
+	return m.styles.ListItemTitle.Render(termimg.Placeholder(ref.Alt)) + "\n" +
+		m.styles.Muted.Render(truncateString(imageDesc(ref), cols, m.ellipsis))
+}
+
+// imagePreviewRows is the height of the inline image preview, in rows.
+func (m Model) imagePreviewRows() int {
+	return max(1, m.imageVisibleItems()*3-1)
+}
+
+func (m Model) renderImages() string {
+	var sb strings.Builder
+	if m.imagePreview == "" {
+		// Remove a previously previewed image; Kitty keeps images on screen
+		// until told otherwise.
+		sb.WriteString(termimg.Clear(m.imageProtocol))
+	}
+	sb.WriteString(m.styles.Muted.Render(m.msgs.format("view.images", len(m.images), m.imageArticle.Title)))
+	if m.imagePreview != "" {
+		sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("view.image_position", m.imageCursor+1, len(m.images))))
+		sb.WriteString("\n\n")
+		sb.WriteString(m.imagePreview)
+		return sb.String()
+	}
+	sb.WriteString("\n\n")
+
+	contentWidth := m.width - 4
+	end := min(m.imageScroll+m.imageVisibleItems(), len(m.images))
+	for i := m.imageScroll; i < end; i++ {
+		ref := m.images[i]
+		if i > m.imageScroll {
+			sb.WriteString("\n\n")
+		}
+		alt := ref.Alt
+		if alt == "" {
+			alt = m.msgs.text("view.no_alt_text")
+		}
+		alt = truncateString(alt, contentWidth-2, m.ellipsis)
+		if i == m.imageCursor {
+			sb.WriteString(m.styles.SelectionMarker.Render(""))
+			sb.WriteString(m.styles.SelectedTitle.Render(alt))
+		} else {
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.ListItemTitle.Render(alt))
+		}
+		sb.WriteString("\n  ")
+		sb.WriteString(m.styles.ListItemDesc.Render(truncateString(imageDesc(ref), contentWidth-2, m.ellipsis)))
+	}
+	return sb.String()
+}
+
+func imageDesc(ref storage.ImageRef) string {
+	if ref.IsRemote() {
+		return ref.Path + " (remote)"
+	}
+	return ref.Path
+}
+
+// imageVisibleItems returns the number of images that fit on screen,
+// leaving room for the heading above the list.
+func (m Model) imageVisibleItems() int {
+	return max(1, m.calcVisibleItems()-1)
+}
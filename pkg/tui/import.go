@@ -6,13 +6,16 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/state"
 	"github.com/irfansharif/shelf/pkg/storage"
 )
 
@@ -22,6 +25,10 @@ type (
 		tabs     map[string][]safari.Tab
 		warnings []error
 	}
+	frontWindowTabsMsg struct {
+		tabs []safari.Tab
+		err  error
+	}
 	importEditorFinishedMsg struct {
 		tmpPath string
 		err     error
@@ -29,19 +36,49 @@ type (
 	importArticleResultMsg struct {
 		url     string
 		title   string
+		tags    []string
 		skipped bool
 		err     error
+		tagErr  error // saved, but the buffer's tags couldn't be applied
+		gen     uint64
 	}
+	// importRetryMsg dispatches the import queue again once a URL held
+	// back by the rate limit may go.
+	importRetryMsg struct{ gen uint64 }
 )
 
-// gatherSafariTabs returns a command that collects tabs from Safari.
-func gatherSafariTabs() tea.Cmd {
+// gatherSafariTabs returns a command that collects tabs from the Safari
+// sources import_sources enables.
+func (m Model) gatherSafariTabs() tea.Cmd {
+	sources := m.importSources
 	return func() tea.Msg {
-		tabs, warnings := safari.GatherTabs()
+		tabs, warnings := safari.GatherTabs(sources)
 		return safariTabsGatheredMsg{tabs: tabs, warnings: warnings}
 	}
 }
 
+// startWindowImport begins an import of just the tabs in Safari's front
+// window, first asking whether to resume a paused import instead.
+func (m Model) startWindowImport() (tea.Model, tea.Cmd) {
+	if len(m.appState.ImportQueue) > 0 {
+		m.state = stateConfirmResume
+		m.importWindow = true
+		return m, nil
+	}
+	return m.gatherWindowImport()
+}
+
+// gatherWindowImport gathers the front window's tabs for a new import.
+func (m Model) gatherWindowImport() (tea.Model, tea.Cmd) {
+	m.state = stateGatheringTabs
+	m.err = nil
+	m.importWarnings = nil
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		tabs, err := safari.FrontWindowTabs()
+		return frontWindowTabsMsg{tabs: tabs, err: err}
+	})
+}
+
 // sourceLabel maps source keys to display names for the import file headers.
 var sourceLabel = map[string]string{
 	"icloud":      "iCloud Tabs",
@@ -49,109 +86,101 @@ var sourceLabel = map[string]string{
 	"readinglist": "Reading List",
 }
 
-// sourceOrder defines the iteration order for sources in the import file.
-var sourceOrder = []string{"local", "icloud", "readinglist"}
+// importSort is the grouping and ordering of tabs in the import buffer.
+type importSort string
+
+const (
+	// importSortSource groups by source, then by domain, then by recency.
+	importSortSource importSort = "source"
+	// importSortRecent lists all tabs by recency, most recent first.
+	importSortRecent importSort = "recent"
+	// importSortDomain groups all tabs by domain, alphabetically.
+	importSortDomain importSort = "domain"
+	// importSortTitle lists all tabs alphabetically by title.
+	importSortTitle importSort = "title"
+)
 
 // formatImportFile generates the temp file content for the editor buffer.
 // All URLs are commented out by default; the user uncomments the ones they
-// want to import. Tabs are grouped first by source (iCloud, Local, Reading
-// List) with level-1 fold markers, then by domain with level-2 fold markers.
-// Within each domain, tabs are sorted by LastViewed descending; domain groups
-// are sorted by their most recent tab's LastViewed (descending), with an
-// alphabetical tiebreaker.
-func formatImportFile(tabsBySource map[string][]safari.Tab, savedURLs map[string]bool, warnings []error) string {
+// want to import. Already-saved URLs are left out, as are tabs from sources
+// not in sources, which also sets their order (safari.Sources if empty).
+//
+// With importSortSource (the default), tabs are grouped first by source
+// (Local, iCloud, Reading List by default) with level-1 fold markers, then by domain
+// with level-2 fold markers. Within each domain, tabs are sorted by
+// LastViewed descending; domain groups are sorted by their most recent tab's
+// LastViewed (descending), with an alphabetical tiebreaker. The other
+// orderings merge all sources: importSortDomain keeps the domain folds
+// (alphabetical), while importSortRecent and importSortTitle produce a flat
+// list.
+func formatImportFile(msgs messages, tabsBySource map[string][]safari.Tab, savedURLs map[string]bool, warnings []error, by importSort, sources []string) string {
+	if len(sources) == 0 {
+		sources = safari.Sources
+	}
 	var sb strings.Builder
-	sb.WriteString("# Safari Import — uncomment URLs to import, then :wq\n")
-	sb.WriteString("# Use zo/zc to unfold/fold groups, zR to open all.\n")
+	writeComment(&sb, msgs.text("import.buffer_help"))
 	sb.WriteString("#\n")
 
 	for _, w := range warnings {
-		sb.WriteString(fmt.Sprintf("# Warning: %s\n", w.Error()))
+		writeComment(&sb, msgs.format("import.buffer_warning", w.Error()))
 	}
 	if len(warnings) > 0 {
 		sb.WriteString("#\n")
 	}
 
-	for _, source := range sourceOrder {
-		tabs := tabsBySource[source]
-		if len(tabs) == 0 {
-			continue
-		}
-
-		// Filter out already-saved URLs.
+	unsavedTabs := func(tabs []safari.Tab) []safari.Tab {
 		var unsaved []safari.Tab
 		for _, t := range tabs {
 			if !savedURLs[t.URL] {
 				unsaved = append(unsaved, t)
 			}
 		}
-		if len(unsaved) == 0 {
-			continue
-		}
-
-		label := sourceLabel[source]
-		// Level-1 fold: source group.
-		sb.WriteString(fmt.Sprintf("\n# === %s (%d) === %s\n", label, len(unsaved), "{"+"{"+"{1"))
+		return unsaved
+	}
 
-		// Group tabs by domain.
-		domainTabs := make(map[string][]safari.Tab)
-		for _, t := range unsaved {
-			domain := extractDomain(t.URL)
-			domainTabs[domain] = append(domainTabs[domain], t)
+	switch by {
+	case importSortRecent, importSortTitle, importSortDomain:
+		var all []safari.Tab
+		for _, source := range sources {
+			all = append(all, unsavedTabs(tabsBySource[source])...)
 		}
-
-		// Sort tabs within each domain by LastViewed descending.
-		for d := range domainTabs {
-			sort.Slice(domainTabs[d], func(i, j int) bool {
-				return domainTabs[d][i].LastViewed.After(domainTabs[d][j].LastViewed)
+		all = dedupeTabs(all)
+		switch by {
+		case importSortRecent:
+			sort.SliceStable(all, func(i, j int) bool {
+				return all[i].LastViewed.After(all[j].LastViewed)
+			})
+			writeImportTabs(&sb, all, "")
+		case importSortTitle:
+			sort.SliceStable(all, func(i, j int) bool {
+				return strings.ToLower(tabLabel(all[i])) < strings.ToLower(tabLabel(all[j]))
+			})
+			writeImportTabs(&sb, all, "")
+		case importSortDomain:
+			writeDomainGroups(&sb, all, 1, func(a, b domainGroup) bool {
+				return a.domain < b.domain
 			})
 		}
 
-		// Sort domains by most recent tab's LastViewed (descending),
-		// alphabetical tiebreaker.
-		var domains []string
-		for d := range domainTabs {
-			domains = append(domains, d)
-		}
-		domainMaxTime := make(map[string]time.Time)
-		for d, dt := range domainTabs {
-			var maxT time.Time
-			for _, t := range dt {
-				if t.LastViewed.After(maxT) {
-					maxT = t.LastViewed
-				}
+	default:
+		for _, source := range sources {
+			unsaved := unsavedTabs(tabsBySource[source])
+			if len(unsaved) == 0 {
+				continue
 			}
-			domainMaxTime[d] = maxT
-		}
-		sort.Slice(domains, func(i, j int) bool {
-			ti, tj := domainMaxTime[domains[i]], domainMaxTime[domains[j]]
-			if !ti.Equal(tj) {
-				return ti.After(tj)
-			}
-			return domains[i] < domains[j]
-		})
 
-		for _, domain := range domains {
-			dt := domainTabs[domain]
-			// Level-2 fold: domain group.
-			sb.WriteString(fmt.Sprintf("\n# --- %s (%d) --- %s\n", domain, len(dt), "{"+"{"+"{2"))
-			for i, t := range dt {
-				if i > 0 {
-					sb.WriteString("\n")
+			label := sourceLabel[source]
+			// Level-1 fold: source group.
+			sb.WriteString(fmt.Sprintf("\n# === %s (%d) === %s\n", label, len(unsaved), "{"+"{"+"{1"))
+			writeDomainGroups(&sb, unsaved, 2, func(a, b domainGroup) bool {
+				if !a.latest.Equal(b.latest) {
+					return a.latest.After(b.latest)
 				}
-				title := t.Title
-				if title == "" {
-					title = t.URL
-				}
-				sb.WriteString(fmt.Sprintf("\t# %s\n", title))
-				sb.WriteString(fmt.Sprintf("\t# %s\n", t.URL))
-			}
-			// Close level-2 fold.
-			sb.WriteString("# " + "}" + "}" + "}2\n")
+				return a.domain < b.domain
+			})
+			// Close level-1 fold.
+			sb.WriteString("# " + "}" + "}" + "}1\n")
 		}
-
-		// Close level-1 fold.
-		sb.WriteString("# " + "}" + "}" + "}1\n")
 	}
 
 	// Vim modeline: conf filetype for # comment highlighting,
@@ -161,6 +190,114 @@ func formatImportFile(tabsBySource map[string][]safari.Tab, savedURLs map[string
 	return sb.String()
 }
 
+// writeComment writes text to an import buffer as comment lines.
+func writeComment(sb *strings.Builder, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			sb.WriteString("#\n")
+		} else {
+			sb.WriteString("# " + line + "\n")
+		}
+	}
+}
+
+// formatWindowImportFile generates the import buffer for the tabs of one
+// Safari window. Unlike formatImportFile's, the URLs are listed in tab order
+// and left uncommented: the tabs were opened to be saved, so the user
+// comments out the ones to skip. Already-saved URLs are left out.
+func formatWindowImportFile(msgs messages, tabs []safari.Tab, savedURLs map[string]bool) string {
+	var sb strings.Builder
+	writeComment(&sb, msgs.text("import.window_buffer_help"))
+	for _, t := range dedupeTabs(tabs) {
+		if savedURLs[t.URL] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n# %s\n%s\n", tabLabel(t), t.URL))
+	}
+	sb.WriteString("\n# vim: ft=conf\n")
+	return sb.String()
+}
+
+// domainGroup is the set of tabs from one domain in the import buffer.
+type domainGroup struct {
+	domain string
+	tabs   []safari.Tab
+	latest time.Time // most recent LastViewed among tabs
+}
+
+// writeDomainGroups writes tabs grouped by domain, each group in a fold at
+// the given level. Tabs within a group are sorted by LastViewed descending;
+// groups are ordered by less.
+func writeDomainGroups(sb *strings.Builder, tabs []safari.Tab, level int, less func(a, b domainGroup) bool) {
+	byDomain := make(map[string]*domainGroup)
+	var groups []*domainGroup
+	for _, t := range tabs {
+		domain := extractDomain(t.URL)
+		g, ok := byDomain[domain]
+		if !ok {
+			g = &domainGroup{domain: domain}
+			byDomain[domain] = g
+			groups = append(groups, g)
+		}
+		g.tabs = append(g.tabs, t)
+		if t.LastViewed.After(g.latest) {
+			g.latest = t.LastViewed
+		}
+	}
+	for _, g := range groups {
+		sort.Slice(g.tabs, func(i, j int) bool {
+			return g.tabs[i].LastViewed.After(g.tabs[j].LastViewed)
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return less(*groups[i], *groups[j])
+	})
+
+	for _, g := range groups {
+		sb.WriteString(fmt.Sprintf("\n# --- %s (%d) --- %s%d\n", g.domain, len(g.tabs), "{"+"{"+"{", level))
+		writeImportTabs(sb, g.tabs, "\t")
+		sb.WriteString(fmt.Sprintf("# %s%d\n", "}"+"}"+"}", level))
+	}
+}
+
+// writeImportTabs writes each tab as a commented-out title and URL,
+// separated by blank lines.
+func writeImportTabs(sb *strings.Builder, tabs []safari.Tab, indent string) {
+	for i, t := range tabs {
+		if i > 0 || indent == "" {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s# %s\n", indent, tabLabel(t)))
+		sb.WriteString(fmt.Sprintf("%s# %s\n", indent, t.URL))
+	}
+}
+
+// tabLabel is the title shown for a tab, falling back to its URL.
+func tabLabel(t safari.Tab) string {
+	if t.Title == "" {
+		return t.URL
+	}
+	return t.Title
+}
+
+// dedupeTabs removes tabs whose URL appears in another source, keeping the
+// most recently viewed.
+func dedupeTabs(tabs []safari.Tab) []safari.Tab {
+	seen := make(map[string]int)
+	var result []safari.Tab
+	for _, t := range tabs {
+		if i, ok := seen[t.URL]; ok {
+			if t.LastViewed.After(result[i].LastViewed) {
+				result[i] = t
+			}
+			continue
+		}
+		seen[t.URL] = len(result)
+		result = append(result, t)
+	}
+	return result
+}
+
 // extractDomain returns the hostname from a URL, stripping "www." prefix.
 func extractDomain(rawURL string) string {
 	parsed, err := url.Parse(rawURL)
@@ -172,26 +309,42 @@ func extractDomain(rawURL string) string {
 	return host
 }
 
-// parseImportFile reads the edited temp file and returns URLs to import.
-func parseImportFile(path string) ([]string, error) {
+// importItem is a URL to import along with the tags to give it.
+type importItem struct {
+	url  string
+	tags []string
+}
+
+// parseImportFile reads the edited temp file and returns the URLs to import.
+// Each uncommented line is a URL optionally followed by whitespace-separated
+// #tag tokens; other trailing text is ignored.
+func parseImportFile(path string) ([]importItem, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading import file: %w", err)
 	}
 
-	var urls []string
+	var items []importItem
 	for _, line := range strings.Split(string(data), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		urls = append(urls, line)
+		fields := strings.Fields(line)
+		item := importItem{url: fields[0]}
+		for _, f := range fields[1:] {
+			if tag := strings.TrimPrefix(f, "#"); tag != f && tag != "" && !slices.Contains(item.tags, tag) {
+				item.tags = append(item.tags, tag)
+			}
+		}
+		items = append(items, item)
 	}
-	return urls, nil
+	return items, nil
 }
 
 // handleSafariTabsGathered processes gathered Safari tabs: writes the temp
-// file and opens it in the user's editor.
+// file and opens it in the user's editor, first looking up the titles of
+// untitled tabs if import_fetch_titles is set.
 func (m Model) handleSafariTabsGathered(msg safariTabsGatheredMsg) (tea.Model, tea.Cmd) {
 	totalTabs := 0
 	for _, tabs := range msg.tabs {
@@ -200,30 +353,75 @@ func (m Model) handleSafariTabsGathered(msg safariTabsGatheredMsg) (tea.Model, t
 
 	if totalTabs == 0 && len(msg.warnings) > 0 {
 		m.state = stateList
-		m.err = fmt.Errorf("no Safari tabs found: %s", msg.warnings[0].Error())
+		m.err = errors.New(m.msgs.format("status.no_safari_tabs_reason", msg.warnings[0].Error()))
 		return m, nil
 	}
 	if totalTabs == 0 {
 		m.state = stateList
-		m.statusMsg = "No Safari tabs found"
+		m.statusMsg = m.msgs.text("status.no_safari_tabs")
+		return m, nil
+	}
+
+	// Keep the warnings for the post-import toast; the comments embedded in
+	// the editor buffer are easy to miss.
+	m.importWarnings = msg.warnings
+	for _, w := range msg.warnings {
+		m.logger.Warn("safari source unavailable", "err", w)
+	}
+
+	if m.importFetchTitles {
+		return m.resolveTitles(msg.tabs, false)
+	}
+	content := formatImportFile(m.msgs, msg.tabs, m.savedURLs(), msg.warnings, m.importSort, m.importSources)
+	return m.openImportBuffer(content)
+}
+
+// handleFrontWindowTabs opens the import buffer for the tabs of Safari's
+// front window.
+func (m Model) handleFrontWindowTabs(msg frontWindowTabsMsg) (tea.Model, tea.Cmd) {
+	if m.state != stateGatheringTabs {
+		return m, nil // cancelled
+	}
+	if msg.err != nil {
+		m.state = stateList
+		m.err = msg.err
 		return m, nil
 	}
+	savedURLs := m.savedURLs()
+	unsaved := slices.ContainsFunc(msg.tabs, func(t safari.Tab) bool { return !savedURLs[t.URL] })
+	if !unsaved {
+		m.state = stateList
+		if len(msg.tabs) == 0 {
+			m.statusMsg = m.msgs.text("status.no_window_pages")
+		} else {
+			m.statusMsg = m.msgs.text("status.window_all_saved")
+		}
+		return m, nil
+	}
+	if m.importFetchTitles {
+		return m.resolveTitles(map[string][]safari.Tab{"window": msg.tabs}, true)
+	}
+	return m.openImportBuffer(formatWindowImportFile(m.msgs, msg.tabs, savedURLs))
+}
 
-	// Build set of already-saved URLs.
-	savedURLs := make(map[string]bool)
+// savedURLs returns the set of source URLs already saved.
+func (m Model) savedURLs() map[string]bool {
+	saved := make(map[string]bool)
 	for _, a := range m.store.List() {
 		if a.SourceURL != "" {
-			savedURLs[a.SourceURL] = true
+			saved[a.SourceURL] = true
 		}
 	}
+	return saved
+}
 
-	content := formatImportFile(msg.tabs, savedURLs, msg.warnings)
-
-	// Write temp file.
+// openImportBuffer writes content to a temp file and opens it in the
+// user's editor.
+func (m Model) openImportBuffer(content string) (tea.Model, tea.Cmd) {
 	tmpFile, err := os.CreateTemp("", "shelf-import-*.txt")
 	if err != nil {
 		m.state = stateList
-		m.err = fmt.Errorf("creating temp file: %w", err)
+		m.err = fmt.Errorf(m.msgs.text("status.temp_file_create_failed"), err)
 		return m, nil
 	}
 	tmpPath := tmpFile.Name()
@@ -231,12 +429,16 @@ func (m Model) handleSafariTabsGathered(msg safariTabsGatheredMsg) (tea.Model, t
 		tmpFile.Close()
 		os.Remove(tmpPath)
 		m.state = stateList
-		m.err = fmt.Errorf("writing temp file: %w", err)
+		m.err = fmt.Errorf(m.msgs.text("status.temp_file_write_failed"), err)
 		return m, nil
 	}
 	tmpFile.Close()
 
-	// Open editor.
+	return m, editImportBuffer(tmpPath)
+}
+
+// editImportBuffer opens the import buffer at path in the user's editor.
+func editImportBuffer(path string) tea.Cmd {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "nvim"
@@ -246,57 +448,126 @@ func (m Model) handleSafariTabsGathered(msg safariTabsGatheredMsg) (tea.Model, t
 		shell = "/bin/sh"
 	}
 
-	c := exec.Command(shell, "-l", "-c", fmt.Sprintf("%s %q", editor, tmpPath))
+	c := exec.Command(shell, "-l", "-c", fmt.Sprintf("%s %q", editor, path))
 	c.Stdin = os.Stdin
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
 
-	return m, tea.ExecProcess(c, func(err error) tea.Msg {
-		return importEditorFinishedMsg{tmpPath: tmpPath, err: err}
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return importEditorFinishedMsg{tmpPath: path, err: err}
 	})
 }
 
-// handleImportEditorFinished parses the edited file and starts batch import.
+// handleImportEditorFinished parses the edited file and shows the dry-run
+// preview. The buffer is kept until the import starts or is cancelled, so
+// the preview can send the user back to edit it.
 func (m Model) handleImportEditorFinished(msg importEditorFinishedMsg) (tea.Model, tea.Cmd) {
-	defer os.Remove(msg.tmpPath)
-
 	if msg.err != nil {
+		os.Remove(msg.tmpPath)
 		m.state = stateList
-		m.err = fmt.Errorf("editor: %w", msg.err)
+		m.err = fmt.Errorf(m.msgs.text("status.editor_failed"), msg.err)
 		return m, nil
 	}
 
-	urls, err := parseImportFile(msg.tmpPath)
+	items, err := parseImportFile(msg.tmpPath)
 	if err != nil {
+		os.Remove(msg.tmpPath)
 		m.state = stateList
 		m.err = err
 		return m, nil
 	}
 
-	if len(urls) == 0 {
+	if len(items) == 0 {
+		os.Remove(msg.tmpPath)
 		m.state = stateList
-		m.statusMsg = "No URLs to import"
+		m.statusMsg = m.withSourceWarnings(m.msgs.text("status.no_urls"))
 		return m, nil
 	}
 
-	m.importQueue = urls
-	m.importTotal = len(urls)
+	return m.openImportPreview(msg.tmpPath, items)
+}
+
+// startImport begins a batch import of items.
+func (m Model) startImport(items []importItem) (tea.Model, tea.Cmd) {
+	m.importQueue = items
+	m.importPaused = false
+	m.importInFlight = nil
+	m.importRetrying = false
+	m.importGen++
+	m.importTotal = len(items)
 	m.importDone = 0
 	m.importSkipped = 0
 	m.importErrors = nil
 	m.state = stateImporting
-	return m, tea.Batch(m.spinner.Tick, m.importExtractAndSave(urls[0]))
+	m.logger.Info("import started", "urls", len(items))
+	var cmd tea.Cmd
+	m, cmd = m.dispatchImports()
+	return m, tea.Batch(m.spinner.Tick, cmd)
+}
+
+// dispatchImports starts queued imports until importConcurrency are in
+// flight. URLs from a host importLimiter holds back stay queued, in order,
+// while those from other hosts go ahead, and the queue is dispatched again
+// once the first of them may go.
+func (m Model) dispatchImports() (Model, tea.Cmd) {
+	if m.importPaused {
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	var retry time.Duration
+	now := time.Now()
+	held := make(map[string]bool) // hosts held back, whose later URLs wait their turn
+	for i := 0; i < len(m.importQueue) && len(m.importInFlight) < m.importConcurrency; {
+		item := m.importQueue[i]
+		host := extractDomain(item.url)
+		if held[host] {
+			i++
+			continue
+		}
+		if d := m.importLimiter.take(host, now); d > 0 {
+			held[host] = true
+			if retry == 0 || d < retry {
+				retry = d
+			}
+			i++
+			continue
+		}
+		m.importQueue = append(m.importQueue[:i:i], m.importQueue[i+1:]...)
+		m.importInFlight = append(m.importInFlight, item)
+		cmds = append(cmds, m.importExtractAndSave(item))
+	}
+	if retry > 0 && !m.importRetrying {
+		m.importRetrying = true
+		gen := m.importGen
+		cmds = append(cmds, tea.Tick(retry, func(time.Time) tea.Msg {
+			return importRetryMsg{gen: gen}
+		}))
+	}
+	return m, tea.Batch(cmds...)
 }
 
-// importExtractAndSave extracts an article and saves it in a single command.
-// Duplicates (slug collisions) are silently skipped.
-func (m Model) importExtractAndSave(url string) tea.Cmd {
+// handleImportRetry dispatches the queue again after a URL was held back
+// by the rate limit.
+func (m Model) handleImportRetry(msg importRetryMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.importGen {
+		return m, nil
+	}
+	m.importRetrying = false
+	return m.dispatchImports()
+}
+
+// importExtractAndSave extracts an article and saves it in a single command,
+// then applies any tags given in the import buffer. Duplicates (slug
+// collisions) are silently skipped.
+func (m Model) importExtractAndSave(item importItem) tea.Cmd {
 	ext := m.extract
 	store := m.store
+	gen := m.importGen
+	url, tags := item.url, item.tags
 	return func() tea.Msg {
 		result, err := ext.Extract(url)
 		if err != nil {
-			return importArticleResultMsg{url: url, err: err}
+			return importArticleResultMsg{url: url, tags: tags, err: err, gen: gen}
 		}
 
 		images := make([]storage.ImageFile, len(result.Images))
@@ -308,50 +579,313 @@ func (m Model) importExtractAndSave(url string) tea.Cmd {
 		if err != nil {
 			var existsErr *storage.ErrArticleExists
 			if errors.As(err, &existsErr) {
-				return importArticleResultMsg{url: url, title: result.Title, skipped: true}
+				return importArticleResultMsg{url: url, title: result.Title, skipped: true, gen: gen}
 			}
-			return importArticleResultMsg{url: url, title: result.Title, err: err}
+			return importArticleResultMsg{url: url, title: result.Title, tags: tags, err: err, gen: gen}
+		}
+
+		msg := importArticleResultMsg{url: url, title: result.Title, tags: tags, gen: gen}
+		if len(tags) > 0 {
+			msg.tagErr = addTags(store, store.ArticlePath(result.Title), tags)
 		}
+		return msg
+	}
+}
 
-		return importArticleResultMsg{url: url, title: result.Title}
+// addTags adds tags to those already on the article at filePath.
+func addTags(store *storage.Store, filePath string, tags []string) error {
+	article, err := store.Get(filePath)
+	if err != nil {
+		return err
 	}
+	merged := article.Meta.Tags
+	for _, t := range tags {
+		if !slices.Contains(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	return store.UpdateTags(filePath, merged)
 }
 
 // handleImportArticleResult processes the result of a single import and
-// advances the queue or finishes.
+// dispatches more of the queue or finishes.
 func (m Model) handleImportArticleResult(msg importArticleResultMsg) (tea.Model, tea.Cmd) {
-	// Advance the queue.
-	if len(m.importQueue) > 0 {
-		m.importQueue = m.importQueue[1:]
+	// Discard results from cancelled batches.
+	if msg.gen != m.importGen {
+		return m, nil
+	}
+	if i := slices.IndexFunc(m.importInFlight, func(item importItem) bool { return item.url == msg.url }); i >= 0 {
+		m.importInFlight = append(m.importInFlight[:i:i], m.importInFlight[i+1:]...)
 	}
 
-	if msg.err != nil {
-		m.importErrors = append(m.importErrors, fmt.Sprintf("%s: %s", msg.url, msg.err.Error()))
-	} else if msg.skipped {
+	switch {
+	case msg.err != nil:
+		m.importErrors = append(m.importErrors, importFailure{url: msg.url, tags: msg.tags, err: msg.err})
+		m.logger.Error("import failed", "url", msg.url, "err", msg.err)
+	case msg.skipped:
 		m.importSkipped++
+		m.logger.Info("import skipped (already saved)", "url", msg.url, "title", msg.title)
+	default:
+		m.logger.Info("imported", "url", msg.url, "title", msg.title)
+		if msg.tagErr != nil {
+			m.logger.Warn("tagging imported article failed", "url", msg.url, "tags", msg.tags, "err", msg.tagErr)
+		}
 	}
 
 	m.importDone++
 
-	if len(m.importQueue) == 0 {
-		m.state = stateList
-		m.refreshArticles()
-		m.statusMsg = m.importSummary()
+	if len(m.importQueue) == 0 && len(m.importInFlight) == 0 {
+		return m.finishImport()
+	}
+	if m.importPaused {
+		m.setPausedImport(m.pausedItems())
+		if len(m.importInFlight) == 0 {
+			m.logger.Info("import paused", "remaining", len(m.importQueue))
+		}
 		return m, nil
 	}
 
-	return m, m.importExtractAndSave(m.importQueue[0])
+	return m.dispatchImports()
+}
+
+// finishImport ends the batch import, landing on the failure review screen
+// if anything failed.
+func (m Model) finishImport() (tea.Model, tea.Cmd) {
+	m.importPaused = false
+	m.setPausedImport(nil)
+	m.state = stateList
+	m.refreshArticles()
+	m.logger.Info("import finished", "summary", m.importSummary())
+	m.statusMsg = m.withSourceWarnings(m.importSummary())
+	if len(m.importErrors) > 0 {
+		// Land on the failure review screen instead of the list.
+		m.state = stateImportFailures
+		m.failCursor = 0
+		m.failScroll = 0
+		m.failSelected = make(map[int]bool)
+	}
+	return m, m.measureTotalSize()
+}
+
+// toggleImportPause pauses or resumes the running import. Pausing stops
+// dispatching new URLs, lets those in flight finish, and persists the queue
+// so the import can be resumed after quitting.
+func (m Model) toggleImportPause() (tea.Model, tea.Cmd) {
+	if m.importPaused {
+		m.importPaused = false
+		m.setPausedImport(nil)
+		m.logger.Info("import resumed", "remaining", len(m.importQueue))
+		if len(m.importInFlight) == 0 && len(m.importQueue) == 0 {
+			return m.finishImport()
+		}
+		var cmd tea.Cmd
+		m, cmd = m.dispatchImports()
+		return m, tea.Batch(m.spinner.Tick, cmd)
+	}
+	m.importPaused = true
+	m.setPausedImport(m.pausedItems())
+	return m, nil
+}
+
+// pausedItems returns what a paused import has left to do: the URLs still
+// in flight, which may yet fail or be cut short by quitting, then the
+// queue. Those that do get saved are skipped as duplicates on resuming.
+func (m Model) pausedItems() []importItem {
+	return append(slices.Clone(m.importInFlight), m.importQueue...)
+}
+
+// leavePausedImport returns to the list, keeping the paused queue for the
+// next import.
+func (m Model) leavePausedImport() (tea.Model, tea.Cmd) {
+	if len(m.importInFlight) > 0 {
+		// Let in-flight articles finish; their results are discarded, but
+		// they're saved all the same. They stay in the paused queue in
+		// case they don't, and are skipped on resuming if they were.
+		m.importGen++
+	}
+	m.state = stateList
+	m.suppressQuit = true
+	m.refreshArticles()
+	m.statusMsg = m.msgs.format("status.import_left_paused", len(m.pausedItems()), m.keys.Import.Help().Key)
+	m.importInFlight = nil
+	m.importQueue = nil
+	m.importPaused = false
+	return m, nil
+}
+
+// resumePausedImport starts a new batch from the queue saved by a paused
+// import, possibly from an earlier session.
+func (m Model) resumePausedImport() (tea.Model, tea.Cmd) {
+	var items []importItem
+	for _, q := range m.appState.ImportQueue {
+		items = append(items, importItem{url: q.URL, tags: q.Tags})
+	}
+	m.setPausedImport(nil)
+	m.err = nil
+	m.statusMsg = ""
+	m.importWarnings = nil
+	return m.startImport(items)
+}
+
+// handleConfirmResumeKeys answers the prompt shown when an import is
+// started while one is paused: r resumes it, n drops it and starts the
+// new one.
+func (m Model) handleConfirmResumeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r", "R", "enter":
+		return m.resumePausedImport()
+	case "n", "N":
+		m.logger.Info("paused import dropped", "remaining", len(m.appState.ImportQueue))
+		m.setPausedImport(nil)
+		if m.importWindow {
+			return m.gatherWindowImport()
+		}
+		return m.gatherSafariImport()
+	case "esc", "ctrl+c", "q":
+		m.state = stateList
+		m.suppressQuit = true
+	}
+	return m, nil
+}
+
+// setPausedImport persists items as the paused import queue; nil clears it.
+func (m Model) setPausedImport(items []importItem) {
+	queue := make([]state.QueuedImport, len(items))
+	for i, item := range items {
+		queue[i] = state.QueuedImport{URL: item.url, Tags: item.tags}
+	}
+	if len(queue) == 0 && len(m.appState.ImportQueue) == 0 {
+		return
+	}
+	if len(queue) == 0 {
+		queue = nil
+	}
+	m.appState.ImportQueue = queue
+	m.saveState()
 }
 
 // importSummary returns a human-readable summary of the batch import.
 func (m Model) importSummary() string {
 	saved := m.importDone - m.importSkipped - len(m.importErrors)
-	parts := []string{fmt.Sprintf("Import complete: %d saved", saved)}
+	parts := []string{m.msgs.format("status.import_complete", saved)}
 	if m.importSkipped > 0 {
-		parts = append(parts, fmt.Sprintf("%d skipped", m.importSkipped))
+		parts = append(parts, m.msgs.format("view.import_skipped", m.importSkipped))
 	}
 	if len(m.importErrors) > 0 {
-		parts = append(parts, fmt.Sprintf("%d failed", len(m.importErrors)))
+		parts = append(parts, m.msgs.format("view.import_failed", len(m.importErrors)))
 	}
 	return strings.Join(parts, ", ")
 }
+
+// withSourceWarnings appends to msg the Safari sources that couldn't be
+// read for this import and why, e.g. "iCloud tabs unavailable (…)".
+func (m Model) withSourceWarnings(msg string) string {
+	if len(m.importWarnings) == 0 {
+		return msg
+	}
+	var parts []string
+	for _, w := range m.importWarnings {
+		// Warnings read "<source>: <reason>"; keep the reason's first line.
+		source, reason, _ := strings.Cut(w.Error(), ": ")
+		reason, _, _ = strings.Cut(reason, "\n")
+		parts = append(parts, m.msgs.format("status.source_unavailable", source, truncateString(reason, 40, m.ellipsis)))
+	}
+	return msg + " · " + strings.Join(parts, "; ")
+}
+
+// importFailure records a URL that failed to import and why.
+type importFailure struct {
+	url  string
+	tags []string // from the import buffer, reapplied on retry
+	err  error
+}
+
+// handleImportFailuresKeys handles keys on the post-import failure review
+// screen: navigate, select failures, retry them, or copy the list.
+func (m Model) handleImportFailuresKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.failCursor > 0 {
+			m.failCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.failCursor < len(m.importErrors)-1 {
+			m.failCursor++
+		}
+	case msg.String() == " ":
+		m.failSelected[m.failCursor] = !m.failSelected[m.failCursor]
+	case key.Matches(msg, m.keys.Probe):
+		return m.probeURL(m.importErrors[m.failCursor].url)
+	case msg.String() == "r":
+		// Retry the selected failures, or all of them if none are selected.
+		var items []importItem
+		for i, f := range m.importErrors {
+			if len(m.failSelected) == 0 || m.failSelected[i] {
+				items = append(items, importItem{url: f.url, tags: f.tags})
+			}
+		}
+		if len(items) == 0 {
+			for _, f := range m.importErrors {
+				items = append(items, importItem{url: f.url, tags: f.tags})
+			}
+		}
+		m.statusMsg = ""
+		m.importWarnings = nil // already reported for the original import
+		return m.startImport(items)
+	case msg.String() == "y":
+		var sb strings.Builder
+		for _, f := range m.importErrors {
+			sb.WriteString(fmt.Sprintf("%s\t%s\n", f.url, f.err.Error()))
+		}
+		if err := copyToClipboard(sb.String()); err != nil {
+			m.err = err
+		} else {
+			m.statusMsg = m.msgs.format("status.copied_failed_urls", len(m.importErrors))
+		}
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+		m.importErrors = nil
+		m.failSelected = nil
+		m.statusMsg = ""
+	}
+	m.failScroll = clampScroll(m.failCursor, m.failScroll, m.failVisibleItems(), len(m.importErrors))
+	return m, nil
+}
+
+// renderImportFailures renders the list of failed imports with their errors.
+func (m Model) renderImportFailures() string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.Error.Render(m.msgs.format("view.import_failures", len(m.importErrors))))
+	sb.WriteString("\n\n")
+
+	contentWidth := m.width - 4
+	end := min(m.failScroll+m.failVisibleItems(), len(m.importErrors))
+	for i := m.failScroll; i < end; i++ {
+		f := m.importErrors[i]
+		if i > m.failScroll {
+			sb.WriteString("\n\n")
+		}
+		check := "[ ] "
+		if m.failSelected[i] {
+			check = "[x] "
+		}
+		url := truncateString(f.url, contentWidth-2-len(check), m.ellipsis)
+		if i == m.failCursor {
+			sb.WriteString(m.styles.SelectionMarker.Render(""))
+			sb.WriteString(m.styles.SelectedTitle.Render(check + url))
+		} else {
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.ListItemTitle.Render(check + url))
+		}
+		sb.WriteString("\n  ")
+		sb.WriteString(m.styles.ListItemDesc.Render(truncateString(f.err.Error(), contentWidth-2, m.ellipsis)))
+	}
+	return sb.String()
+}
+
+// failVisibleItems returns the number of failures that fit on screen, leaving
+// room for the heading above the list.
+func (m Model) failVisibleItems() int {
+	return max(1, m.calcVisibleItems()-1)
+}
@@ -0,0 +1,445 @@
+package tui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/irfansharif/shelf/pkg/extractor"
+	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/state"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func importTestTabs() map[string][]safari.Tab {
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+	return map[string][]safari.Tab{
+		"local": {
+			{URL: "https://b.com/1", Title: "beta one", LastViewed: at(1)},
+			{URL: "https://a.com/1", Title: "Alpha one", LastViewed: at(5)},
+			{URL: "https://b.com/2", Title: "Beta two", LastViewed: at(3)},
+			{URL: "https://saved.com/", Title: "Saved", LastViewed: at(9)},
+		},
+		"icloud": {
+			{URL: "https://a.com/1", Title: "Alpha one", LastViewed: at(7)},
+			{URL: "https://c.com/1", Title: "Gamma", LastViewed: at(2)},
+		},
+	}
+}
+
+// importURLs returns the URLs in an import buffer, in order.
+func importURLs(content string) []string {
+	var urls []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "# ")
+		if strings.HasPrefix(line, "https://") {
+			urls = append(urls, line)
+		}
+	}
+	return urls
+}
+
+func TestFormatImportFileDefault(t *testing.T) {
+	saved := map[string]bool{"https://saved.com/": true}
+	got := formatImportFile(nil, importTestTabs(), saved, nil, importSortSource, nil)
+	want := `# Safari Import — uncomment URLs to import, then :wq
+# Use zo/zc to unfold/fold groups, zR to open all.
+# Append #tags after a URL to tag it on import: https://… #rust #async
+#
+
+# === Local Tabs (3) === {{{1
+
+# --- a.com (1) --- {{{2
+	# Alpha one
+	# https://a.com/1
+# }}}2
+
+# --- b.com (2) --- {{{2
+	# Beta two
+	# https://b.com/2
+
+	# beta one
+	# https://b.com/1
+# }}}2
+# }}}1
+
+# === iCloud Tabs (2) === {{{1
+
+# --- a.com (1) --- {{{2
+	# Alpha one
+	# https://a.com/1
+# }}}2
+
+# --- c.com (1) --- {{{2
+	# Gamma
+	# https://c.com/1
+# }}}2
+# }}}1
+
+# vim: ft=conf foldmethod=marker foldlevel=0
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatImportFileSort(t *testing.T) {
+	saved := map[string]bool{"https://saved.com/": true}
+	for _, tc := range []struct {
+		by   importSort
+		want []string
+	}{
+		{importSortRecent, []string{"https://a.com/1", "https://b.com/2", "https://c.com/1", "https://b.com/1"}},
+		{importSortDomain, []string{"https://a.com/1", "https://b.com/2", "https://b.com/1", "https://c.com/1"}},
+		{importSortTitle, []string{"https://a.com/1", "https://b.com/1", "https://b.com/2", "https://c.com/1"}},
+	} {
+		t.Run(string(tc.by), func(t *testing.T) {
+			content := formatImportFile(nil, importTestTabs(), saved, nil, tc.by, nil)
+			got := importURLs(content)
+			if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+			if strings.Contains(content, "=== ") {
+				t.Errorf("unexpected source groups:\n%s", content)
+			}
+			// The buffer must still round-trip through the parser once
+			// uncommented.
+			path := filepath.Join(t.TempDir(), "import.txt")
+			uncommented := strings.ReplaceAll(content, "# https://", "https://")
+			if err := os.WriteFile(path, []byte(uncommented), 0644); err != nil {
+				t.Fatal(err)
+			}
+			items, err := parseImportFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != len(tc.want) {
+				t.Errorf("parsed %d URLs, want %d", len(items), len(tc.want))
+			}
+		})
+	}
+}
+
+func TestFormatImportFileSources(t *testing.T) {
+	tabs := importTestTabs()
+	tabs["readinglist"] = []safari.Tab{{URL: "https://d.com/1", Title: "Delta", LastViewed: time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC)}}
+	sources := []string{"readinglist", "local"}
+
+	content := formatImportFile(nil, tabs, nil, nil, importSortSource, sources)
+	reading := strings.Index(content, "=== Reading List (1) ===")
+	local := strings.Index(content, "=== Local Tabs (4) ===")
+	if reading < 0 || local < 0 || reading > local {
+		t.Errorf("want Reading List, then Local Tabs:\n%s", content)
+	}
+	if strings.Contains(content, "iCloud") || strings.Contains(content, "https://c.com/1") {
+		t.Errorf("disabled source listed:\n%s", content)
+	}
+
+	got := importURLs(formatImportFile(nil, tabs, nil, nil, importSortRecent, sources))
+	want := []string{"https://saved.com/", "https://a.com/1", "https://d.com/1", "https://b.com/2", "https://b.com/1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("by recency: got %v, want %v", got, want)
+	}
+}
+
+func TestFormatWindowImportFile(t *testing.T) {
+	tabs := []safari.Tab{
+		{URL: "https://b.com/1", Title: "Beta one"},
+		{URL: "https://saved.com/", Title: "Saved"},
+		{URL: "https://a.com/1"},
+		{URL: "https://b.com/1", Title: "Beta one again"},
+	}
+	got := formatWindowImportFile(nil, tabs, map[string]bool{"https://saved.com/": true})
+	want := `# Safari Import (front window) — comment out URLs to skip, then :wq
+# Append #tags after a URL to tag it on import: https://… #rust #async
+
+# Beta one
+https://b.com/1
+
+# https://a.com/1
+https://a.com/1
+
+# vim: ft=conf
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The tabs are imported as they are, in tab order.
+	path := filepath.Join(t.TempDir(), "import.txt")
+	if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := parseImportFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []importItem{{url: "https://b.com/1"}, {url: "https://a.com/1"}}; !reflect.DeepEqual(items, want) {
+		t.Errorf("parsed %+v, want %+v", items, want)
+	}
+}
+
+func TestParseImportFileTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.txt")
+	content := `# https://skipped.com/
+https://a.com/1
+	https://b.com/post#intro #rust #async #rust
+https://c.com/ notes #go # #
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := parseImportFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []importItem{
+		{url: "https://a.com/1"},
+		{url: "https://b.com/post#intro", tags: []string{"rust", "async"}},
+		{url: "https://c.com/", tags: []string{"go"}},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %+v, want %+v", items, want)
+	}
+}
+
+func TestEstimateImport(t *testing.T) {
+	items := func(urls ...string) []importItem {
+		var items []importItem
+		for _, u := range urls {
+			items = append(items, importItem{url: u})
+		}
+		return items
+	}
+	m := Model{importConcurrency: 2, importLimiter: newHostLimiter(0.5, 1)}
+	for _, tc := range []struct {
+		items []importItem
+		want  time.Duration
+	}{
+		{nil, 0},
+		// Four hosts, two at a time.
+		{items("https://a.com/", "https://b.com/", "https://c.com/", "https://d.com/"), 2 * importFetchEstimate},
+		// One host: three requests spaced two seconds apart.
+		{items("https://a.com/1", "https://a.com/2", "https://a.com/3"), 4*time.Second + importFetchEstimate},
+	} {
+		if got := m.estimateImport(tc.items); got != tc.want {
+			t.Errorf("estimateImport(%d items) = %s, want %s", len(tc.items), got, tc.want)
+		}
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	l := newHostLimiter(0.5, 1)
+	start := time.Now()
+	if d := l.take("a.com", start); d != 0 {
+		t.Fatalf("first request waits %s", d)
+	}
+	// A second request to the host must wait two seconds, and asking
+	// doesn't push that back.
+	for range 2 {
+		if d := l.take("a.com", start); d != 2*time.Second {
+			t.Fatalf("second request waits %s, want 2s", d)
+		}
+	}
+	if d := l.take("a.com", start.Add(time.Second)); d != time.Second {
+		t.Errorf("a second later: waits %s, want 1s", d)
+	}
+	if d := l.take("a.com", start.Add(2*time.Second)); d != 0 {
+		t.Errorf("two seconds later: waits %s", d)
+	}
+	// Other hosts don't wait on it.
+	if d := l.take("b.com", start); d != 0 {
+		t.Errorf("other host waits %s", d)
+	}
+}
+
+func TestDispatchImportsHeldBack(t *testing.T) {
+	m := Model{importConcurrency: 2, importLimiter: newHostLimiter(0.5, 1)}
+	m.importQueue = []importItem{{url: "https://a.com/1"}, {url: "https://a.com/2"}, {url: "https://b.com/1"}, {url: "https://a.com/3"}}
+
+	// a.com/2 is held back without taking a slot, so b.com goes ahead, and
+	// a retry is scheduled for when a.com may go again.
+	m, cmd := m.dispatchImports()
+	want := []importItem{{url: "https://a.com/2"}, {url: "https://a.com/3"}}
+	if len(m.importInFlight) != 2 || !reflect.DeepEqual(m.importQueue, want) || !m.importRetrying || cmd == nil {
+		t.Fatalf("in flight %d, queue %+v, retrying %v", len(m.importInFlight), m.importQueue, m.importRetrying)
+	}
+	// A retry for an earlier batch is ignored.
+	next, _ := m.handleImportRetry(importRetryMsg{gen: m.importGen + 1})
+	if !next.(Model).importRetrying {
+		t.Errorf("stale retry handled")
+	}
+}
+
+func TestPausedImport(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:             store,
+		appState:          testState(t),
+		logger:            slog.New(slog.DiscardHandler),
+		importConcurrency: 1,
+		importLimiter:     newHostLimiter(0, 1),
+	}
+	urls := func(queue []state.QueuedImport) []string {
+		var got []string
+		for _, q := range queue {
+			got = append(got, q.URL)
+		}
+		return got
+	}
+	next, _ := m.startImport([]importItem{{url: "https://a.com/1"}, {url: "https://a.com/2"}, {url: "https://a.com/3"}})
+	m = next.(Model)
+
+	// The paused queue includes the URL in flight, until it's done.
+	next, _ = m.toggleImportPause()
+	m = next.(Model)
+	if got, want := urls(m.appState.ImportQueue), []string{"https://a.com/1", "https://a.com/2", "https://a.com/3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("paused queue = %q, want %q", got, want)
+	}
+	next, _ = m.handleImportArticleResult(importArticleResultMsg{url: "https://a.com/1", gen: m.importGen})
+	m = next.(Model)
+	if got, want := urls(m.appState.ImportQueue), []string{"https://a.com/2", "https://a.com/3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("paused queue after a.com/1 = %q, want %q", got, want)
+	}
+	next, _ = m.leavePausedImport()
+	m = next.(Model)
+
+	// Importing again asks whether to resume; r does.
+	next, _ = m.startWindowImport()
+	if next.(Model).state != stateConfirmResume {
+		t.Fatalf("state = %v, want stateConfirmResume", next.(Model).state)
+	}
+	resumed, _ := next.(Model).handleConfirmResumeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if r := resumed.(Model); r.state != stateImporting || r.importTotal != 2 {
+		t.Errorf("after r: state %v, %d to import, want importing 2", r.state, r.importTotal)
+	}
+	// n drops it for a new import.
+	fresh, _ := next.(Model).handleConfirmResumeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if f := fresh.(Model); f.state != stateGatheringTabs || len(f.appState.ImportQueue) != 0 {
+		t.Errorf("after n: state %v, paused queue %d, want gathering tabs and none", f.state, len(f.appState.ImportQueue))
+	}
+}
+
+// TestCancelImportMidSave cancels an import while its article is in
+// flight, lets the save carry on as it does after a cancel, waits for it as
+// quitting does, and checks the article ends up whole, with no staging
+// directory left behind.
+func TestCancelImportMidSave(t *testing.T) {
+	const numImages = 200
+	image := base64.StdEncoding.EncodeToString(make([]byte, 64<<10))
+	cancelled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-cancelled
+		var content strings.Builder
+		content.WriteString("---\ntitle: Big\nsource: https://a.com/big\n---\n\n")
+		var images []map[string]string
+		for i := range numImages {
+			path := fmt.Sprintf("images/%d.png", i)
+			fmt.Fprintf(&content, "![](%s)\n", path)
+			images = append(images, map[string]string{"path": path, "data": image})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"title": "Big", "content": content.String(), "images": images})
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:             store,
+		extract:           extractor.New(srv.URL),
+		keys:              DefaultKeyMap(),
+		appState:          testState(t),
+		logger:            slog.New(slog.DiscardHandler),
+		importConcurrency: 1,
+		importLimiter:     newHostLimiter(0, 1),
+	}
+	next, _ := m.startImport([]importItem{{url: "https://a.com/big"}})
+	m = next.(Model)
+
+	// Run the import as Bubble Tea would, and cancel it while the article
+	// is in flight.
+	result := make(chan tea.Msg, 1)
+	cmd := m.importExtractAndSave(m.importInFlight[0])
+	go func() { result <- cmd() }()
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.state != stateList {
+		t.Fatalf("state after cancel = %v, want stateList", m.state)
+	}
+	close(cancelled)
+
+	// The cancelled import's result is discarded, but the article is saved
+	// all the same.
+	next, _ = m.Update(<-result)
+	if m := next.(Model); m.state != stateList || m.importDone != 0 {
+		t.Errorf("cancelled result handled: state %v, done %d", m.state, m.importDone)
+	}
+	store.Wait()
+	if left, _ := filepath.Glob(filepath.Join(dir, "articles", ".*")); len(left) > 0 {
+		t.Errorf("staging directories left: %v", left)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles", "big", "index.md")); err != nil {
+		t.Errorf("cancelled article not saved: %v", err)
+	}
+	pngs, err := filepath.Glob(filepath.Join(dir, "articles", "big", "images", "*.png"))
+	if err != nil || len(pngs) != numImages {
+		t.Errorf("saved article has %d of %d images (%v)", len(pngs), numImages, err)
+	}
+}
+
+func TestImportPreviewURLs(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContent("Saved", "---\ntitle: Saved\nsource: https://a.com/saved\n---\n\nBody.\n", nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:             store,
+		styles:            DefaultStyles(),
+		importConcurrency: 2,
+		importLimiter:     newHostLimiter(0, 1),
+		width:             80,
+		height:            30,
+	}
+	next, _ := m.openImportPreview("", []importItem{
+		{url: "a.com/saved"},
+		{url: "localhost:8080/draft", tags: []string{"dev"}},
+		{url: "not a url"},
+		{url: "https://b.com/new"},
+	})
+	m = next.(Model)
+
+	// Schemeless lines are imported as https, and match what's saved.
+	want := []importItem{{url: "https://localhost:8080/draft", tags: []string{"dev"}}, {url: "https://b.com/new"}}
+	if got := m.importPreviewNew(); !reflect.DeepEqual(got, want) {
+		t.Errorf("would import %+v, want %+v", got, want)
+	}
+	if m.importPreview[0].savedAs != "Saved" {
+		t.Errorf("a.com/saved not matched to the saved article: %+v", m.importPreview[0])
+	}
+	// Invalid lines are left out, saying why.
+	if p := m.importPreview[2]; p.invalid == nil || p.isNew() {
+		t.Errorf("invalid line previewed as %+v", p)
+	}
+	if view := m.renderImportPreview(); !strings.Contains(view, "1 invalid (e to fix)") || !strings.Contains(view, `invalid URL "not a url"`) {
+		t.Errorf("preview doesn't show the invalid line:\n%s", view)
+	}
+}
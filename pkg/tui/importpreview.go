@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// importFetchEstimate is a rough time to fetch and extract one article, used
+// only for the dry-run estimate.
+const importFetchEstimate = 3 * time.Second
+
+// importPreviewItem is a URL from the edited import buffer and what
+// importing it would do.
+type importPreviewItem struct {
+	importItem
+	savedAs   string // title of the existing article, if already saved
+	duplicate bool   // listed earlier in the same buffer
+	invalid   error  // why the URL can't be imported; see validateAndNormalizeURL
+}
+
+// isNew reports whether importing the item would fetch anything.
+func (p importPreviewItem) isNew() bool {
+	return p.savedAs == "" && !p.duplicate && p.invalid == nil
+}
+
+// openImportPreview shows a dry run of importing items: which URLs are new
+// and which are already saved, and roughly how long it would take. Nothing
+// is fetched until the user confirms. bufferPath is the edited import buffer,
+// reopened if the user goes back to edit it.
+func (m Model) openImportPreview(bufferPath string, items []importItem) (tea.Model, tea.Cmd) {
+	savedTitles := make(map[string]string)
+	for _, a := range m.store.List() {
+		if a.SourceURL != "" {
+			savedTitles[a.SourceURL] = a.Title
+		}
+	}
+	seen := make(map[string]bool)
+	m.importPreview = make([]importPreviewItem, len(items))
+	for i, item := range items {
+		url, err := validateAndNormalizeURL(item.url)
+		if err != nil {
+			m.importPreview[i] = importPreviewItem{importItem: item, invalid: err}
+			continue
+		}
+		item.url = url
+		m.importPreview[i] = importPreviewItem{
+			importItem: item,
+			savedAs:    savedTitles[item.url],
+			duplicate:  seen[item.url],
+		}
+		seen[item.url] = true
+	}
+	m.importBufferPath = bufferPath
+	m.importPreviewCursor = 0
+	m.importPreviewScroll = 0
+	m.state = stateImportPreview
+	return m, nil
+}
+
+// importPreviewNew returns the previewed items that would be imported.
+func (m Model) importPreviewNew() []importItem {
+	var items []importItem
+	for _, p := range m.importPreview {
+		if p.isNew() {
+			items = append(items, p.importItem)
+		}
+	}
+	return items
+}
+
+// closeImportPreview removes the import buffer and clears the preview.
+func (m Model) closeImportPreview() Model {
+	if m.importBufferPath != "" {
+		os.Remove(m.importBufferPath)
+	}
+	m.importBufferPath = ""
+	m.importPreview = nil
+	return m
+}
+
+func (m Model) handleImportPreviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.importPreviewCursor > 0 {
+			m.importPreviewCursor--
+		}
+		m.importPreviewScroll = clampScroll(m.importPreviewCursor, m.importPreviewScroll, m.importPreviewVisibleItems(), len(m.importPreview))
+	case key.Matches(msg, m.keys.Down):
+		if m.importPreviewCursor < len(m.importPreview)-1 {
+			m.importPreviewCursor++
+		}
+		m.importPreviewScroll = clampScroll(m.importPreviewCursor, m.importPreviewScroll, m.importPreviewVisibleItems(), len(m.importPreview))
+	case key.Matches(msg, m.keys.Submit), msg.String() == "y":
+		items := m.importPreviewNew()
+		m = m.closeImportPreview()
+		if len(items) == 0 {
+			m.state = stateList
+			m.statusMsg = m.withSourceWarnings(m.msgs.text("status.nothing_new"))
+			return m, nil
+		}
+		return m.startImport(items)
+	case msg.String() == "e":
+		// Back to the editor; the buffer keeps the user's edits.
+		return m, editImportBuffer(m.importBufferPath)
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m = m.closeImportPreview()
+		m.state = stateList
+		m.suppressQuit = true
+		m.statusMsg = m.withSourceWarnings(m.msgs.text("status.import_cancelled"))
+	}
+	return m, nil
+}
+
+// estimateImport returns roughly how long importing items would take:
+// articles are fetched importConcurrency at a time, but requests to the
+// same host are spaced out by the import rate limit.
+func (m Model) estimateImport(items []importItem) time.Duration {
+	if len(items) == 0 {
+		return 0
+	}
+	batches := (len(items) + m.importConcurrency - 1) / max(1, m.importConcurrency)
+	estimate := time.Duration(batches) * importFetchEstimate
+
+	if rate := m.importLimiter.rate; rate > 0 {
+		perHost := make(map[string]int)
+		for _, item := range items {
+			perHost[extractDomain(item.url)]++
+		}
+		for _, n := range perHost {
+			spaced := time.Duration(float64(n-1)/rate*float64(time.Second)) + importFetchEstimate
+			estimate = max(estimate, spaced)
+		}
+	}
+	return estimate
+}
+
+// formatEstimate renders an import time estimate coarsely, e.g. "~40s" or
+// "~3m".
+func formatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("~%ds", int(d.Round(time.Second).Seconds()))
+	}
+	return fmt.Sprintf("~%dm", int(d.Round(time.Minute).Minutes()))
+}
+
+func (m Model) renderImportPreview() string {
+	items := m.importPreviewNew()
+	var sb strings.Builder
+	counts := []string{m.msgs.format("view.preview_new", len(items))}
+	invalid := 0
+	for _, p := range m.importPreview {
+		if p.invalid != nil {
+			invalid++
+		}
+	}
+	if skipped := len(m.importPreview) - len(items) - invalid; skipped > 0 {
+		counts = append(counts, m.msgs.format("view.preview_skipped", skipped))
+	}
+	if invalid > 0 {
+		counts = append(counts, m.msgs.format("view.preview_invalid", invalid))
+	}
+	heading := strings.Join(counts, ", ")
+	if len(items) > 0 {
+		heading += " · " + m.msgs.format("view.preview_estimate", formatEstimate(m.estimateImport(items)))
+	}
+	sb.WriteString(m.styles.Muted.Render(m.msgs.format("view.preview_heading", heading)))
+	sb.WriteString("\n\n")
+
+	contentWidth := m.width - 4
+	end := min(m.importPreviewScroll+m.importPreviewVisibleItems(), len(m.importPreview))
+	for i := m.importPreviewScroll; i < end; i++ {
+		p := m.importPreview[i]
+		if i > m.importPreviewScroll {
+			sb.WriteString("\n\n")
+		}
+		url := truncateString(p.url, contentWidth-2, m.ellipsis)
+		switch {
+		case i == m.importPreviewCursor:
+			sb.WriteString(m.styles.SelectionMarker.Render(""))
+			sb.WriteString(m.styles.SelectedTitle.Render(url))
+		case p.isNew():
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.ListItemTitle.Render(url))
+		default:
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.Muted.Render(url))
+		}
+
+		var desc string
+		switch {
+		case p.invalid != nil:
+			desc = p.invalid.Error()
+		case p.duplicate:
+			desc = m.msgs.text("view.preview_repeated")
+		case p.savedAs != "":
+			desc = m.msgs.format("view.preview_saved_as", p.savedAs)
+		default:
+			desc = m.msgs.text("view.preview_is_new")
+		}
+		if len(p.tags) > 0 {
+			desc += " · " + m.msgs.format("view.tags", strings.Join(p.tags, ", "))
+		}
+		sb.WriteString("\n  ")
+		sb.WriteString(m.styles.ListItemDesc.Render(truncateString(desc, contentWidth-2, m.ellipsis)))
+	}
+	return sb.String()
+}
+
+// importPreviewVisibleItems returns the number of previewed URLs that fit on
+// screen, leaving room for the heading above the list.
+func (m Model) importPreviewVisibleItems() int {
+	return max(1, m.calcVisibleItems()-1)
+}
@@ -28,6 +28,33 @@ func NewURLInput(styles Styles) URLInputModel {
 	}
 }
 
+// NewTitleInput creates an input for a new note's title, styled like the
+// URL input.
+func NewTitleInput(styles Styles) URLInputModel {
+	m := NewURLInput(styles)
+	m.textInput.Placeholder = "Note title"
+	m.textInput.CharLimit = 200
+	return m
+}
+
+// NewArchiveNoteInput creates an input for why an article is being
+// archived, styled like the URL input.
+func NewArchiveNoteInput(styles Styles) URLInputModel {
+	m := NewURLInput(styles)
+	m.textInput.Placeholder = "Why archive it? (optional)"
+	m.textInput.CharLimit = 200
+	return m
+}
+
+// NewTagInput creates an input for a tag's new name, styled like the URL
+// input.
+func NewTagInput(styles Styles) URLInputModel {
+	m := NewURLInput(styles)
+	m.textInput.Placeholder = "New tag name"
+	m.textInput.CharLimit = 64
+	return m
+}
+
 // Init initializes the URL input model.
 func (m URLInputModel) Init() tea.Cmd {
 	return textinput.Blink
@@ -181,6 +208,13 @@ func (m SearchInputModel) Clear() SearchInputModel {
 	return m
 }
 
+// SetValue replaces the search query, placing the cursor at the end.
+func (m SearchInputModel) SetValue(s string) SearchInputModel {
+	m.textInput.SetValue(s)
+	m.textInput.CursorEnd()
+	return m
+}
+
 // IsActive returns whether search is active.
 func (m SearchInputModel) IsActive() bool {
 	return m.active
@@ -11,15 +11,45 @@ type KeyMap struct {
 	Bottom key.Binding
 
 	// Actions
-	Open         key.Binding
-	Add          key.Binding
-	Import       key.Binding
-	Delete       key.Binding
-	Archive      key.Binding
-	ShowArchive  key.Binding
-	Search       key.Binding
-	Reload       key.Binding
-	SafariReload key.Binding
+	Open          key.Binding
+	OpenEditor    key.Binding
+	OpenPager     key.Binding
+	OpenBrowser   key.Binding
+	OpenPreview   key.Binding
+	OpenDataDir   key.Binding
+	Reveal        key.Binding
+	Add           key.Binding
+	SaveTab       key.Binding
+	NewNote       key.Binding
+	Import        key.Binding
+	ImportWindow  key.Binding
+	PauseImport   key.Binding
+	Delete        key.Binding
+	Archive       key.Binding
+	Pin           key.Binding
+	Lock          key.Binding
+	ClearWarnings key.Binding
+	Peek          key.Binding
+	Export        key.Binding
+	MoveUp        key.Binding
+	MoveDown      key.Binding
+	ResetProgress key.Binding
+	Undo          key.Binding
+	Redo          key.Binding
+	ShowArchive   key.Binding
+	Search        key.Binding
+	Find          key.Binding
+	SavedSearch   key.Binding
+	Reload        key.Binding
+	SafariReload  key.Binding
+	Yank          key.Binding
+	Images        key.Binding
+	Notes         key.Binding
+	Tags          key.Binding
+	FocusTags     key.Binding
+	RenameTag     key.Binding
+	ShowError     key.Binding
+	Probe         key.Binding // only enabled with SHELF_DEBUG=1
 
 	// General
 	Quit   key.Binding
@@ -49,15 +79,55 @@ func DefaultKeyMap() KeyMap {
 		),
 		Open: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "open in neovim"),
+			key.WithHelp("enter", "open"),
+		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "open in editor"),
+		),
+		OpenPager: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "open in pager"),
+		),
+		OpenBrowser: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open in browser"),
+		),
+		OpenPreview: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "preview"),
+		),
+		OpenDataDir: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "open data directory"),
+		),
+		Reveal: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "reveal in file manager"),
 		),
 		Add: key.NewBinding(
 			key.WithKeys("a"),
 			key.WithHelp("a", "add URL"),
 		),
+		SaveTab: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "save current safari tab"),
+		),
+		NewNote: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "new note"),
+		),
 		Import: key.NewBinding(
 			key.WithKeys("i"),
-			key.WithHelp("i", "import safari"),
+			key.WithHelp("i", "import safari / resume"),
+		),
+		ImportWindow: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "import front safari window"),
+		),
+		PauseImport: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause / resume import"),
 		),
 		Delete: key.NewBinding(
 			key.WithKeys("d"),
@@ -67,6 +137,55 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("x"),
 			key.WithHelp("x", "archive"),
 		),
+		Pin: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pin"),
+		),
+		Lock: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "lock"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "export listed"),
+		),
+		Peek: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "peek"),
+		),
+		ClearWarnings: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "clear warnings"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "move up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "move down"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "redo"),
+		),
+		ResetProgress: key.NewBinding(
+			key.WithKeys("0"),
+			key.WithHelp("0", "reset progress"),
+		),
+		ShowError: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "show the full error"),
+		),
+		Probe: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "debug fetch"),
+			key.WithDisabled(),
+		),
 		ShowArchive: key.NewBinding(
 			key.WithKeys("X"),
 			key.WithHelp("X", "show archived"),
@@ -75,6 +194,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
 		),
+		Find: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "find as you type"),
+		),
+		SavedSearch: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "saved searches"),
+		),
 		Reload: key.NewBinding(
 			key.WithKeys("r"),
 			key.WithHelp("r", "reload"),
@@ -83,6 +210,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("R"),
 			key.WithHelp("R", "refetch (safari)"),
 		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("yy/yb/yu", "yank path/body/URL"),
+		),
+		Images: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "images"),
+		),
+		Notes: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "notes"),
+		),
+		Tags: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tag sidebar"),
+		),
+		FocusTags: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "focus tags / list"),
+		),
+		RenameTag: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rename tag"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -107,11 +258,13 @@ func (k KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.Add, k.Import, k.Open, k.Delete, k.Archive, k.ShowArchive, k.Reload, k.SafariReload, k.Quit}
 }
 
-// FullHelp returns keybindings to show in the full help view.
+// FullHelp returns keybindings to show in the full help view of the list.
+// Keys that only act in the tag sidebar, such as RenameTag, are left out:
+// they reuse list keys.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Top, k.Bottom},
-		{k.Open, k.Add, k.Import, k.Delete, k.Archive, k.ShowArchive, k.Search, k.Reload, k.SafariReload},
+		{k.Open, k.OpenEditor, k.OpenPager, k.OpenBrowser, k.OpenPreview, k.OpenDataDir, k.Reveal, k.Peek, k.Add, k.SaveTab, k.NewNote, k.Import, k.ImportWindow, k.Delete, k.Archive, k.Pin, k.Lock, k.ClearWarnings, k.MoveUp, k.MoveDown, k.ResetProgress, k.Undo, k.Redo, k.ShowArchive, k.Search, k.Find, k.SavedSearch, k.Reload, k.SafariReload, k.Yank, k.Export, k.Images, k.Notes, k.Tags, k.FocusTags, k.ShowError, k.Probe},
 		{k.Quit, k.Cancel, k.Help},
 	}
 }
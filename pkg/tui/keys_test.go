@@ -0,0 +1,21 @@
+package tui
+
+import "testing"
+
+// TestFullHelpKeys checks that the list's full help doesn't show two
+// actions on one key.
+func TestFullHelpKeys(t *testing.T) {
+	keys := DefaultKeyMap()
+	keys.Probe.SetEnabled(true)
+	seen := make(map[string]string)
+	for _, col := range keys.FullHelp() {
+		for _, b := range col {
+			for _, k := range b.Keys() {
+				if prev, ok := seen[k]; ok {
+					t.Errorf("%q is bound to both %q and %q", k, prev, b.Help().Desc)
+				}
+				seen[k] = b.Help().Desc
+			}
+		}
+	}
+}
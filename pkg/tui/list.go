@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strings"
 	"time"
 
@@ -11,131 +12,147 @@ import (
 	"github.com/irfansharif/shelf/pkg/storage"
 )
 
-// formatRelativeTime returns a human-readable relative time string.
-func formatRelativeTime(t time.Time) string {
-	now := time.Now()
+// timeStyle is how the list shows when an article was saved.
+type timeStyle string
+
+const (
+	timeRelative timeStyle = "relative" // "3 days ago"
+	timeShort    timeStyle = "short"    // "3d"
+	timeAbsolute timeStyle = "absolute" // formatted with timeFormat.layout
+)
+
+// timeFormat configures formatRelativeTime. The zero value is relative.
+type timeFormat struct {
+	style  timeStyle
+	layout string // time.Format layout for timeAbsolute
+}
+
+// formatRelativeTime returns when t was, as seen at now, in format f.
+// Relative and short times share their buckets: minutes under an hour,
+// hours under a day, days under a week, weeks under 30 days, months under a
+// year, then years.
+func formatRelativeTime(msgs messages, t, now time.Time, f timeFormat) string {
+	switch f.style {
+	case timeAbsolute:
+		layout := f.layout
+		if layout == "" {
+			layout = time.DateOnly
+		}
+		return t.Local().Format(layout)
+	case timeShort:
+		return formatShortTime(msgs, now.Sub(t))
+	}
 	diff := now.Sub(t)
 
 	switch {
 	case diff < time.Minute:
-		return "just now"
+		return msgs.text("time.just_now")
 	case diff < time.Hour:
 		mins := int(diff.Minutes())
 		if mins == 1 {
-			return "1 min ago"
+			return msgs.text("time.minute_ago")
 		}
-		return fmt.Sprintf("%d mins ago", mins)
+		return msgs.format("time.minutes_ago", mins)
 	case diff < 24*time.Hour:
 		hours := int(diff.Hours())
 		if hours == 1 {
-			return "1 hour ago"
+			return msgs.text("time.hour_ago")
 		}
-		return fmt.Sprintf("%d hours ago", hours)
+		return msgs.format("time.hours_ago", hours)
 	case diff < 7*24*time.Hour:
 		days := int(diff.Hours() / 24)
 		if days == 1 {
-			return "1 day ago"
+			return msgs.text("time.day_ago")
 		}
-		return fmt.Sprintf("%d days ago", days)
+		return msgs.format("time.days_ago", days)
 	case diff < 30*24*time.Hour:
 		weeks := int(diff.Hours() / 24 / 7)
 		if weeks == 1 {
-			return "1 week ago"
+			return msgs.text("time.week_ago")
 		}
-		return fmt.Sprintf("%d weeks ago", weeks)
+		return msgs.format("time.weeks_ago", weeks)
 	case diff < 365*24*time.Hour:
 		months := int(diff.Hours() / 24 / 30)
 		if months == 1 {
-			return "1 month ago"
+			return msgs.text("time.month_ago")
 		}
-		return fmt.Sprintf("%d months ago", months)
+		return msgs.format("time.months_ago", months)
 	default:
 		years := int(diff.Hours() / 24 / 365)
 		if years == 1 {
-			return "1 year ago"
+			return msgs.text("time.year_ago")
 		}
-		return fmt.Sprintf("%d years ago", years)
+		return msgs.format("time.years_ago", years)
 	}
 }
 
-// formatFileSize returns a human-readable file size.
-func formatFileSize(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-	)
-
+// formatShortTime returns a compact age such as "5m" or "3d", for
+// formatRelativeTime's short style.
+func formatShortTime(msgs messages, diff time.Duration) string {
 	switch {
-	case bytes >= MB:
-		return fmt.Sprintf("%.1f MB", float64(bytes)/MB)
-	case bytes >= KB:
-		return fmt.Sprintf("%d KB", bytes/KB)
+	case diff < time.Minute:
+		return msgs.text("time.short_now")
+	case diff < time.Hour:
+		return msgs.format("time.short_minutes", int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		return msgs.format("time.short_hours", int(diff.Hours()))
+	case diff < 7*24*time.Hour:
+		return msgs.format("time.short_days", int(diff.Hours()/24))
+	case diff < 30*24*time.Hour:
+		return msgs.format("time.short_weeks", int(diff.Hours()/24/7))
+	case diff < 365*24*time.Hour:
+		return msgs.format("time.short_months", int(diff.Hours()/24/30))
 	default:
-		return fmt.Sprintf("%d B", bytes)
+		return msgs.format("time.short_years", int(diff.Hours()/24/365))
 	}
 }
 
-// truncateString truncates a string to the given display width, adding ellipsis if needed.
-func truncateString(s string, width int) string {
-	if width <= 3 {
+// truncateString truncates a string to the given display width, ending it
+// with ellipsis (the ellipsis setting) if needed.
+func truncateString(s string, width int, ellipsis string) string {
+	if width <= runewidth.StringWidth(ellipsis) {
 		return s
 	}
 	if runewidth.StringWidth(s) <= width {
 		return s
 	}
-	return runewidth.Truncate(s, width, "...")
+	return runewidth.Truncate(s, width, ellipsis)
 }
 
-// renderArticleItem renders a single article item for the list.
-func renderArticleItem(meta storage.ArticleMeta, selected bool, width int, styles Styles) string {
-	var sb strings.Builder
+// density is how much room each article takes in the list.
+type density string
 
-	titleWidth := width - 4 // Account for selection marker and padding
+const (
+	densityComfortable density = "comfortable" // title, then metadata and tags below
+	densityCompact     density = "compact"     // title, metadata and tags on one line
+)
 
-	title := truncateString(meta.Title, titleWidth)
-	if title == "" {
-		title = "Untitled"
+// itemHeight returns the number of lines an article takes in the list,
+// including the blank line separating it from the next.
+func (d density) itemHeight() int {
+	if d == densityCompact {
+		return 1
 	}
+	return 3
+}
 
-	// Build description line: Author · domain · relative time · size
-	var descParts []string
-	if meta.Author != "" {
-		descParts = append(descParts, meta.Author)
-	}
-	if meta.SourceDomain != "" {
-		descParts = append(descParts, meta.SourceDomain)
-	}
-	descParts = append(descParts, formatRelativeTime(meta.SavedAt))
-	if meta.FileSize > 0 {
-		descParts = append(descParts, formatFileSize(meta.FileSize))
-	}
-	if meta.NoteCount > 0 {
-		if meta.NoteCount == 1 {
-			descParts = append(descParts, "1 note")
-		} else {
-			descParts = append(descParts, fmt.Sprintf("%d notes", meta.NoteCount))
-		}
-	}
-	if meta.Progress > 0 && meta.TotalLines > 0 {
-		pct := meta.Progress * 100 / meta.TotalLines
-		if pct > 100 {
-			pct = 100
-		}
-		if pct > 0 {
-			descParts = append(descParts, fmt.Sprintf("%d%%", pct))
-		}
-	}
-	desc := strings.Join(descParts, " · ")
+// listItemsThatFit returns how many articles fit in listHeight lines at
+// density d, always at least one.
+func listItemsThatFit(listHeight int, d density) int {
+	return max(1, listHeight/d.itemHeight())
+}
 
-	// Render tags as styled chips, right-aligned
-	var tagStr string
-	if len(meta.Tags) > 0 {
-		var tags []string
-		for _, t := range meta.Tags {
-			tags = append(tags, styles.Tag.Render("#"+t))
-		}
-		tagStr = strings.Join(tags, " ")
-	}
+// renderArticleItem renders a single article item for the list, with mark
+// (see domainMark), if any, before its title.
+func renderArticleItem(meta storage.ArticleMeta, mark string, selected bool, width int, tf timeFormat, msgs messages, ellipsis string, styles Styles) string {
+	var sb strings.Builder
+
+	titleWidth := width - 4 - lipgloss.Width(mark) // Account for selection marker and padding
+
+	title := truncateString(articleTitle(msgs, meta), titleWidth, ellipsis)
+
+	desc := articleDesc(msgs, meta, tf)
+	tagStr := articleTags(meta, styles)
 
 	lineWidth := width - 2 // usable width after 2-char indent
 	// Truncate description to fit available width (reserving space for tags).
@@ -143,10 +160,11 @@ func renderArticleItem(meta storage.ArticleMeta, selected bool, width int, style
 	if tagStr != "" {
 		descWidth = lineWidth - lipgloss.Width(tagStr) - 1 // 1 for padding
 	}
-	desc = truncateString(desc, descWidth)
+	desc = truncateString(desc, descWidth, ellipsis)
 
 	if selected {
 		sb.WriteString(styles.SelectionMarker.Render(""))
+		sb.WriteString(mark)
 		sb.WriteString(styles.SelectedTitle.Render(title))
 		sb.WriteString("\n")
 		sb.WriteString("  ")
@@ -164,6 +182,7 @@ func renderArticleItem(meta storage.ArticleMeta, selected bool, width int, style
 		}
 	} else {
 		sb.WriteString("  ")
+		sb.WriteString(mark)
 		sb.WriteString(styles.ListItemTitle.Render(title))
 		sb.WriteString("\n")
 		sb.WriteString("  ")
@@ -184,12 +203,185 @@ func renderArticleItem(meta storage.ArticleMeta, selected bool, width int, style
 	return sb.String()
 }
 
+// renderCompactArticleItem renders an article on a single line: the title,
+// then its metadata dimmed, with tags right-aligned. The title gets at least
+// three fifths of the room left by the tags when both don't fit. mark, if
+// any, goes before the title.
+func renderCompactArticleItem(meta storage.ArticleMeta, mark string, selected bool, width int, tf timeFormat, msgs messages, ellipsis string, styles Styles) string {
+	title := articleTitle(msgs, meta)
+	desc := articleDesc(msgs, meta, tf)
+	tagStr := articleTags(meta, styles)
+
+	prefix := "  "
+	titleStyle, descStyle := styles.ListItemTitle, styles.ListItemDesc
+	if selected {
+		prefix = styles.SelectionMarker.Render("")
+		titleStyle, descStyle = styles.SelectedTitle, styles.SelectedDesc
+	}
+	prefix += mark
+	lineWidth := width - lipgloss.Width(prefix)
+	avail := lineWidth
+	if tagStr != "" {
+		avail = max(0, lineWidth-lipgloss.Width(tagStr)-1)
+	}
+	titleWidth := min(lipgloss.Width(title), max(avail-lipgloss.Width(desc)-2, avail*3/5))
+	title = truncateString(title, titleWidth, ellipsis)
+	desc = truncateString(desc, avail-lipgloss.Width(title)-2, ellipsis)
+	if lipgloss.Width(desc) <= runewidth.StringWidth(ellipsis) {
+		desc = "" // just an ellipsis
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteString(titleStyle.Render(title))
+	used := lipgloss.Width(title)
+	if desc != "" {
+		sb.WriteString("  ")
+		sb.WriteString(descStyle.Render(desc))
+		used += 2 + lipgloss.Width(desc)
+	}
+	if tagStr != "" {
+		sb.WriteString(strings.Repeat(" ", max(1, lineWidth-used-lipgloss.Width(tagStr))))
+		sb.WriteString(tagStr)
+	}
+	return sb.String()
+}
+
+// pinGlyph marks pinned articles in the list.
+const pinGlyph = "⚑ "
+
+// lockGlyph marks locked articles, which are never re-fetched.
+const lockGlyph = "🔒 "
+
+// warningGlyph marks articles the extractor had doubts about, e.g. images
+// it couldn't download; W clears them.
+const warningGlyph = "⚠ "
+
+// newGlyph marks articles saved since the previous visit.
+const newGlyph = "• "
+
+// domainGlyph marks articles with domain_marks on, colored by domainMark.
+const domainGlyph = "● "
+
+// domainColors are the colors domainMark picks between: the Solarized
+// accents.
+var domainColors = []lipgloss.Color{
+	"#b58900", // yellow
+	"#cb4b16", // orange
+	"#dc322f", // red
+	"#d33682", // magenta
+	"#6c71c4", // violet
+	"#268bd2", // blue
+	"#2aa198", // cyan
+	"#859900", // green
+}
+
+// domainMark renders domainGlyph in a color derived from domain, so every
+// article from the same domain gets the same one, or as many blank columns
+// for an article with no domain, keeping titles aligned.
+func domainMark(domain string) string {
+	if domain == "" {
+		return strings.Repeat(" ", runewidth.StringWidth(domainGlyph))
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(domain)))
+	color := domainColors[h.Sum32()%uint32(len(domainColors))]
+	return lipgloss.NewStyle().Foreground(color).Render(domainGlyph)
+}
+
+// isNew reports whether an article was saved since the previous visit
+// (but before this one started) and hasn't been opened yet.
+func (m Model) isNew(a storage.ArticleMeta) bool {
+	return !m.newSince.IsZero() && a.SavedAt.After(m.newSince) && a.SavedAt.Before(m.visitStart) && !m.seenNew[a.FilePath]
+}
+
+// newCount returns how many listed articles are new; see isNew.
+func (m Model) newCount() int {
+	n := 0
+	for _, a := range m.articles {
+		if m.isNew(a) {
+			n++
+		}
+	}
+	return n
+}
+
+// articleTitle returns the title shown for an article in the list.
+func articleTitle(msgs messages, meta storage.ArticleMeta) string {
+	title := meta.Title
+	if title == "" {
+		title = msgs.text("view.untitled")
+	}
+	if len(meta.Warnings) > 0 {
+		title = warningGlyph + title
+	}
+	if meta.Locked {
+		title = lockGlyph + title
+	}
+	if meta.IsPinned() {
+		title = pinGlyph + title
+	}
+	return title
+}
+
+// articleDesc returns an article's metadata line: author · domain ·
+// saved time · size · notes · "flat file" for one shelf migrate would
+// convert · progress.
+func articleDesc(msgs messages, meta storage.ArticleMeta, tf timeFormat) string {
+	var descParts []string
+	if meta.Author != "" {
+		descParts = append(descParts, meta.Author)
+	}
+	if meta.SourceDomain != "" {
+		descParts = append(descParts, meta.SourceDomain)
+	}
+	if meta.SourceSection != "" {
+		descParts = append(descParts, meta.SourceSection)
+	}
+	descParts = append(descParts, formatRelativeTime(msgs, meta.SavedAt, time.Now(), tf))
+	if meta.FileSize > 0 {
+		descParts = append(descParts, storage.FormatSize(meta.FileSize))
+	}
+	if meta.NoteCount > 0 {
+		if meta.NoteCount == 1 {
+			descParts = append(descParts, msgs.text("list.note"))
+		} else {
+			descParts = append(descParts, msgs.format("list.notes", meta.NoteCount))
+		}
+	}
+	if meta.HasNotes {
+		descParts = append(descParts, msgs.text("list.notes_file"))
+	}
+	if meta.IsFlat() {
+		descParts = append(descParts, msgs.text("list.flat_file"))
+	}
+	if meta.Progress > 0 && meta.TotalLines > 0 {
+		pct := meta.Progress * 100 / meta.TotalLines
+		if pct > 100 {
+			pct = 100
+		}
+		if pct > 0 {
+			descParts = append(descParts, fmt.Sprintf("%d%%", pct))
+		}
+	}
+	return strings.Join(descParts, " · ")
+}
+
+// articleTags renders an article's tags as styled chips.
+func articleTags(meta storage.ArticleMeta, styles Styles) string {
+	var tags []string
+	for _, t := range meta.Tags {
+		tags = append(tags, styles.Tag.Render("#"+t))
+	}
+	return strings.Join(tags, " ")
+}
+
 // renderEmptyState renders the empty state message.
-func renderEmptyState(styles Styles) string {
-	return styles.Muted.Render("No articles saved yet. Press 'a' to add a URL.")
+func renderEmptyState(msgs messages, styles Styles) string {
+	return styles.Muted.Render(msgs.text("list.empty"))
 }
 
 // renderNoResults renders the no search results message.
-func renderNoResults(query string, styles Styles) string {
-	return styles.Muted.Render(fmt.Sprintf("No articles matching '%s'", query))
+func renderNoResults(query string, msgs messages, styles Styles) string {
+	return styles.Muted.Render(msgs.format("list.no_results", query))
 }
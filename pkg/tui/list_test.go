@@ -0,0 +1,856 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/irfansharif/shelf/pkg/extractor"
+	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/state"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// testState returns empty state backed by a file in a temporary directory,
+// for a Model to save to.
+func testState(t *testing.T) *state.State {
+	t.Helper()
+	s, err := state.Load(filepath.Join(t.TempDir(), state.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestListVisibleItems(t *testing.T) {
+	for _, tc := range []struct {
+		height  int
+		density density
+		want    int
+	}{
+		{42, densityComfortable, 10},
+		{42, densityCompact, 30},
+		{43, densityComfortable, 10},
+		// The zero value is comfortable.
+		{42, "", 10},
+		// Always room for one.
+		{5, densityComfortable, 1},
+		{5, densityCompact, 1},
+	} {
+		m := Model{height: tc.height, density: tc.density}
+		if got := m.listVisibleItems(); got != tc.want {
+			t.Errorf("listVisibleItems(height %d, %q) = %d, want %d", tc.height, tc.density, got, tc.want)
+		}
+	}
+
+	// Other list screens keep two-line items whatever the density.
+	if got := (Model{height: 42, density: densityCompact}).calcVisibleItems(); got != 10 {
+		t.Errorf("calcVisibleItems() = %d with compact density, want 10", got)
+	}
+}
+
+func TestListScroll(t *testing.T) {
+	for _, d := range []density{densityComfortable, densityCompact} {
+		t.Run(string(d), func(t *testing.T) {
+			m := Model{height: 42, density: d}
+			visible := m.listVisibleItems()
+			const total = 100
+
+			// Moving down scrolls once the cursor passes the last visible
+			// item, keeping it on the bottom row.
+			scroll := 0
+			for cursor := 0; cursor < total; cursor++ {
+				scroll = clampScroll(cursor, scroll, visible, total)
+				if want := max(0, cursor-visible+1); scroll != want {
+					t.Fatalf("cursor %d: scroll = %d, want %d", cursor, scroll, want)
+				}
+			}
+			// Moving back up keeps the cursor on the top row.
+			for cursor := total - 1; cursor >= 0; cursor-- {
+				scroll = clampScroll(cursor, scroll, visible, total)
+				if want := min(cursor, total-visible); scroll != want {
+					t.Fatalf("cursor %d going up: scroll = %d, want %d", cursor, scroll, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCompactArticleItem(t *testing.T) {
+	styles := DefaultStyles()
+	meta := storage.ArticleMeta{
+		Title:        "A fairly long article title about scheduler latencies and control theory",
+		Author:       "Jane Doe",
+		SourceDomain: "example.com",
+		SavedAt:      time.Now().Add(-3 * time.Hour),
+		Tags:         []string{"go", "systems"},
+	}
+	for _, width := range []int{40, 80, 160} {
+		for _, selected := range []bool{false, true} {
+			t.Run(fmt.Sprintf("width=%d,selected=%t", width, selected), func(t *testing.T) {
+				got := renderCompactArticleItem(meta, "", selected, width, timeFormat{}, nil, "...", styles)
+				if strings.Contains(got, "\n") {
+					t.Fatalf("compact item spans lines:\n%s", got)
+				}
+				if w := lipgloss.Width(got); w > width {
+					t.Errorf("width %d exceeds %d: %q", w, width, got)
+				}
+				for _, want := range []string{"#go", "#systems", meta.Title[:10]} {
+					if !strings.Contains(got, want) {
+						t.Errorf("missing %q: %q", want, got)
+					}
+				}
+				if width >= 160 && !strings.Contains(got, "example.com") {
+					t.Errorf("missing metadata at width %d: %q", width, got)
+				}
+			})
+		}
+	}
+}
+
+// TestRenderCompactArticleItemEllipsis checks that the metadata is dropped
+// when it's cut down to just the ellipsis, however wide that is, but not
+// before.
+func TestRenderCompactArticleItemEllipsis(t *testing.T) {
+	meta := storage.ArticleMeta{
+		Title:        "Go",
+		SourceDomain: "example.com",
+		SavedAt:      time.Now().Add(-3 * time.Hour),
+	}
+	var shortest int
+	for width := 4; width <= 40; width++ {
+		got := renderCompactArticleItem(meta, "", false, width, timeFormat{}, nil, "…", DefaultStyles())
+		if strings.HasSuffix(got, "  …") {
+			t.Errorf("width %d: metadata is just an ellipsis: %q", width, got)
+		}
+		if _, desc, ok := strings.Cut(strings.TrimPrefix(got, "  "), "  "); ok && strings.HasSuffix(desc, "…") && (shortest == 0 || lipgloss.Width(desc) < shortest) {
+			shortest = lipgloss.Width(desc)
+		}
+	}
+	if shortest != 2 {
+		t.Errorf("shortest cut metadata is %d columns, want 2", shortest)
+	}
+}
+
+func TestDomainMarks(t *testing.T) {
+	if domainMark("Example.com") != domainMark("example.com") {
+		t.Errorf("mark differs by the domain's case")
+	}
+	// Articles without a domain get blank space, keeping titles aligned.
+	if w, blank := lipgloss.Width(domainMark("example.com")), domainMark(""); w != 2 || blank != "  " {
+		t.Errorf("mark is %d columns, no domain %q; want 2 columns each", w, blank)
+	}
+
+	styles := DefaultStyles()
+	meta := storage.ArticleMeta{
+		Title:        "A fairly long article title about scheduler latencies and control theory",
+		SourceDomain: "example.com",
+		SavedAt:      time.Now(),
+		Tags:         []string{"go"},
+	}
+	mark := domainMark(meta.SourceDomain)
+	for _, width := range []int{40, 80} {
+		for _, selected := range []bool{false, true} {
+			for _, got := range []string{
+				renderArticleItem(meta, mark, selected, width, timeFormat{}, nil, "...", styles),
+				renderCompactArticleItem(meta, mark, selected, width, timeFormat{}, nil, "...", styles),
+			} {
+				if !strings.Contains(got, domainGlyph) {
+					t.Errorf("width %d: no mark in %q", width, got)
+				}
+				if w := lipgloss.Width(got); w > width {
+					t.Errorf("width %d exceeds %d with a mark: %q", w, width, got)
+				}
+			}
+		}
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	for _, tc := range []struct {
+		s, ellipsis string
+		width       int
+		want        string
+	}{
+		{"scheduler latencies", "...", 12, "scheduler..."},
+		{"scheduler latencies", "…", 12, "scheduler l…"},
+		{"scheduler latencies", "…", 40, "scheduler latencies"},
+		{"日本語のタイトル", "…", 7, "日本語…"},
+		{"scheduler", "...", 3, "scheduler"}, // too narrow to truncate
+	} {
+		got := truncateString(tc.s, tc.width, tc.ellipsis)
+		if got != tc.want {
+			t.Errorf("truncateString(%q, %d, %q) = %q, want %q", tc.s, tc.width, tc.ellipsis, got, tc.want)
+		}
+		if w := runewidth.StringWidth(got); tc.want != tc.s && w > tc.width {
+			t.Errorf("truncateString(%q, %d, %q) is %d columns wide", tc.s, tc.width, tc.ellipsis, w)
+		}
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.Local)
+	day := 24 * time.Hour
+	for _, tc := range []struct {
+		ago             time.Duration
+		relative, short string
+	}{
+		{0, "just now", "now"},
+		{59 * time.Second, "just now", "now"},
+		{time.Minute, "1 min ago", "1m"},
+		{59 * time.Minute, "59 mins ago", "59m"},
+		{time.Hour, "1 hour ago", "1h"},
+		{23 * time.Hour, "23 hours ago", "23h"},
+		{day, "1 day ago", "1d"},
+		{6 * day, "6 days ago", "6d"},
+		{7 * day, "1 week ago", "1w"},
+		{29 * day, "4 weeks ago", "4w"},
+		{30 * day, "1 month ago", "1mo"},
+		{364 * day, "12 months ago", "12mo"},
+		{365 * day, "1 year ago", "1y"},
+		{3 * 365 * day, "3 years ago", "3y"},
+	} {
+		saved := now.Add(-tc.ago)
+		if got := formatRelativeTime(nil, saved, now, timeFormat{style: timeRelative}); got != tc.relative {
+			t.Errorf("relative, %s ago = %q, want %q", tc.ago, got, tc.relative)
+		}
+		if got := formatRelativeTime(nil, saved, now, timeFormat{style: timeShort}); got != tc.short {
+			t.Errorf("short, %s ago = %q, want %q", tc.ago, got, tc.short)
+		}
+		// The zero format is relative.
+		if got := formatRelativeTime(nil, saved, now, timeFormat{}); got != tc.relative {
+			t.Errorf("default, %s ago = %q, want %q", tc.ago, got, tc.relative)
+		}
+	}
+
+	// Absolute times ignore the buckets.
+	saved := time.Date(2023, 3, 4, 9, 5, 0, 0, time.Local)
+	for _, tc := range []struct {
+		layout, want string
+	}{
+		{"", "2023-03-04"},
+		{"2006-01-02", "2023-03-04"},
+		{"02.01.2006", "04.03.2023"},
+		{"Jan 2, 2006 15:04", "Mar 4, 2023 09:05"},
+	} {
+		if got := formatRelativeTime(nil, saved, now, timeFormat{style: timeAbsolute, layout: tc.layout}); got != tc.want {
+			t.Errorf("absolute with layout %q = %q, want %q", tc.layout, got, tc.want)
+		}
+	}
+}
+
+func TestSaveFrontTab(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Old Post\nsource: https://example.com/old\ntags: archived\n---\n\nBody.\n"
+	if err := store.SaveContent("Old Post", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		urlInput:    NewURLInput(DefaultStyles()),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = next.(Model)
+	if m.state != stateLoading {
+		t.Fatalf("state after c = %v, want loading", m.state)
+	}
+	next, _ = m.Update(frontTabMsg{err: safari.ErrNoWindow, gen: m.fetchGen})
+	m = next.(Model)
+	if m.state != stateList || !errors.Is(m.err, safari.ErrNoWindow) {
+		t.Errorf("no window: state %v, err %v", m.state, m.err)
+	}
+
+	// A tab already saved and archived is unarchived, as when its URL is
+	// typed in.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = next.(Model)
+	next, _ = m.Update(frontTabMsg{url: "https://example.com/old", gen: m.fetchGen})
+	m = next.(Model)
+	if m.state != stateList || m.err != nil || m.statusMsg != `Unarchived "Old Post"` {
+		t.Errorf("saved tab: state %v, err %v, status %q", m.state, m.err, m.statusMsg)
+	}
+	if store.List()[0].IsArchived() {
+		t.Errorf("article still archived")
+	}
+}
+
+func TestNewSinceLastVisit(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastVisit := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for title, saved := range map[string]time.Time{
+		"Old Post":   lastVisit.Add(-time.Hour),
+		"Fresh Post": lastVisit.Add(time.Hour),
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\nsaved: %s\n---\n\nBody.\n", title, saved.Format(time.RFC3339))
+		if err := store.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appState, err := state.Load(filepath.Join(dir, state.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    appState,
+		newSince:    lastVisit,
+		visitStart:  lastVisit.Add(24 * time.Hour),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	view := m.View()
+	for _, want := range []string{newGlyph + "Fresh Post", "1 new"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, newGlyph+"Old Post") {
+		t.Errorf("article saved before the last visit marked new:\n%s", view)
+	}
+
+	// Opening the article clears its marker.
+	if m.articles[0].Title != "Fresh Post" {
+		t.Fatalf("first article = %q", m.articles[0].Title)
+	}
+	next, _ := m.openSelected(openPreview)
+	m = next.(Model)
+	if m.isNew(m.articles[0]) || m.newCount() != 0 {
+		t.Errorf("opened article still new")
+	}
+}
+
+func TestLockedArticle(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Curated\nsource: https://example.com/curated\n---\n\nHand-edited.\n"
+	if err := store.SaveContent("Curated", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		urlInput:    NewURLInput(DefaultStyles()),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(keys string) {
+		t.Helper()
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)})
+		m = next.(Model)
+	}
+
+	press("L")
+	if !store.List()[0].Locked || m.statusMsg != `Locked "Curated"; it won't be re-fetched` {
+		t.Fatalf("after L: locked %v, status %q", store.List()[0].Locked, m.statusMsg)
+	}
+	if view := m.View(); !strings.Contains(view, lockGlyph+"Curated") {
+		t.Errorf("list doesn't mark the article locked:\n%s", view)
+	}
+
+	// Re-fetching, directly or via Safari, is refused up front.
+	for _, key := range []string{"r", "R"} {
+		press(key)
+		if m.state != stateList || !errors.As(m.err, new(*lockedError)) {
+			t.Errorf("%s: state %v, err %v", key, m.state, m.err)
+		}
+	}
+	if got := m.msgs.errorText(m.err); got != "Error: article is locked — unlock with L first" {
+		t.Errorf("error text = %q", got)
+	}
+
+	// So is overwriting it with a fetch under the same title.
+	m.fetchGen++
+	next, _ := m.Update(articleExtractedMsg{
+		result: &extractor.ExtractResult{Title: "Curated", Content: "---\ntitle: Curated\n---\n\nFetched.\n"},
+		gen:    m.fetchGen,
+	})
+	m = next.(Model)
+	if m.state != stateList || !errors.As(m.err, new(*lockedError)) {
+		t.Errorf("fetched over it: state %v, err %v", m.state, m.err)
+	}
+	if a, err := store.Get(store.List()[0].FilePath); err != nil || a.Content != "Hand-edited.\n" {
+		t.Errorf("locked article overwritten: %v, %v", a, err)
+	}
+
+	press("L")
+	if store.List()[0].Locked {
+		t.Errorf("still locked after a second L")
+	}
+}
+
+func TestRefetchKeepsTags(t *testing.T) {
+	t.Setenv("TMUX", "")
+	dir := t.TempDir()
+	store, err := storage.New(dir, storage.WithDefaultTags("inbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Essay\nsource: https://example.com/essay\ntags:\n---\n\nBody.\n"
+	if err := store.SaveContent("Essay", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := store.ArticlePath("Essay")
+	if err := store.UpdateTags(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(dir, "articles", "essay", storage.NotesFile)
+	if err := os.WriteFile(notes, []byte("Mine.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		urlInput:    NewURLInput(DefaultStyles()),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+
+	// r re-fetches the article in place: the reader cleared its tags, so
+	// the default ones aren't put back, and its notes stay.
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(Model)
+	if m.state != stateLoading {
+		t.Fatalf("after r: state %v", m.state)
+	}
+	next, _ = m.Update(articleExtractedMsg{
+		result: &extractor.ExtractResult{Title: "Essay", Content: "---\ntitle: Essay\nsource: https://example.com/essay\ntags:\n---\n\nFetched.\n"},
+		gen:    m.fetchGen,
+	})
+	m = next.(Model)
+	a, err := store.Get(path)
+	if err != nil || m.err != nil {
+		t.Fatalf("after re-fetch: %v, %v", err, m.err)
+	}
+	if a.Content != "Fetched.\n" || len(a.Meta.Tags) != 0 {
+		t.Errorf("after re-fetch: content %q, tags %v; want the fetched body and no tags", a.Content, a.Meta.Tags)
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Errorf("notes lost on re-fetch: %v", err)
+	}
+}
+
+func TestClearWarnings(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Patchy\nsource: https://example.com/patchy\nwarnings: 2 images couldn't be downloaded\n---\n\nBody.\n"
+	if err := store.SaveContent("Patchy", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	if view := m.View(); !strings.Contains(view, warningGlyph+"Patchy") {
+		t.Errorf("list doesn't mark the article's warnings:\n%s", view)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	m = next.(Model)
+	if len(store.List()[0].Warnings) > 0 || m.statusMsg != `Cleared warnings for "Patchy": 2 images couldn't be downloaded` {
+		t.Fatalf("after W: warnings %q, status %q", store.List()[0].Warnings, m.statusMsg)
+	}
+	if view := m.View(); strings.Contains(view, warningGlyph) {
+		t.Errorf("warning glyph left after clearing:\n%s", view)
+	}
+}
+
+func TestPeek(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Essay\nauthor: Ann Author\nsource: https://example.com/essay\nfetch_ms: 2430\n---\n\n" +
+		"# Essay\n\n![cover](images/cover.png)\n\nThe opening\nparagraph.\n\nThe second paragraph.\n"
+	if err := store.SaveContent("Essay", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(k tea.KeyMsg) {
+		t.Helper()
+		next, _ := m.Update(k)
+		m = next.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if m.state != statePeek {
+		t.Fatalf("space: state %v, want statePeek", m.state)
+	}
+	view := m.View()
+	for _, want := range []string{"Ann Author · fetched in 2.4s", "The opening paragraph."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("peek missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "second paragraph") {
+		t.Errorf("peek shows more than the first paragraph:\n%s", view)
+	}
+
+	// Any key closes it without acting on the list.
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.state != stateList || store.List()[0].IsArchived() {
+		t.Errorf("after x: state %v, archived %v", m.state, store.List()[0].IsArchived())
+	}
+	if _, ok := m.peekCache[store.List()[0].FilePath]; !ok {
+		t.Errorf("peek not cached")
+	}
+}
+
+func TestExportView(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, tags string }{{"One", "rust"}, {"Two", "go"}, {"Three", "rust"}} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+		exportRoot:  filepath.Join(dir, "exports"),
+	}
+	m.searchInput = m.searchInput.SetValue("tag:rust")
+	m.refreshArticles()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	m = next.(Model)
+	if m.state != stateExporting || !strings.Contains(m.View(), "Exporting 1 of 2") {
+		t.Fatalf("after >: state %v\n%s", m.state, m.View())
+	}
+	for m.state == stateExporting {
+		next, _ = m.Update(m.exportNext()())
+		m = next.(Model)
+	}
+	if m.err != nil || m.statusMsg != "Exported 2 articles to "+m.exportDir {
+		t.Fatalf("status %q, err %v", m.statusMsg, m.err)
+	}
+	entries, err := os.ReadDir(m.exportDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, ","); got != "one,three" {
+		t.Errorf("exported %s, want one,three", got)
+	}
+}
+
+func TestOverwriteChoice(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, body := range map[string]string{"First": "Body.", "Second": "Body [[note]] with ==a highlight==."} {
+		content := fmt.Sprintf("---\ntitle: %s\nsource: https://example.com/%s\n---\n\n%s\n", title, strings.ToLower(title), body)
+		if err := store.SaveContent(title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newModel := func(overwrite string) Model {
+		m := Model{
+			store:       store,
+			keys:        DefaultKeyMap(),
+			styles:      DefaultStyles(),
+			urlInput:    NewURLInput(DefaultStyles()),
+			searchInput: NewSearchInput(DefaultStyles()),
+			appState:    testState(t),
+			overwrite:   overwrite,
+			width:       80,
+			height:      30,
+		}
+		m.refreshArticles()
+		return m
+	}
+	submit := func(m Model, url string) Model {
+		t.Helper()
+		next, _ := m.submitURL(url)
+		return next.(Model)
+	}
+
+	// Skipping always at the prompt answers the next one too.
+	m := submit(newModel("ask"), "https://example.com/first")
+	if m.state != stateConfirmOverwrite {
+		t.Fatalf("state = %v, want the overwrite prompt", m.state)
+	}
+	if view := m.View(); !strings.Contains(view, "[a] always") || !strings.Contains(view, "[s] never") {
+		t.Errorf("prompt doesn't offer to remember the choice:\n%s", view)
+	}
+	saved := "Saved " + time.Now().Format(time.DateOnly) + " (just now) · "
+	if view := m.View(); !strings.Contains(view, saved) || strings.Contains(view, "Overwriting loses") {
+		t.Errorf("prompt doesn't say when the article was saved, or warns about nothing:\n%s", view)
+	}
+	if m := submit(newModel("ask"), "https://example.com/second"); !strings.Contains(m.View(), "⚠ Overwriting loses your notes (1), highlights (1)") {
+		t.Errorf("prompt doesn't warn about the reader's notes:\n%s", m.View())
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = next.(Model)
+	if m.state != stateList || m.overwritePath != "" {
+		t.Fatalf("after s: state %v, overwriting %q", m.state, m.overwritePath)
+	}
+	if !strings.Contains(m.View(), "skipping saved articles") {
+		t.Errorf("header doesn't say saved articles are skipped:\n%s", m.View())
+	}
+	m = submit(m, "https://example.com/second")
+	if m.state != stateList || m.statusMsg != `"Second" is already saved; kept it` {
+		t.Errorf("remembered skip: state %v, status %q", m.state, m.statusMsg)
+	}
+
+	// Moving about keeps the choice for the batch; anything else ends it.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m = next.(Model); m.overwriteMemo != "never" {
+		t.Errorf("after j: remembered %q", m.overwriteMemo)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(Model); m.overwriteMemo != "" {
+		t.Errorf("after esc: remembered %q", m.overwriteMemo)
+	}
+	if m := submit(m, "https://example.com/second"); m.state != stateConfirmOverwrite {
+		t.Errorf("after the batch: state %v, want the prompt again", m.state)
+	}
+
+	// Overwriting always at the prompt re-fetches without asking again.
+	m = submit(newModel("ask"), "https://example.com/first")
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = next.(Model)
+	if m.state != stateLoading || m.overwritePath == "" {
+		t.Fatalf("after a: state %v, overwriting %q", m.state, m.overwritePath)
+	}
+	m.state = stateList
+	m = submit(m, "https://example.com/second")
+	if m.state != stateLoading || m.overwriteTitle != "Second" {
+		t.Errorf("remembered overwrite: state %v, overwriting %q", m.state, m.overwriteTitle)
+	}
+
+	// The config default applies from the start.
+	if m := submit(newModel("never"), "https://example.com/first"); m.state != stateList || m.statusMsg == "" {
+		t.Errorf("overwrite = never: state %v, status %q", m.state, m.statusMsg)
+	}
+	if m := submit(newModel("always"), "https://example.com/first"); m.state != stateLoading {
+		t.Errorf("overwrite = always: state %v", m.state)
+	}
+}
+
+func TestFind(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Alpha", "Rust notes", "Reading list", "Zebra"} {
+		if err := store.SaveContent(title, "---\ntitle: "+title+"\n---\n\nBody.\n", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	typ := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			if k == "esc" {
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			}
+			next, _ := m.Update(msg)
+			m = next.(Model)
+		}
+	}
+	selected := func() string { return m.articles[m.cursor].Title }
+
+	typ("f", "z")
+	if selected() != "Zebra" || m.statusMsg != "Find: z" {
+		t.Fatalf("fz: selected %q, status %q", selected(), m.statusMsg)
+	}
+
+	// After the timeout a letter starts a new prefix; typing it again
+	// cycles through the titles starting with it.
+	m.findAt = time.Now().Add(-2 * findTimeout)
+	typ("r")
+	first := selected()
+	typ("r")
+	second := selected()
+	if !strings.HasPrefix(first, "R") || !strings.HasPrefix(second, "R") || first == second {
+		t.Fatalf("r, r: selected %q then %q", first, second)
+	}
+
+	// Letters typed in time extend the prefix.
+	m.findAt = time.Now().Add(-2 * findTimeout)
+	typ("r", "e")
+	if selected() != "Reading list" || m.findPrefix != "re" {
+		t.Fatalf("re: selected %q, prefix %q", selected(), m.findPrefix)
+	}
+
+	// Without a title starting with the prefix, one containing it is used.
+	m.findAt = time.Now().Add(-2 * findTimeout)
+	typ("b")
+	if selected() != "Zebra" {
+		t.Fatalf("b: selected %q", selected())
+	}
+	m.findAt = time.Now().Add(-2 * findTimeout)
+	typ("q")
+	if selected() != "Zebra" || m.statusMsg != "Find: q (no match)" {
+		t.Fatalf("q: selected %q, status %q", selected(), m.statusMsg)
+	}
+
+	// Esc stops finding, and letters act as keys again.
+	typ("esc", "x")
+	for _, a := range store.List() {
+		if a.Title == "Zebra" && (m.finding || !a.IsArchived()) {
+			t.Fatalf("esc, x: finding %v, Zebra archived %v", m.finding, a.IsArchived())
+		}
+	}
+}
+
+func TestMatchCounts(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, source, tags string }{
+		{"Rust Ownership", "https://blog.rust-lang.org/ownership", ""},
+		{"Lifetimes", "https://blog.rust-lang.org/lifetimes", ""},
+		{"Traits", "https://example.com/traits", "rust"},
+		{"Goroutines", "https://go.dev/goroutines", "go"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\nsource: %s\ntags: %s\n---\n\nBody.\n", a.title, a.source, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		matchCounts: true,
+		width:       100,
+		height:      30,
+	}
+	view := func(query string) string {
+		m.searchInput = m.searchInput.SetValue(query)
+		m.refreshArticles()
+		return m.View()
+	}
+
+	// Each result counts towards the first field it matched in.
+	want := "(1 of 3 of 4) · 1 in title, 1 in domain, 1 in tags"
+	if got := view("rust"); !strings.Contains(got, want) {
+		t.Errorf("header missing %q:\n%s", want, got)
+	}
+	// Filters alone match no field.
+	if got := view("tag:rust"); strings.Contains(got, " in title") || strings.Contains(got, " in tags") {
+		t.Errorf("match counts for a filter:\n%s", got)
+	}
+	m.matchCounts = false
+	if got := view("rust"); strings.Contains(got, " in title") {
+		t.Errorf("match counts with match_counts off:\n%s", got)
+	}
+	// Prompts hide the counts.
+	m.state, m.resetting = stateConfirmResetTag, "rust"
+	if got := view("rust"); strings.Contains(got, "(1 of 3 of 4)") {
+		t.Errorf("counts shown in a prompt:\n%s", got)
+	}
+}
+
+func TestProbeKey(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       100,
+		height:      30,
+	}
+	probe := func() (status string, inHelp bool) {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		for _, entry := range next.(Model).helpColumns()[2] {
+			inHelp = inHelp || entry.desc == "debug fetch"
+		}
+		return next.(Model).statusMsg, inHelp
+	}
+
+	// Without SHELF_DEBUG, D does nothing and isn't in the help.
+	if status, inHelp := probe(); status != "" || inHelp {
+		t.Errorf("probe disabled: status %q, in help %v", status, inHelp)
+	}
+	m.keys.Probe.SetEnabled(true)
+	if status, inHelp := probe(); status != "Nothing to debug: no URL" || !inHelp {
+		t.Errorf("probe enabled: status %q, in help %v", status, inHelp)
+	}
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// macroForKey returns the macro bound to the pressed key, if any.
+func (m Model) macroForKey(msg tea.KeyMsg) (config.Macro, bool) {
+	for _, mac := range m.macros {
+		if mac.Key == msg.String() {
+			return mac, true
+		}
+	}
+	return config.Macro{}, false
+}
+
+// runMacro runs a macro's actions in order on the selected article,
+// stopping at the first that fails. The cursor stays on the article as it
+// moves, e.g. when archived.
+func (m Model) runMacro(mac config.Macro) (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	desc := m.msgs.format("status.macro_ran", strings.Join(mac.Actions, ", "), article.Title)
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		for _, action := range mac.Actions {
+			if err := m.runAction(article.FilePath, action); err != nil {
+				return fmt.Errorf(m.msgs.text("status.macro_failed"), mac.Key, action, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		m.err = err
+	} else {
+		m.statusMsg = desc
+	}
+
+	m.refreshArticles()
+	for i, a := range m.articles {
+		if a.FilePath == article.FilePath {
+			m.cursor = i
+			break
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.articles)-1, 0))
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	return m, nil
+}
+
+// runAction applies a single macro action (see config.Macro) to the
+// article at filePath.
+func (m Model) runAction(filePath, action string) error {
+	if tag, ok := strings.CutPrefix(action, "tag:"); ok {
+		return addTags(m.store, filePath, []string{strings.TrimSpace(tag)})
+	}
+	if tag, ok := strings.CutPrefix(action, "untag:"); ok {
+		article, err := m.store.Get(filePath)
+		if err != nil {
+			return err
+		}
+		tag = strings.TrimSpace(tag)
+		tags := slices.DeleteFunc(slices.Clone(article.Meta.Tags), func(t string) bool {
+			return strings.EqualFold(t, tag)
+		})
+		return m.store.UpdateTags(filePath, tags)
+	}
+	switch action {
+	case "archive", "unarchive":
+		return m.store.SetArchived(filePath, action == "archive")
+	case "pin", "unpin":
+		return m.store.SetPinned(filePath, action == "pin")
+	case "lock", "unlock":
+		return m.store.SetLocked(filePath, action == "lock")
+	case "reset-progress":
+		return m.store.UpdateProgress(filePath, 0)
+	}
+	return errors.New(m.msgs.text("status.unknown_action"))
+}
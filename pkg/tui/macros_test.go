@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestMacro(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: Essay\nsource: https://example.com/essay\ntags: inbox\n---\n\nBody.\n"
+	if err := store.SaveContent("Essay", content, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+		macros: []config.Macro{
+			{Key: "A", Actions: []string{"tag:read-later", "untag:inbox", "archive"}},
+			{Key: "x", Actions: []string{"pin"}}, // shadowed by archive
+		},
+	}
+	m.refreshArticles()
+	press := func(keys string) {
+		t.Helper()
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)})
+		m = next.(Model)
+	}
+
+	press("A")
+	a := store.List()[0]
+	if want := []string{"read-later", "archived"}; !slices.Equal(a.Tags, want) {
+		t.Errorf("tags after macro = %q, want %q", a.Tags, want)
+	}
+	if m.err != nil || m.statusMsg != `Ran tag:read-later, untag:inbox, archive on "Essay"` {
+		t.Errorf("status %q, err %v", m.statusMsg, m.err)
+	}
+
+	// Built-in keys win over macros bound to them.
+	m.showArchived = true
+	m.refreshArticles()
+	press("x")
+	if a := store.List()[0]; a.IsArchived() || a.IsPinned() {
+		t.Errorf("x ran the macro: archived %v, pinned %v", a.IsArchived(), a.IsPinned())
+	}
+
+	m.state = stateHelp
+	if view := m.View(); !strings.Contains(view, "tag:read-later, untag:inbox, archive") {
+		t.Errorf("help doesn't list the macro:\n%s", view)
+	}
+}
+
+// TestMacroBuiltinKeys checks that config.BuiltinKeys, which macros can't
+// bind, are exactly the keys in the key map, so neither drifts from the
+// other.
+func TestMacroBuiltinKeys(t *testing.T) {
+	var want []string
+	keys := reflect.ValueOf(DefaultKeyMap())
+	for i := range keys.NumField() {
+		if binding, ok := keys.Field(i).Interface().(key.Binding); ok {
+			want = append(want, binding.Keys()...)
+		}
+	}
+	slices.Sort(want)
+	want = slices.Compact(want)
+	got := slices.Sorted(slices.Values(config.BuiltinKeys))
+	if !slices.Equal(got, want) {
+		t.Errorf("config.BuiltinKeys = %q, want the key map's keys %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// matchFields are the fields renderMatchCounts counts results by, in the
+// order storage.Query.MatchedField checks them, with the message for each.
+var matchFields = []struct {
+	field storage.Field
+	msg   string
+}{
+	{storage.FieldTitle, "header.match_title"},
+	{storage.FieldAuthor, "header.match_author"},
+	{storage.FieldDomain, "header.match_domain"},
+	{storage.FieldTags, "header.match_tags"},
+}
+
+// renderMatchCounts breaks the listed results of a search down by the
+// field its text matched first, e.g. "12 in title, 3 in tags", leaving out
+// fields nothing matched. It's "" when the query is only filters.
+func (m Model) renderMatchCounts() string {
+	q := storage.ParseQuery(m.searchInput.Value())
+	if q.Text == "" {
+		return ""
+	}
+	counts := make(map[storage.Field]int)
+	for _, a := range m.articles {
+		counts[q.MatchedField(a)]++
+	}
+	var parts []string
+	for _, f := range matchFields {
+		if n := counts[f.field]; n > 0 {
+			parts = append(parts, m.msgs.format(f.msg, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,397 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// messages is a catalog of user-facing strings keyed by identifier, e.g.
+// "view.fetching". Identifiers missing from a catalog fall back to
+// defaultMessages, so a nil catalog is plain English and a locale file only
+// needs the strings it translates.
+type messages map[string]string
+
+// defaultMessages is the English catalog. Values with verbs are
+// fmt.Sprintf formats, or fmt.Errorf formats for those wrapping an error
+// with %w; translations must keep the same verbs, in order.
+var defaultMessages = messages{
+	// Header
+	"header.articles":             "Articles",
+	"header.with_archived":        "(+archived)",
+	"header.endpoint_unreachable": "endpoint unreachable",
+	"header.count":                "(%d of %d)",
+	"header.count_filtered":       "(%d of %d of %d)",
+	"header.count_none":           "(0 of %d)",
+	"header.archived_count":       "%d archived",
+	"header.new_count":            "%d new",
+	"header.import_paused":        "import paused (%d remaining)",
+	"header.overwriting_all":      "overwriting saved articles",
+	"header.skipping_saved":       "skipping saved articles",
+	"header.streak":               "%d-day streak",
+	"header.total_size":           "%s on disk",
+	"header.match_title":          "%d in title",
+	"header.match_author":         "%d in author",
+	"header.match_domain":         "%d in domain",
+	"header.match_tags":           "%d in tags",
+
+	// Main content area
+	"view.fetching":          "Fetching article...",
+	"view.exporting":         "Exporting %d of %d...",
+	"view.peek_empty":        "(no text to show)",
+	"view.fetched_in":        "fetched in %s",
+	"view.scanning":          "Scanning %d articles...",
+	"view.archived_because":  "archived: %s",
+	"view.error":             "Error",
+	"view.new_note":          "Name the note; it opens in your editor to write, with no source URL",
+	"view.rename_tag":        "Renaming %q on %d article(s); a name already in use merges the two",
+	"view.archive_note":      "Archiving %q; Enter archives it, with the reason if you give one",
+	"view.confirm_overwrite": "Article %q already exists. Overwrite?",
+	"view.confirm_refetch":   "Already saved as %q. Re-fetch?",
+	"view.safari_waiting":    "Safari opened — complete any verification, then press Enter...",
+	"view.gathering_tabs":    "Gathering Safari tabs...",
+	"view.resolving_titles":  "Looking up titles of untitled tabs... %d/%d",
+	"view.import_pausing":    "Pausing (%d remaining), finishing %d in flight...",
+	"view.import_paused":     "Paused (%d remaining)",
+	"view.importing":         "Importing %d/%d...",
+	"view.import_saved":      "%d saved",
+	"view.import_skipped":    "%d skipped",
+	"view.import_failed":     "%d failed",
+	"view.import_failures":   "%d imports failed",
+	"view.loading":           "Loading...",
+	"view.untitled":          "Untitled",
+	"view.tags":              "tags: %s",
+	"view.all_tags":          "All tags",
+	"view.all_articles":      "All articles",
+	"view.no_filter":         "no filter",
+	"view.images":            "%d images in %q",
+	"view.image_position":    "%d of %d",
+	"view.no_alt_text":       "(no alt text)",
+	"view.debug_title":       "Debug · %s",
+
+	// Import preview
+	"view.preview_heading":  "Dry run: %s",
+	"view.preview_new":      "%d new",
+	"view.preview_skipped":  "%d already saved or repeated",
+	"view.preview_invalid":  "%d invalid (e to fix)",
+	"view.preview_estimate": "%s estimated",
+	"view.preview_repeated": "repeated; imported once",
+	"view.preview_saved_as": "already saved as %q",
+	"view.preview_is_new":   "new",
+
+	// Overwrite prompt details
+	"view.overwrite_saved":      "Saved %s (%s)",
+	"view.overwrite_new_size":   "%s fetched",
+	"view.overwrite_notes":      "notes (%d)",
+	"view.overwrite_highlights": "highlights (%d)",
+	"view.overwrite_loses":      "⚠ Overwriting loses your %s",
+
+	// Article list
+	"list.empty":              "No articles saved yet. Press 'a' to add a URL.",
+	"list.no_results":         "No articles matching '%s'",
+	"list.archived_separator": "archived",
+	"list.note":               "1 note",
+	"list.notes":              "%d notes",
+	"list.notes_file":         "✎ notes.md",
+	"list.flat_file":          "flat file",
+
+	// Saved times
+	"time.just_now":      "just now",
+	"time.minute_ago":    "1 min ago",
+	"time.minutes_ago":   "%d mins ago",
+	"time.hour_ago":      "1 hour ago",
+	"time.hours_ago":     "%d hours ago",
+	"time.day_ago":       "1 day ago",
+	"time.days_ago":      "%d days ago",
+	"time.week_ago":      "1 week ago",
+	"time.weeks_ago":     "%d weeks ago",
+	"time.month_ago":     "1 month ago",
+	"time.months_ago":    "%d months ago",
+	"time.year_ago":      "1 year ago",
+	"time.years_ago":     "%d years ago",
+	"time.short_now":     "now",
+	"time.short_minutes": "%dm",
+	"time.short_hours":   "%dh",
+	"time.short_days":    "%dd",
+	"time.short_weeks":   "%dw",
+	"time.short_months":  "%dmo",
+	"time.short_years":   "%dy",
+
+	// Status line
+	"status.confirm_delete":          "Delete %q? This cannot be undone.",
+	"status.confirm_delete_untitled": "Delete this article?",
+	"status.confirm_trash":           "Delete %q? u brings it back.",
+	"status.deleted":                 "Deleted %q",
+	"status.undid":                   "Undid: %s",
+	"status.redid":                   "Redid: %s",
+	"status.nothing_to_undo":         "Nothing to undo",
+	"status.nothing_to_redo":         "Nothing to redo",
+	"status.confirm_quit":            "Quit? [y/n]",
+	"status.confirm_delete_tag":      "Remove tag %q from %d article(s)? [y/n]",
+	"status.confirm_reset_tag":       "Reset reading progress of %d article(s) tagged %q? [y/n]",
+	"status.confirm_resume":          "Resume the paused import (%d remaining), or start a new one and drop it? [r/n]",
+	"status.error":                   "Error: %v",
+	"status.error_more":              "(%s: full error)",
+	"status.find":                    "Find: %s",
+	"status.find_none":               "Find: %s (no match)",
+	"status.fetch_failed":            "Couldn't fetch: %v — press r to retry",
+	"status.save_failed":             "Couldn't save to disk: %v",
+	"status.save_denied":             "Couldn't save to disk: %v — check the data directory's permissions",
+	"status.save_no_space":           "Couldn't save to disk: %v — free up some space and try again",
+	"status.archived":                "Archived %q",
+	"status.unarchived":              "Unarchived %q",
+	"status.pinned":                  "Pinned %q",
+	"status.unpinned":                "Unpinned %q",
+	"status.overwrite_skipped":       "%q is already saved; kept it",
+	"status.placeholder_saved":       "Saved placeholder — use [R] to refetch via Safari",
+	"status.locked":                  "Locked %q; it won't be re-fetched",
+	"status.unlocked":                "Unlocked %q",
+	"status.article_locked":          "article is locked — unlock with %s first",
+	"status.config_reloaded":         "Reloaded shelf.toml",
+	"status.no_data_dir":             "data directory %s doesn't exist",
+	"status.config_restart":          "Reloaded shelf.toml; restart shelf for changes to %s",
+	"status.config_unknown_keys":     "Unknown keys in shelf.toml (misspelled?): %s",
+	"status.exported":                "Exported %d articles to %s",
+	"status.export_stopped":          "Export stopped after %d of %d articles; they're in %s",
+	"status.macro_ran":               "Ran %s on %q",
+	"status.warnings_cleared":        "Cleared warnings for %q: %s",
+	"status.no_warnings":             "%q has no extraction warnings",
+	"status.sort_not_manual":         `Set sort = "manual" in the config to reorder articles`,
+	"status.progress_reset":          "Reset progress of %q",
+	"status.tag_progress_reset":      "Reset progress of %d article(s) tagged %q",
+	"status.tag_renamed":             "Renamed %q to %q on %d article(s)",
+	"status.tag_deleted":             "Removed tag %q from %d article(s)",
+	"status.status_tag":              "%q marks articles rather than what they're about; use shelf delete-tag -f",
+	"status.article_deleted":         "Article deleted",
+	"status.saved":                   "Saved %q",
+	"status.title_empty":             "title cannot be empty",
+	"status.copied_path":             "Copied path of %q",
+	"status.copied_body":             "Copied body of %q",
+	"status.copied_url":              "Copied URL of %q",
+	"status.no_safari_tabs":          "No Safari tabs found",
+	"status.no_window_pages":         "No web pages open in the front Safari window",
+	"status.window_all_saved":        "Every tab in the front Safari window is already saved",
+	"status.no_urls":                 "No URLs to import",
+	"status.nothing_new":             "Nothing new to import",
+	"status.import_cancelled":        "Import cancelled",
+	"status.import_left_paused":      "Import paused (%d remaining) · press %s to resume",
+	"status.import_complete":         "Import complete: %d saved",
+	"status.source_unavailable":      "%s unavailable (%s)",
+	"status.copied_failed_urls":      "Copied %d failed URLs",
+	"status.no_images":               "No images in %q",
+	"status.no_remote_images":        "No remote images to download",
+	"status.downloading_images":      "Downloading %d remote image(s)...",
+	"status.downloaded_images":       "Downloaded %d image(s)",
+	"status.images_failed":           "%d failed (see shelf.log)",
+	"status.no_tags":                 "No tagged articles",
+	"status.no_saved_searches":       "No saved searches; add [[saved_search]] entries to shelf.toml",
+	"status.session_no_match":        "Last search matched nothing; showing all articles",
+	"status.debug_no_url":            "Nothing to debug: no URL",
+	"status.debugging":               "Fetching %s for debugging...",
+	"status.import_aborted":          "%s (cancelled)",
+	"status.no_source_url":           "no source URL for %q",
+	"status.no_safari_tabs_reason":   "no Safari tabs found: %s",
+	"status.safari_open_failed":      "opening Safari: %w",
+	"status.safari_extract_failed":   "extracting HTML from Safari: %w",
+	"status.safari_empty_html":       "Safari returned empty HTML",
+	"status.temp_file_create_failed": "creating temp file: %w",
+	"status.temp_file_write_failed":  "writing temp file: %w",
+	"status.editor_failed":           "editor: %w",
+	"status.config_reload_failed":    "reloading config: %w",
+	"status.tmux_split_failed":       "tmux split-window: %w",
+	"status.macro_failed":            "macro %s: %s: %w",
+	"status.unknown_action":          "unknown action",
+
+	// Placeholder article saved when extraction fails
+	"article.placeholder_title": "Refetch needed — %s",
+	"article.placeholder_body":  "*Extraction failed — use R to re-fetch via Safari.*",
+
+	// Import buffer, written as comments
+	"import.buffer_help":        "Safari Import — uncomment URLs to import, then :wq\nUse zo/zc to unfold/fold groups, zR to open all.\nAppend #tags after a URL to tag it on import: https://… #rust #async",
+	"import.window_buffer_help": "Safari Import (front window) — comment out URLs to skip, then :wq\nAppend #tags after a URL to tag it on import: https://… #rust #async",
+	"import.buffer_warning":     "Warning: %s",
+
+	// Plain mode (--plain)
+	"plain.help": `Commands:
+  <n>        print article n
+  e <n>      open article n in $EDITOR
+  a <url>    save the article at url
+  x <n>      archive or unarchive article n
+  d <n>      delete article n
+  / <query>  search ("/" alone clears the search)
+  X          show or hide archived articles
+  l          list articles again
+  q          quit`,
+	"plain.no_article":      "no article %q; type l to list them",
+	"plain.unknown_command": "unknown command %q; type ? for help",
+	"plain.already_saved":   "%q is already saved",
+	"plain.new":             "new",
+	"plain.pinned":          "pinned",
+	"plain.locked":          "locked",
+	"plain.archived":        "archived",
+	"plain.warnings":        "warnings: %s",
+
+	// Footer hints
+	"footer.search":            "[/] search",
+	"footer.help":              "[?] help",
+	"footer.debug":             "[D]ebug",
+	"footer.reset_progress":    "[0] reset progress",
+	"footer.add":               "[a]dd URL",
+	"footer.ctrlc_cancel":      "[ctrl+c] cancel",
+	"footer.ctrlc_clear":       "[ctrl+c] clear",
+	"footer.delete":            "[d]elete",
+	"footer.edit":              "[e]dit",
+	"footer.apply":             "[enter] apply",
+	"footer.continue":          "[enter] continue",
+	"footer.enter_done":        "[enter] done",
+	"footer.extract":           "[enter] extract",
+	"footer.fetch":             "[enter] fetch",
+	"footer.create":            "[enter] create",
+	"footer.archive":           "[enter] archive",
+	"footer.rename":            "[enter] rename",
+	"footer.filter":            "[enter] filter",
+	"footer.import_confirm":    "[enter] import",
+	"footer.open":              "[enter] open",
+	"footer.back":              "[esc] back",
+	"footer.cancel":            "[esc] cancel",
+	"footer.esc_done":          "[esc] done",
+	"footer.resume_later":      "[esc] resume later",
+	"footer.top_bottom":        "[g/G] top/bottom",
+	"footer.import":            "[i]mport",
+	"footer.scroll":            "[j/k] scroll",
+	"footer.localize":          "[l]ocalize remote",
+	"footer.n_cancel":          "[n] cancel",
+	"footer.open_settings":     "[o]pen settings",
+	"footer.preview":           "[p] preview",
+	"footer.resume":            "[p] resume",
+	"footer.pause":             "[p]ause",
+	"footer.quit":              "[q]uit",
+	"footer.refetch":           "[r/R]efetch",
+	"footer.recheck":           "[r]echeck",
+	"footer.retry":             "[r]etry",
+	"footer.select":            "[space] select",
+	"footer.skip_titles":       "[esc] skip the rest",
+	"footer.hide_tags":         "[t] hide",
+	"footer.rename_tag":        "[r]ename",
+	"footer.delete_tag":        "[d]elete",
+	"footer.focus_list":        "[tab] list",
+	"footer.archive_hide":      "[x/X] archive/hide",
+	"footer.archive_show":      "[x/X] archive/show",
+	"footer.unarchive_hide":    "[x/X] unarchive/hide",
+	"footer.copy":              "[y] copy",
+	"footer.confirm_delete":    "[y] delete",
+	"footer.confirm_overwrite": "[y] overwrite",
+	"footer.overwrite_always":  "[a] always",
+	"footer.overwrite_never":   "[s] never",
+	"footer.confirm_quit":      "[y] quit",
+	"footer.confirm_untag":     "[y] remove tag",
+	"footer.confirm_reset":     "[y] reset",
+	"footer.resume_import":     "[r] resume",
+	"footer.new_import":        "[n] new import",
+	"footer.history":           "[↑/↓] history",
+	"footer.close_help":        "press any key to close",
+
+	// Help grid descriptions
+	"help.down":           "move down",
+	"help.up":             "move up",
+	"help.top":            "go to top",
+	"help.bottom":         "go to bottom",
+	"help.tags":           "show / hide tags",
+	"help.focus_tags":     "focus tags / list",
+	"help.rename_tag":     "rename the tag",
+	"help.delete_tag":     "remove the tag everywhere",
+	"help.open":           "open (%s)",
+	"help.open_with":      "editor/pager/browser/preview",
+	"help.open_data_dir":  "open the data directory",
+	"help.reveal":         "reveal in the file manager",
+	"help.add":            "add URL",
+	"help.save_tab":       "save current Safari tab",
+	"help.new_note":       "new note (no URL)",
+	"help.delete":         "delete article",
+	"help.search":         "search articles",
+	"help.find":           "jump to a title starting with letters",
+	"help.saved_searches": "saved searches",
+	"help.import":         "import from Safari",
+	"help.import_window":  "import front Safari window",
+	"help.pause_import":   "pause / resume the import",
+	"help.yank":           "copy path/body/URL",
+	"help.archive":        "archive / unarchive",
+	"help.show_archived":  "show / hide archived",
+	"help.pin":            "pin / unpin",
+	"help.lock":           "lock / unlock (no re-fetch)",
+	"help.export":         "export the listed articles",
+	"help.peek":           "peek at the first paragraph",
+	"help.show_error":     "show the full error",
+	"help.clear_warnings": "clear extraction warnings",
+	"help.move":           "move up / down (manual sort)",
+	"help.reset_progress": "reset reading progress",
+	"help.undo":           "undo / redo archive, pin, delete…",
+	"help.refetch":        "re-fetch article",
+	"help.refetch_safari": "re-fetch via Safari",
+	"help.images":         "view images",
+	"help.notes":          "edit notes",
+	"help.help":           "show this help",
+	"help.quit":           "quit",
+	"help.debug":          "debug fetch",
+}
+
+// loadMessages reads a locale file: a TOML table of identifiers and their
+// translations. Identifiers not in defaultMessages are rejected, to catch
+// typos and strings that have since been renamed.
+func loadMessages(path string) (messages, error) {
+	var msgs messages
+	if _, err := toml.DecodeFile(path, &msgs); err != nil {
+		return nil, fmt.Errorf("reading locale file: %w", err)
+	}
+	if err := checkMessageIDs(msgs, path); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// withOverrides returns c with overrides, such as the config file's
+// [messages] table, layered on top. source names them in errors.
+func (c messages) withOverrides(overrides map[string]string, source string) (messages, error) {
+	if err := checkMessageIDs(overrides, source); err != nil {
+		return nil, err
+	}
+	merged := make(messages, len(c)+len(overrides))
+	maps.Copy(merged, c)
+	maps.Copy(merged, overrides)
+	return merged, nil
+}
+
+// checkMessageIDs returns an error naming any identifiers in msgs that
+// aren't in defaultMessages.
+func checkMessageIDs(msgs map[string]string, source string) error {
+	var unknown []string
+	for id := range msgs {
+		if _, ok := defaultMessages[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown messages in %s: %s", source, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// text returns the message for id, falling back to English.
+func (c messages) text(id string) string {
+	if s, ok := c[id]; ok {
+		return s
+	}
+	if s, ok := defaultMessages[id]; ok {
+		return s
+	}
+	return id
+}
+
+// format returns the message for id formatted with args.
+func (c messages) format(id string, args ...any) string {
+	return fmt.Sprintf(c.text(id), args...)
+}
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"unicode"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestMessages(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	msgs, err := loadMessages(write("de.toml", `
+"header.articles" = "Artikel"
+"list.no_results" = "Keine Artikel zu '%s'"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		got, want string
+	}{
+		{msgs.text("header.articles"), "Artikel"},
+		{msgs.format("list.no_results", "rust"), "Keine Artikel zu 'rust'"},
+		// Untranslated messages fall back to English, as does a nil catalog.
+		{msgs.text("view.fetching"), "Fetching article..."},
+		{messages(nil).format("status.pinned", "Go"), `Pinned "Go"`},
+		// Unknown identifiers come back as is.
+		{msgs.text("no.such.message"), "no.such.message"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("got %q, want %q", tc.got, tc.want)
+		}
+	}
+
+	// Identifiers must exist in the English catalog.
+	_, err = loadMessages(write("typo.toml", `"header.artikles" = "Artikel"`))
+	if err == nil || !strings.Contains(err.Error(), "header.artikles") {
+		t.Errorf("loading an unknown identifier: err = %v", err)
+	}
+
+	// Overrides from the config file win over the locale file, and are
+	// checked the same way.
+	overridden, err := msgs.withOverrides(map[string]string{"header.articles": "Leseliste"}, "[messages]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := overridden.text("header.articles"); got != "Leseliste" {
+		t.Errorf("overridden header = %q", got)
+	}
+	if got := overridden.format("list.no_results", "go"); got != "Keine Artikel zu 'go'" {
+		t.Errorf("locale message lost by overriding another: %q", got)
+	}
+	if _, err := msgs.withOverrides(map[string]string{"view.fetchng": "..."}, "[messages]"); err == nil {
+		t.Errorf("overriding an unknown identifier succeeded")
+	}
+
+	// The TUI renders from the catalog.
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()).SetValue("rust"),
+		appState:    testState(t),
+		msgs:        msgs,
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	view := m.View()
+	for _, want := range []string{"Artikel", "Keine Artikel zu 'rust'", "[a]dd URL"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestErrorText(t *testing.T) {
+	var msgs messages
+	denied := &fs.PathError{Op: "mkdir", Path: "/data/articles/post", Err: fs.ErrPermission}
+	full := &fs.PathError{Op: "write", Path: "/data/articles/post/index.md", Err: syscall.ENOSPC}
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{&fetchError{err: errors.New("HTTP 503")}, "Couldn't fetch: HTTP 503 — press r to retry"},
+		{&saveError{err: fmt.Errorf("creating staging dir: %w", denied)}, "Couldn't save to disk: creating staging dir: mkdir /data/articles/post: permission denied — check the data directory's permissions"},
+		{&saveError{err: full}, "Couldn't save to disk: write /data/articles/post/index.md: no space left on device — free up some space and try again"},
+		{&saveError{err: errors.New("disk on fire")}, "Couldn't save to disk: disk on fire"},
+		// Permission errors are only save errors where saving failed.
+		{denied, "Error: mkdir /data/articles/post: permission denied"},
+		{errors.New("URL cannot be empty"), "Error: URL cannot be empty"},
+	} {
+		if got := msgs.errorText(tc.err); got != tc.want {
+			t.Errorf("errorText(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+// TestNoHardcodedMessages fails on English text written into the TUI
+// rather than taken from the catalog: string literals with letters in them
+// passed to fmt.Errorf or errors.New in Model methods, which have the
+// catalog at hand, or assigned to a status message anywhere.
+func TestNoHardcodedMessages(t *testing.T) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hasText reports whether e is a string literal with letters in it, or
+	// is built from one other than as a catalog identifier.
+	var hasText func(e ast.Expr) bool
+	hasText = func(e ast.Expr) bool {
+		switch e := e.(type) {
+		case *ast.BasicLit:
+			s, _ := strconv.Unquote(e.Value)
+			return e.Kind == token.STRING && strings.IndexFunc(s, unicode.IsLetter) >= 0
+		case *ast.BinaryExpr:
+			return hasText(e.X) || hasText(e.Y)
+		case *ast.ParenExpr:
+			return hasText(e.X)
+		case *ast.CallExpr:
+			if sel, ok := e.Fun.(*ast.SelectorExpr); ok && (sel.Sel.Name == "text" || sel.Sel.Name == "format") {
+				return false
+			}
+			return slices.ContainsFunc(e.Args, hasText)
+		}
+		return false
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || path == "messages.go" {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Recv == nil || n.Body == nil {
+					return true
+				}
+				recv := n.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); !ok || ident.Name != "Model" {
+					return true
+				}
+				ast.Inspect(n.Body, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok || len(call.Args) == 0 {
+						return true
+					}
+					sel, ok := call.Fun.(*ast.SelectorExpr)
+					if !ok {
+						return true
+					}
+					if pkg, ok := sel.X.(*ast.Ident); ok &&
+						(pkg.Name == "fmt" && sel.Sel.Name == "Errorf" || pkg.Name == "errors" && sel.Sel.Name == "New") &&
+						hasText(call.Args[0]) {
+						t.Errorf("%s: error text not from the message catalog", fset.Position(call.Pos()))
+					}
+					return true
+				})
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					if sel, ok := lhs.(*ast.SelectorExpr); ok && sel.Sel.Name == "statusMsg" &&
+						i < len(n.Rhs) && hasText(n.Rhs[i]) {
+						t.Errorf("%s: status message not from the message catalog", fset.Position(n.Pos()))
+					}
+				}
+			}
+			return true
+		})
+	}
+}
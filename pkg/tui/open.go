@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// openAction is a way of opening an article.
+type openAction string
+
+const (
+	openEditor  openAction = "editor"  // $EDITOR, with reading progress tracked
+	openPager   openAction = "pager"   // $PAGER, read-only
+	openBrowser openAction = "browser" // the source URL in the default browser
+	openPreview openAction = "preview" // a scrollable view inside shelf
+)
+
+// externalOpenedMsg reports the result of handing a file or URL to the OS.
+type externalOpenedMsg struct{ err error }
+
+// pagerFinishedMsg is sent when the pager exits.
+type pagerFinishedMsg struct{ err error }
+
+// notesEditorFinishedMsg is sent when the editor on an article's notes
+// exits.
+type notesEditorFinishedMsg struct{ err error }
+
+// actionForKey returns the open action bound to msg: Enter runs the
+// configured default, and each action also has its own key.
+func actionForKey(msg tea.KeyMsg, keys KeyMap, def openAction) (openAction, bool) {
+	switch {
+	case key.Matches(msg, keys.Open):
+		return def, true
+	case key.Matches(msg, keys.OpenEditor):
+		return openEditor, true
+	case key.Matches(msg, keys.OpenPager):
+		return openPager, true
+	case key.Matches(msg, keys.OpenBrowser):
+		return openBrowser, true
+	case key.Matches(msg, keys.OpenPreview):
+		return openPreview, true
+	}
+	return "", false
+}
+
+// openSelected opens the selected article with the given action.
+func (m Model) openSelected(action openAction) (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+	article := m.articles[m.cursor]
+	m.appState.RecordOpen(time.Now())
+	m.saveState()
+	if m.isNew(article) {
+		if m.seenNew == nil {
+			m.seenNew = make(map[string]bool)
+		}
+		m.seenNew[article.FilePath] = true
+	}
+
+	switch action {
+	case openPager:
+		return m.openInPager(article)
+	case openBrowser:
+		if article.SourceURL == "" {
+			m.err = errors.New(m.msgs.format("status.no_source_url", article.Title))
+			return m, nil
+		}
+		return m, openExternal(article.SourceURL)
+	case openPreview:
+		return m.openPreview(article)
+	default:
+		return m.openSelectedArticle()
+	}
+}
+
+func (m Model) openInPager(article storage.ArticleMeta) (tea.Model, tea.Cmd) {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	fpath := m.store.GetFilePath(article.FilePath)
+	c := exec.Command(shell, "-l", "-c", fmt.Sprintf("%s %q", pager, fpath))
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return pagerFinishedMsg{err: err}
+	})
+}
+
+// Opener returns the command that opens a file, directory or URL with the
+// OS default handler: open on macOS, xdg-open elsewhere.
+func Opener() string {
+	if runtime.GOOS == "darwin" {
+		return "open"
+	}
+	return "xdg-open"
+}
+
+// openExternal opens a file or URL with the OS default handler; see Opener.
+func openExternal(target string) tea.Cmd {
+	return func() tea.Msg {
+		opener := Opener()
+		if err := exec.Command(opener, target).Run(); err != nil {
+			return externalOpenedMsg{err: fmt.Errorf("%s %s: %w", opener, target, err)}
+		}
+		return externalOpenedMsg{}
+	}
+}
+
+// openDataDir shows data_dir in the OS file manager.
+func (m Model) openDataDir() (tea.Model, tea.Cmd) {
+	if _, err := os.Stat(m.cfg.DataDir); err != nil {
+		if os.IsNotExist(err) {
+			err = errors.New(m.msgs.format("status.no_data_dir", m.cfg.DataDir))
+		}
+		m.err = err
+		return m, nil
+	}
+	return m, openExternal(m.cfg.DataDir)
+}
+
+// revealSelected shows the selected article in the OS file manager: its
+// directory for a directory-format article, or else its file.
+func (m Model) revealSelected() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+	return m, reveal(revealTarget(m.store.GetFilePath(m.articles[m.cursor].FilePath)))
+}
+
+// revealTarget returns what to reveal for the article whose file is at
+// path: the directory of a directory-format article, or a flat file itself.
+func revealTarget(path string) string {
+	if filepath.Base(path) == "index.md" {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+// reveal shows target selected in its folder with open -R on macOS.
+// xdg-open can't select a file, so elsewhere it opens the folder
+// containing a file, or a directory itself.
+func reveal(target string) tea.Cmd {
+	return func() tea.Msg {
+		opener, args := Opener(), []string{"-R", target}
+		if runtime.GOOS != "darwin" {
+			dir := target
+			if info, err := os.Stat(target); err == nil && !info.IsDir() {
+				dir = filepath.Dir(target)
+			}
+			args = []string{dir}
+		}
+		if err := exec.Command(opener, args...).Run(); err != nil {
+			return externalOpenedMsg{err: fmt.Errorf("%s %s: %w", opener, target, err)}
+		}
+		return externalOpenedMsg{}
+	}
+}
+
+// openPreview shows the article's markdown in a scrollable view.
+func (m Model) openPreview(article storage.ArticleMeta) (tea.Model, tea.Cmd) {
+	full, err := m.store.Get(article.FilePath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m = m.showPreview(article.Title, full.Content)
+	m.previewNote = full.Meta.ArchiveNote
+	return m, nil
+}
+
+// showPreview shows body in the scrollable preview under title. Leaving it
+// returns to the current screen.
+func (m Model) showPreview(title, body string) Model {
+	m.previewTitle = title
+	m.previewNote = ""
+	m.previewReturn = m.state
+	if m.previewText == nil || m.previewText.body != body || m.previewText.width != m.width-4 {
+		m.previewText = newPreviewText(body, m.width-4)
+	}
+	m.preview = viewport.New(m.width-4, m.previewHeight())
+	m.preview.SetContent(m.previewText.content())
+	m = m.wrapPreviewAhead()
+	m.state = statePreview
+	return m
+}
+
+func (m Model) handlePreviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		// The wrapped text is kept, so reopening the same article is
+		// instant.
+		m.state = m.previewReturn
+		m.suppressQuit = true
+		return m, nil
+	case key.Matches(msg, m.keys.Top):
+		m.preview.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		if m.previewText.wrapTo(math.MaxInt) {
+			m.preview.SetContent(m.previewText.content())
+		}
+		m.preview.GotoBottom()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.preview, cmd = m.preview.Update(msg)
+	return m.wrapPreviewAhead(), cmd
+}
+
+// wrapPreviewAhead wraps the preview text a few screens past the bottom of
+// the view, so scrolling down never reaches the end of what's wrapped
+// before the end of the article.
+func (m Model) wrapPreviewAhead() Model {
+	if m.previewText.wrapTo(m.preview.YOffset + 4*m.preview.Height) {
+		m.preview.SetContent(m.previewText.content())
+	}
+	return m
+}
+
+// resizePreview fits the preview to a new window size, keeping the scroll
+// position.
+func (m Model) resizePreview() Model {
+	m.preview.Width = m.width - 4
+	m.preview.Height = m.previewHeight()
+	if m.previewText.width != m.width-4 {
+		m.previewText = newPreviewText(m.previewText.body, m.width-4)
+		m.previewText.wrapTo(m.preview.YOffset + m.preview.Height)
+		m.preview.SetContent(m.previewText.content())
+	}
+	return m.wrapPreviewAhead()
+}
+
+// previewHeight is the number of rows available to the preview.
+func (m Model) previewHeight() int {
+	return max(1, m.height-10)
+}
+
+// previewText is a preview's body wrapped to the preview's width. Wrapping
+// a book-length article takes long enough to notice, so lines are wrapped
+// as the preview is scrolled to them rather than all up front.
+type previewText struct {
+	body    string
+	width   int
+	lines   []string // the body's lines, unwrapped
+	next    int      // lines[next:] aren't wrapped yet
+	wrapped []string // lines[:next], wrapped
+}
+
+func newPreviewText(body string, width int) *previewText {
+	return &previewText{
+		body:  body,
+		width: width,
+		lines: strings.Split(strings.TrimSpace(body), "\n"),
+	}
+}
+
+// wrapTo wraps lines until at least rows are wrapped or there are none left,
+// reporting whether it wrapped any.
+func (p *previewText) wrapTo(rows int) bool {
+	style := lipgloss.NewStyle().Width(p.width)
+	start := p.next
+	for len(p.wrapped) < rows && p.next < len(p.lines) {
+		p.wrapped = append(p.wrapped, strings.Split(style.Render(p.lines[p.next]), "\n")...)
+		p.next++
+	}
+	return p.next > start
+}
+
+// content returns the text wrapped so far, for the viewport.
+func (p *previewText) content() string {
+	return strings.Join(p.wrapped, "\n")
+}
+
+// scrollPercent is how far through the body the preview is scrolled. Until
+// the whole body is wrapped it's estimated from the lines wrapped so far.
+func (m Model) scrollPercent() float64 {
+	p := m.previewText
+	if p.next == len(p.lines) {
+		return m.preview.ScrollPercent()
+	}
+	if p.next == 0 {
+		return 0
+	}
+	total := float64(len(p.wrapped)) * float64(len(p.lines)) / float64(p.next)
+	return min(1, float64(m.preview.YOffset)/max(1, total-float64(m.preview.Height)))
+}
+
+func (m Model) renderPreview() string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.SelectedTitle.Render(truncateString(m.previewTitle, m.width-4, m.ellipsis)))
+	sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" · %d%%", int(m.scrollPercent()*100))))
+	if m.previewNote != "" {
+		sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("view.archived_because", m.previewNote)))
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(m.preview.View())
+	return sb.String()
+}
+
+// openNotes opens the selected article's notes.md in $EDITOR, creating it
+// when the editor first writes it.
+func (m Model) openNotes() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+	path, err := m.store.NotesPath(m.articles[m.cursor].FilePath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "nvim"
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	c := exec.Command(shell, "-l", "-c", fmt.Sprintf("%s %q", editor, path))
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return notesEditorFinishedMsg{err: err}
+	})
+}
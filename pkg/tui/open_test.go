@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/irfansharif/shelf/pkg/state"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestActionForKey(t *testing.T) {
+	keys := DefaultKeyMap()
+	press := func(s string) tea.KeyMsg {
+		if s == "enter" {
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	for _, tc := range []struct {
+		def    openAction
+		key    string
+		want   openAction
+		wantOK bool
+	}{
+		{openEditor, "enter", openEditor, true},
+		{openPager, "enter", openPager, true},
+		{openBrowser, "enter", openBrowser, true},
+		{openPreview, "enter", openPreview, true},
+		// Secondary keys ignore the configured default.
+		{openPager, "E", openEditor, true},
+		{openEditor, "v", openPager, true},
+		{openEditor, "o", openBrowser, true},
+		{openBrowser, "p", openPreview, true},
+		// Other keys aren't open actions.
+		{openEditor, "x", "", false},
+		{openEditor, "e", "", false},
+	} {
+		got, ok := actionForKey(press(tc.key), keys, tc.def)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("actionForKey(%q, default %s) = %q, %t; want %q, %t", tc.key, tc.def, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestEditorView(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContent("a:b", "---\ntitle: a:b\n---\n\nBody.\n", nil); err != nil {
+		t.Fatal(err)
+	}
+	appState, err := state.Load(filepath.Join(dir, state.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:        store,
+		searchInput:  NewSearchInput(DefaultStyles()),
+		appState:     appState,
+		positionFile: filepath.Join(dir, "pos"),
+	}
+	m.refreshArticles()
+	article := m.articles[0]
+
+	// The position vim writes on exit is remembered, even with a colon in
+	// the path, and the article reopens there.
+	pos := m.store.GetFilePath(article.FilePath) + ":40:7:25"
+	if err := os.WriteFile(m.positionFile, []byte(pos+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.savePositionFromFile()
+	article = m.articles[0]
+	want := state.EditorView{Line: 40, Col: 7, TopLine: 25}
+	if got := m.editorView(article); got != want || article.Progress != 40 {
+		t.Errorf("editorView() = %+v, progress %d; want %+v, progress 40", got, article.Progress, want)
+	}
+	if reloaded, err := state.Load(filepath.Join(dir, state.FileName)); err != nil || reloaded.EditorViews[article.FilePath] != want {
+		t.Errorf("view not saved to state: %+v, %v", reloaded.EditorViews, err)
+	}
+
+	// Once progress moves elsewhere, the remembered view is stale.
+	if err := m.store.UpdateProgress(article.FilePath, 0); err != nil {
+		t.Fatal(err)
+	}
+	m.refreshArticles()
+	if got := m.editorView(m.articles[0]); got != (state.EditorView{}) {
+		t.Errorf("after resetting progress, editorView() = %+v", got)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	// vim only checkpoints with an interval set.
+	if cmd := vimEditorCommand("nvim", "/a.md", "/pos", state.EditorView{}, 0); strings.Contains(cmd, "timer_start") {
+		t.Errorf("checkpoint 0: %s", cmd)
+	}
+	if cmd := vimEditorCommand("nvim", "/a.md", "/pos", state.EditorView{}, time.Minute); !strings.Contains(cmd, "timer_start(60000, ") {
+		t.Errorf("checkpoint 1m: %s", cmd)
+	}
+
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContent("essay", "---\ntitle: Essay\n---\n\nBody.\n", nil); err != nil {
+		t.Fatal(err)
+	}
+	appState, err := state.Load(filepath.Join(dir, state.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:        store,
+		searchInput:  NewSearchInput(DefaultStyles()),
+		appState:     appState,
+		positionFile: filepath.Join(dir, "pos"),
+		checkpoint:   time.Minute,
+		tmuxPaneID:   "%42",
+	}
+	m.refreshArticles()
+
+	// While the editor pane is open, each checkpoint is saved as progress.
+	pos := m.store.GetFilePath(m.articles[0].FilePath) + ":12:1:3"
+	if err := os.WriteFile(m.positionFile, []byte(pos+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next, cmd := m.Update(checkpointMsg{})
+	m = next.(Model)
+	if cmd == nil || m.articles[0].Progress != 12 {
+		t.Fatalf("checkpoint with the pane open: cmd %v, progress %d; want 12 and another tick", cmd != nil, m.articles[0].Progress)
+	}
+
+	// Once it's closed, checkpointing stops.
+	m.tmuxPaneID = ""
+	m.checkpointing = true
+	next, cmd = m.Update(checkpointMsg{})
+	if m = next.(Model); cmd != nil || m.checkpointing {
+		t.Errorf("checkpoint with no pane: cmd %v, checkpointing %v", cmd != nil, m.checkpointing)
+	}
+}
+
+func TestPreviewWrapsAsScrolled(t *testing.T) {
+	var sb strings.Builder
+	for i := range 2000 {
+		fmt.Fprintf(&sb, "Line %d is long enough that it wraps in a narrow preview.\n", i)
+	}
+	body := sb.String()
+	m := Model{keys: DefaultKeyMap(), styles: DefaultStyles(), width: 44, height: 20}
+	m = m.showPreview("Book", body)
+
+	// Only the first few screens are wrapped when the preview opens.
+	p := m.previewText
+	if p.next == 0 || p.next > 100 {
+		t.Fatalf("wrapped %d of %d lines on open, want the first few screens", p.next, len(p.lines))
+	}
+	// Scrolling down wraps more ahead of the view.
+	next := p.next
+	for range 20 {
+		model, _ := m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyPgDown})
+		m = model.(Model)
+	}
+	if m.previewText.next <= next || m.preview.YOffset == 0 {
+		t.Fatalf("after paging down: offset %d, %d lines wrapped; want more than %d", m.preview.YOffset, m.previewText.next, next)
+	}
+	if pct := m.scrollPercent(); pct <= 0 || pct >= 1 {
+		t.Fatalf("scroll percent part way through = %v", pct)
+	}
+
+	// Jumping to the bottom wraps the rest, the same as wrapping it all at
+	// once would.
+	model, _ := m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	m = model.(Model)
+	want := lipgloss.NewStyle().Width(40).Render(strings.TrimSpace(body))
+	if got := m.previewText.content(); got != want {
+		t.Fatalf("wrapped bit by bit differs from wrapped at once")
+	}
+	if !strings.Contains(m.preview.View(), "Line 1999") || m.scrollPercent() != 1 {
+		t.Fatalf("bottom of preview at %v%%:\n%s", m.scrollPercent()*100, m.preview.View())
+	}
+
+	// Leaving and reopening the same article reuses the wrapped text.
+	model, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(Model)
+	if m.showPreview("Book", body).previewText != p {
+		t.Fatalf("reopening the preview wrapped the article again")
+	}
+}
+
+func TestOpenDataDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	m := Model{keys: DefaultKeyMap(), styles: DefaultStyles(), searchInput: NewSearchInput(DefaultStyles()), appState: testState(t), width: 80, height: 30}
+	m.cfg.DataDir = dir
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})
+	m = next.(Model)
+	if cmd != nil || m.err == nil || m.err.Error() != "data directory "+dir+" doesn't exist" {
+		t.Fatalf("O with no data directory: cmd %v, err %v", cmd != nil, m.err)
+	}
+}
+
+func TestRevealTarget(t *testing.T) {
+	for _, tc := range []struct{ path, want string }{
+		{"/data/articles/essay/index.md", "/data/articles/essay"},
+		{"/data/articles/essay.md", "/data/articles/essay.md"},
+	} {
+		if got := revealTarget(tc.path); got != tc.want {
+			t.Errorf("revealTarget(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// overwriteDetails describes the saved article the overwrite prompt would
+// replace, so it isn't clobbered unseen.
+type overwriteDetails struct {
+	saved      time.Time
+	size       int64
+	newSize    int64 // size of the fetched article; 0 until it's re-fetched
+	notes      int   // [[note]] markers
+	highlights int   // ==highlighted== passages
+}
+
+// overwriteDetailsFor looks up the saved article at filePath for the
+// overwrite prompt; the zero value if it's not listed.
+func (m Model) overwriteDetailsFor(filePath string) overwriteDetails {
+	for _, a := range m.store.List() {
+		if a.FilePath != filePath {
+			continue
+		}
+		d := overwriteDetails{
+			saved: a.SavedAt,
+			size:  m.store.ArticleSize(a),
+			notes: a.NoteCount,
+		}
+		if highlights, err := m.store.Highlights(filePath); err == nil {
+			d.highlights = len(highlights)
+		}
+		if m.pendingResult != nil {
+			d.newSize = int64(len(m.pendingResult.Content))
+			for _, img := range m.pendingResult.Images {
+				d.newSize += int64(len(img.Data))
+			}
+		}
+		return d
+	}
+	return overwriteDetails{}
+}
+
+// renderOverwriteDetails renders what's saved under the overwrite prompt:
+// when and how big, and a warning if the reader added anything to it that
+// overwriting would lose. A notes.md is kept, so isn't warned about.
+func (m Model) renderOverwriteDetails() string {
+	d := m.overwriteInfo
+	if d.saved.IsZero() {
+		return ""
+	}
+	details := []string{
+		m.msgs.format("view.overwrite_saved", d.saved.Local().Format(time.DateOnly), formatRelativeTime(m.msgs, d.saved, time.Now(), timeFormat{})),
+		storage.FormatSize(d.size),
+	}
+	if d.newSize > 0 {
+		details = append(details, m.msgs.format("view.overwrite_new_size", storage.FormatSize(d.newSize)))
+	}
+	out := "\n" + m.styles.Muted.Render(strings.Join(details, " · "))
+
+	var added []string
+	if d.notes > 0 {
+		added = append(added, m.msgs.format("view.overwrite_notes", d.notes))
+	}
+	if d.highlights > 0 {
+		added = append(added, m.msgs.format("view.overwrite_highlights", d.highlights))
+	}
+	if len(added) > 0 {
+		out += "\n" + m.styles.Error.Render(m.msgs.format("view.overwrite_loses", strings.Join(added, ", ")))
+	}
+	return out
+}
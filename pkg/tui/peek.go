@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// peekLength is how much of an article's first paragraph a peek shows.
+	peekLength = 400
+	// peekCacheSize is how many peeked articles are kept, so going back and
+	// forth between a few doesn't read them again.
+	peekCacheSize = 8
+)
+
+// peek is what the quick-peek overlay shows for an article.
+type peek struct {
+	title     string
+	author    string
+	paragraph string
+	fetchTime time.Duration // how long extraction took; 0 if unknown
+	archived  string        // why it was archived (archive_note:), if known
+	lines     int           // the article's TotalLines when read, to notice edits
+}
+
+// peekSelected shows the selected article's title, author and first
+// paragraph above the footer, until the next key.
+func (m Model) peekSelected() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	meta := m.articles[m.cursor]
+	p, ok := m.peekCache[meta.FilePath]
+	if !ok || p.lines != meta.TotalLines {
+		article, err := m.store.Get(meta.FilePath)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		p = peek{
+			title:     articleTitle(m.msgs, article.Meta),
+			author:    article.Meta.Author,
+			paragraph: firstParagraph(article.Content, peekLength, m.ellipsis),
+			fetchTime: article.Meta.FetchTime,
+			archived:  article.Meta.ArchiveNote,
+			lines:     meta.TotalLines,
+		}
+		m.cachePeek(meta.FilePath, p)
+	}
+	m.peeked = p
+	m.state = statePeek
+	return m, nil
+}
+
+// cachePeek remembers an article's peek, forgetting the one peeked longest
+// ago once there are peekCacheSize.
+func (m *Model) cachePeek(filePath string, p peek) {
+	if m.peekCache == nil {
+		m.peekCache = make(map[string]peek)
+	}
+	if _, ok := m.peekCache[filePath]; !ok {
+		m.peekOrder = append(m.peekOrder, filePath)
+		if len(m.peekOrder) > peekCacheSize {
+			delete(m.peekCache, m.peekOrder[0])
+			m.peekOrder = m.peekOrder[1:]
+		}
+	}
+	m.peekCache[filePath] = p
+}
+
+// firstParagraph returns the first paragraph of prose in an article's
+// markdown, skipping headings, images, rules and code, joined onto one line
+// and cut to at most limit columns, ending with ellipsis.
+func firstParagraph(body string, limit int, ellipsis string) string {
+	var para []string
+	inCode := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		switch {
+		case inCode:
+			continue
+		case line == "":
+			if len(para) > 0 {
+				return truncateString(strings.Join(para, " "), limit, ellipsis)
+			}
+			continue
+		case len(para) == 0 && (strings.HasPrefix(line, "#") || strings.HasPrefix(line, "![") || strings.Trim(line, "-*_ ") == ""):
+			continue
+		}
+		para = append(para, line)
+	}
+	return truncateString(strings.Join(para, " "), limit, ellipsis)
+}
+
+// renderPeek renders the quick-peek overlay in at most maxLines lines.
+func (m Model) renderPeek(maxLines int) string {
+	width := max(m.width-4, 20)
+	lines := []string{m.styles.Header.Render(truncateString(m.peeked.title, width, m.ellipsis))}
+	var details []string
+	if m.peeked.author != "" {
+		details = append(details, m.peeked.author)
+	}
+	if m.peeked.fetchTime > 0 {
+		details = append(details, m.msgs.format("view.fetched_in", formatFetchTime(m.peeked.fetchTime)))
+	}
+	if m.peeked.archived != "" {
+		details = append(details, m.msgs.format("view.archived_because", m.peeked.archived))
+	}
+	if len(details) > 0 {
+		lines = append(lines, m.styles.Muted.Render(truncateString(strings.Join(details, " · "), width, m.ellipsis)))
+	}
+	lines = append(lines, "")
+	if m.peeked.paragraph == "" {
+		lines = append(lines, m.styles.Muted.Render(m.msgs.text("view.peek_empty")))
+	} else {
+		lines = append(lines, strings.Split(lipgloss.NewStyle().Width(width).Render(m.peeked.paragraph), "\n")...)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatFetchTime formats how long extraction took, e.g. "350ms" or "2.4s".
+func formatFetchTime(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// peekLines returns how many lines the quick-peek overlay needs.
+func (m Model) peekLines() int {
+	return strings.Count(m.renderPeek(1<<30), "\n") + 1
+}
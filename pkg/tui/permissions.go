@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/safari"
+)
+
+// permissionsCheckedMsg carries the result of probing macOS permissions
+// before a Safari import.
+type permissionsCheckedMsg struct{ perms []safari.Permission }
+
+func (m Model) checkSafariPermissions() tea.Cmd {
+	sources := m.importSources
+	return func() tea.Msg {
+		return permissionsCheckedMsg{perms: safari.CheckPermissions(sources)}
+	}
+}
+
+// startSafariImport begins a Safari import, first asking whether to
+// resume a paused one instead (see handleConfirmResumeKeys).
+func (m Model) startSafariImport() (tea.Model, tea.Cmd) {
+	if len(m.appState.ImportQueue) > 0 {
+		m.state = stateConfirmResume
+		m.importWindow = false
+		return m, nil
+	}
+	return m.gatherSafariImport()
+}
+
+// gatherSafariImport gathers tabs for a new Safari import. Unless already
+// acknowledged this session, permissions are checked first so missing
+// ones can be granted before the sources fail with cryptic errors.
+func (m Model) gatherSafariImport() (tea.Model, tea.Cmd) {
+	m.state = stateGatheringTabs
+	m.err = nil
+	m.importWarnings = nil
+	if m.permissionsAcknowledged {
+		return m, tea.Batch(m.spinner.Tick, m.gatherSafariTabs())
+	}
+	return m, tea.Batch(m.spinner.Tick, m.checkSafariPermissions())
+}
+
+func (m Model) handlePermissionsChecked(msg permissionsCheckedMsg) (tea.Model, tea.Cmd) {
+	if m.state != stateGatheringTabs && m.state != statePermissions {
+		return m, nil // cancelled
+	}
+	m.permissions = msg.perms
+	for _, p := range msg.perms {
+		if !p.Granted {
+			m.state = statePermissions
+			m.permCursor = min(m.permCursor, len(msg.perms)-1)
+			return m, nil
+		}
+	}
+	m.state = stateGatheringTabs
+	return m, tea.Batch(m.spinner.Tick, m.gatherSafariTabs())
+}
+
+func (m Model) handlePermissionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.permCursor > 0 {
+			m.permCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.permCursor < len(m.permissions)-1 {
+			m.permCursor++
+		}
+	case msg.String() == "o":
+		perm := m.permissions[m.permCursor]
+		return m, func() tea.Msg {
+			return externalOpenedMsg{err: perm.OpenSettings()}
+		}
+	case msg.String() == "r":
+		return m, m.checkSafariPermissions()
+	case key.Matches(msg, m.keys.Submit):
+		// Continue with whatever sources are available.
+		m.permissionsAcknowledged = true
+		m.state = stateGatheringTabs
+		return m, tea.Batch(m.spinner.Tick, m.gatherSafariTabs())
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+	}
+	return m, nil
+}
+
+func (m Model) renderPermissions() string {
+	var sb strings.Builder
+	sb.WriteString("Safari import works best with these macOS permissions:")
+	sb.WriteString("\n\n")
+
+	contentWidth := m.width - 4
+	for i, p := range m.permissions {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		check := "[✗] "
+		if p.Granted {
+			check = "[✓] "
+		}
+		title := check + p.Name
+		if i == m.permCursor {
+			sb.WriteString(m.styles.SelectionMarker.Render(""))
+			sb.WriteString(m.styles.SelectedTitle.Render(title))
+		} else {
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.ListItemTitle.Render(title))
+		}
+		sb.WriteString("\n  ")
+		desc := p.Purpose
+		if !p.Granted && p.Detail != "" {
+			desc += " — " + p.Detail
+		}
+		sb.WriteString(m.styles.ListItemDesc.Render(truncateString(desc, contentWidth-2, m.ellipsis)))
+	}
+	return sb.String()
+}
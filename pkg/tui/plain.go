@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+// RunPlain runs shelf without the full-screen interface, for screen readers
+// and pipes. The list is printed as numbered lines without styling, and
+// actions are typed at a prompt read from in. It uses the same list,
+// filters and messages as the TUI; only the presentation differs. When in
+// runs out (e.g. it isn't a terminal), RunPlain returns after the list.
+func (m Model) RunPlain(in io.Reader, w io.Writer) error {
+	if m.err != nil {
+		fmt.Fprintln(w, m.msgs.errorText(m.err))
+		m.err = nil
+	}
+	m.printPlainList(w)
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(w, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "q" || line == "quit" {
+			return nil
+		}
+		m = m.runPlainCommand(line, scanner, w)
+		if m.err != nil {
+			fmt.Fprintln(w, m.msgs.errorText(m.err))
+		} else if m.statusMsg != "" {
+			fmt.Fprintln(w, m.statusMsg)
+		}
+		m.err, m.statusMsg = nil, ""
+	}
+}
+
+// runPlainCommand carries out one line typed at RunPlain's prompt. scanner
+// supplies the answer to a delete confirmation.
+func (m Model) runPlainCommand(line string, scanner *bufio.Scanner, w io.Writer) Model {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(cmd, "/") && len(cmd) > 1 {
+		cmd, arg = "/", strings.TrimSpace(line[1:])
+	}
+	switch cmd {
+	case "":
+	case "?", "help":
+		fmt.Fprintln(w, m.msgs.text("plain.help"))
+	case "l", "list":
+		m.printPlainList(w)
+	case "X":
+		m.showArchived = !m.showArchived
+		m.refreshArticles()
+		m.printPlainList(w)
+	case "/":
+		m.activeSearch = ""
+		m.searchInput = m.searchInput.SetValue(arg)
+		m.refreshArticles()
+		m.printPlainList(w)
+	case "a":
+		return m.plainAdd(arg)
+	case "e", "x", "d":
+		if !m.selectPlain(arg) {
+			m.err = errors.New(m.msgs.format("plain.no_article", arg))
+			return m
+		}
+		switch cmd {
+		case "e":
+			m.err = m.plainEdit(m.articles[m.cursor])
+		case "x":
+			next, _ := m.archiveSelectedArticle()
+			m = next.(Model)
+		case "d":
+			article := m.articles[m.cursor]
+			fmt.Fprintf(w, "%s [y/N] ", m.msgs.format("status.confirm_delete", article.Title))
+			if !scanner.Scan() || !strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+				return m
+			}
+			if m.err = m.store.Delete(article.FilePath); m.err == nil {
+				m.refreshArticles()
+			}
+		}
+	default:
+		if !m.selectPlain(cmd) {
+			m.err = errors.New(m.msgs.format("plain.unknown_command", line))
+			return m
+		}
+		m.printPlainArticle(w, m.articles[m.cursor])
+	}
+	return m
+}
+
+// selectPlain moves the cursor to the article numbered arg in the last
+// printed list, reporting whether there is one.
+func (m *Model) selectPlain(arg string) bool {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(m.articles) {
+		return false
+	}
+	m.cursor = n - 1
+	return true
+}
+
+// printPlainList prints the articles as numbered entries: the title, then
+// an indented line of details.
+func (m Model) printPlainList(w io.Writer) {
+	header := m.msgs.text("header.articles")
+	if m.searchInput.Value() != "" {
+		header += ", " + m.searchInput.Value()
+	}
+	if m.showArchived {
+		header += " " + m.msgs.text("header.with_archived")
+	}
+	fmt.Fprintf(w, "%s (%d)\n", header, len(m.articles))
+	if len(m.articles) == 0 {
+		if m.searchInput.Value() != "" {
+			fmt.Fprintln(w, m.msgs.format("list.no_results", m.searchInput.Value()))
+		} else {
+			fmt.Fprintln(w, m.msgs.text("list.empty"))
+		}
+		return
+	}
+	for i, a := range m.articles {
+		a.FileSize = m.store.ArticleSize(a)
+		if !m.showSection {
+			a.SourceSection = ""
+		}
+		title := a.Title
+		if title == "" {
+			title = m.msgs.text("view.untitled")
+		}
+		var marks []string
+		if m.isNew(a) {
+			marks = append(marks, m.msgs.text("plain.new"))
+		}
+		if a.IsPinned() {
+			marks = append(marks, m.msgs.text("plain.pinned"))
+		}
+		if a.Locked {
+			marks = append(marks, m.msgs.text("plain.locked"))
+		}
+		if a.IsArchived() {
+			marks = append(marks, m.msgs.text("plain.archived"))
+		}
+		if len(marks) > 0 {
+			title += " (" + strings.Join(marks, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%d. %s\n", i+1, title)
+		details := articleDesc(m.msgs, a, m.timeFormat)
+		if len(a.Tags) > 0 {
+			details += " · " + m.msgs.format("view.tags", strings.Join(a.Tags, ", "))
+		}
+		if len(a.Warnings) > 0 {
+			details += " · " + m.msgs.format("plain.warnings", strings.Join(a.Warnings, "; "))
+		}
+		fmt.Fprintf(w, "   %s\n", details)
+	}
+}
+
+// printPlainArticle prints an article's title and markdown body.
+func (m Model) printPlainArticle(w io.Writer, meta storage.ArticleMeta) {
+	article, err := m.store.Get(meta.FilePath)
+	if err != nil {
+		fmt.Fprintln(w, m.msgs.format("status.error", err))
+		return
+	}
+	fmt.Fprintf(w, "%s\n\n%s\n", meta.Title, strings.Trim(article.Content, "\n"))
+}
+
+// plainEdit opens an article in $EDITOR on the process's own terminal.
+func (m Model) plainEdit(meta storage.ArticleMeta) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "nvim"
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	c := exec.Command(shell, "-l", "-c", fmt.Sprintf("%s %q", editor, m.store.GetFilePath(meta.FilePath)))
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
+// plainAdd fetches and saves the article at rawURL, as "a" does in the TUI
+// but without offering to overwrite an existing one.
+func (m Model) plainAdd(rawURL string) Model {
+	url, err := validateAndNormalizeURL(rawURL)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	result, err := m.extract.Extract(url)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	images := make([]storage.ImageFile, len(result.Images))
+	for i, img := range result.Images {
+		images[i] = storage.ImageFile{Path: img.Path, Data: img.Data}
+	}
+	if err := m.store.SaveContent(result.Title, result.Content, images); err != nil {
+		var existsErr *storage.ErrArticleExists
+		if errors.As(err, &existsErr) {
+			m.err = errors.New(m.msgs.format("plain.already_saved", existsErr.Title))
+			return m
+		}
+		m.err = &saveError{err: err}
+		return m
+	}
+	m.refreshArticles()
+	m.statusMsg = m.msgs.format("status.saved", result.Title)
+	return m
+}
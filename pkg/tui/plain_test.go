@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestRunPlain(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, a := range []struct{ title, tags string }{
+		{"Rust Async", "rust"},
+		{"Go Generics", "go, pinned"},
+		{"Old Rust", "rust, archived"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\nauthor: Jane Doe\nsaved: 2024-01-0%dT00:00:00Z\ntags: %s\n---\n\nBody of %s.\n", a.title, i+1, a.tags, a.title)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		timeFormat:  timeFormat{style: timeAbsolute},
+	}
+	m.refreshArticles()
+
+	run := func(input string) string {
+		t.Helper()
+		var out strings.Builder
+		if err := m.RunPlain(strings.NewReader(input), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	// Without input, just the list; no escape sequences.
+	got := run("")
+	want := `Articles (2)
+1. Go Generics (pinned)
+   Jane Doe · 2024-01-02 · 111 B · tags: go, pinned
+2. Rust Async
+   Jane Doe · 2024-01-01 · 103 B · tags: rust
+> 
+`
+	if got != want {
+		t.Errorf("list:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("plain output contains escape sequences: %q", got)
+	}
+
+	// Reading, searching and showing archived articles.
+	got = run("2\n/rust\nX\nbogus\n9\nq\nnot reached\n")
+	for _, want := range []string{
+		"> Rust Async\n\nBody of Rust Async.\n",
+		"Articles, rust (1)\n1. Rust Async\n",
+		"Articles, rust (+archived) (2)\n1. Rust Async\n",
+		"2. Old Rust (archived)\n",
+		`unknown command "bogus"`,
+		`unknown command "9"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	// Archiving and deleting change the store; deleting asks first.
+	got = run("x 2\nd 1\nn\nd 1\ny\nl\n")
+	if !strings.Contains(got, `Archived "Rust Async"`) || !strings.Contains(got, `Delete "Go Generics"? This cannot be undone. [y/N]`) {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	if n := store.Count(); n != 2 {
+		t.Errorf("%d articles left, want 2", n)
+	}
+	if !strings.HasSuffix(got, "Articles (0)\nNo articles saved yet. Press 'a' to add a URL.\n> \n") {
+		t.Errorf("final list:\n%s", got)
+	}
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// QuitFilter is the filter (see tea.WithFilter) for the program running the
+// TUI. It drops quit and interrupt messages the model doesn't allow (see
+// AllowsQuit), so that ctrl+c cancels the current screen or operation
+// instead of killing shelf. Bubble Tea v1 sends InterruptMsg for SIGINT,
+// not QuitMsg.
+//
+// A ctrl+c that cancels a screen returns to the list and sets suppressQuit,
+// so a SIGINT-generated quit arriving just after is still dropped; the next
+// message clears it.
+func QuitFilter(m tea.Model, msg tea.Msg) tea.Msg {
+	switch msg.(type) {
+	case tea.QuitMsg, tea.InterruptMsg:
+		if model, ok := m.(Model); ok && !model.AllowsQuit() {
+			return nil
+		}
+	}
+	return msg
+}
+
+// AllowsQuit reports whether the program may quit now: always with
+// ctrl_c = "quit", and otherwise only InListState.
+func (m Model) AllowsQuit() bool {
+	return m.ctrlCQuits || m.InListState()
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitFilter(t *testing.T) {
+	ctrlC := tea.KeyMsg{Type: tea.KeyCtrlC}
+	passes := func(m Model, msg tea.Msg) bool { return QuitFilter(m, msg) != nil }
+	preview := func(ctrlCQuits bool) Model {
+		m := Model{keys: DefaultKeyMap(), styles: DefaultStyles(), width: 80, height: 30, ctrlCQuits: ctrlCQuits}
+		return m.showPreview("Essay", "Body.")
+	}
+
+	// Other messages always pass.
+	if !passes(preview(false), tea.WindowSizeMsg{}) {
+		t.Errorf("filter dropped a non-quit message")
+	}
+
+	// By default ctrl+c backs out of a screen, and the quit SIGINT sends
+	// right after is dropped until the next message.
+	m := preview(false)
+	if passes(m, tea.QuitMsg{}) || passes(m, tea.InterruptMsg{}) {
+		t.Errorf("quit let through away from the list")
+	}
+	next, cmd := m.Update(ctrlC)
+	m = next.(Model)
+	if m.state != stateList || cmd != nil {
+		t.Fatalf("ctrl+c in preview: state %v, cmd %v; want back to the list", m.state, cmd)
+	}
+	if passes(m, tea.InterruptMsg{}) {
+		t.Errorf("interrupt let through right after ctrl+c cancelled")
+	}
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = next.(Model)
+	if !passes(m, tea.QuitMsg{}) || !passes(m, tea.InterruptMsg{}) {
+		t.Errorf("quit dropped in the list")
+	}
+
+	// With ctrl_c = "quit", ctrl+c quits from any screen.
+	m = preview(true)
+	next, cmd = m.Update(ctrlC)
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatalf("ctrl+c in preview: no command, want tea.Quit")
+	}
+	if msg := cmd(); !passes(m, msg) {
+		t.Errorf("ctrl+c in preview: %T dropped, want it let through", msg)
+	}
+	if !passes(m, tea.InterruptMsg{}) {
+		t.Errorf("interrupt dropped with ctrl_c = \"quit\"")
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"sync"
+	"time"
+)
+
+// hostLimiter is a per-host token bucket rate limiter. Requests to the same
+// host are spaced out to at most rate per second (after an initial burst),
+// while requests to different hosts don't wait on each other. It is safe for
+// concurrent use.
+type hostLimiter struct {
+	rate  float64 // tokens added per second; <= 0 disables limiting
+	burst float64 // bucket capacity
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newHostLimiter returns a limiter allowing rate requests/sec per host with
+// the given burst size.
+func newHostLimiter(rate float64, burst int) *hostLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &hostLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// take takes a token from host's bucket if one is available at now and
+// returns 0, or else leaves the bucket be and returns how long until one
+// is. Callers try again after that long rather than sleeping on a token,
+// so a request kept waiting doesn't hold a slot other hosts could use.
+func (l *hostLimiter) take(host string, now time.Time) time.Duration {
+	if l == nil || l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[host] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/config"
+)
+
+// configPollInterval is how often the config file is checked for changes
+// with watch_config.
+const configPollInterval = 2 * time.Second
+
+// configPolledMsg is the result of checking the config file for changes.
+// cfg is nil if it hasn't changed.
+type configPolledMsg struct {
+	cfg     *config.Config
+	modTime time.Time
+	err     error
+}
+
+// applyConfig applies the settings that can change while shelf runs: at
+// startup, and again whenever the config file is reloaded.
+func (m *Model) applyConfig(cfg config.Config) error {
+	m.cfg = cfg
+	m.savedSearches = cfg.SavedSearches
+	m.macros = cfg.Macros
+	m.deleteStyle = cfg.DeleteStyle
+	m.overwrite = cfg.Overwrite
+	m.askArchiveNote = cfg.ArchiveNote
+	m.confirmQuit = cfg.ConfirmQuit
+	m.ctrlCQuits = cfg.CtrlC == "quit"
+	m.manualOrder = cfg.Sort == "manual"
+	m.tmuxNewPanes = cfg.TmuxPanes == "new"
+	m.checkpoint = time.Duration(cfg.Checkpoint) * time.Second
+	m.openAction = openAction(cfg.OpenAction)
+	m.density = density(cfg.Density)
+	m.showSection = cfg.SourceSection
+	m.matchCounts = cfg.MatchCounts
+	m.domainMarks = cfg.DomainMarks
+	m.timeFormat = timeFormat{style: timeStyle(cfg.TimeFormat), layout: cfg.DateLayout}
+	m.ellipsis = cfg.Ellipsis
+	m.importFetchTitles = cfg.ImportFetchTitles
+	m.importSort = importSort(cfg.ImportSort)
+	m.importSources = cfg.ImportSources
+	m.restoreSession = cfg.RestoreSession
+
+	m.spinner.Spinner = spinner.Dot
+	if style, ok := spinners[cfg.Spinner]; ok {
+		m.spinner.Spinner = style
+	}
+
+	var firstErr error
+	m.msgs = nil
+	if cfg.Locale != "" {
+		msgs, err := loadMessages(cfg.Locale)
+		if err != nil {
+			firstErr = err
+		}
+		m.msgs = msgs
+	}
+	if len(cfg.Messages) > 0 {
+		if msgs, err := m.msgs.withOverrides(cfg.Messages, "[messages] in "+config.Path()); err != nil {
+			firstErr = cmp.Or(firstErr, err)
+		} else {
+			m.msgs = msgs
+		}
+	}
+	return firstErr
+}
+
+// watchConfig checks the config file for changes after configPollInterval,
+// reading it again if it has been modified.
+func (m Model) watchConfig() tea.Cmd {
+	since := m.configModTime
+	return tea.Tick(configPollInterval, func(time.Time) tea.Msg {
+		info, err := os.Stat(config.Path())
+		if err != nil || !info.ModTime().After(since) {
+			return configPolledMsg{modTime: since}
+		}
+		cfg, err := config.Load()
+		return configPolledMsg{cfg: &cfg, modTime: info.ModTime(), err: err}
+	})
+}
+
+// handleConfigPolled applies a changed config file, saying which changes
+// need a restart, and keeps watching unless watch_config was turned off.
+func (m Model) handleConfigPolled(msg configPolledMsg) (tea.Model, tea.Cmd) {
+	m.configModTime = msg.modTime
+	switch {
+	case msg.err != nil:
+		// Keep the settings in effect until the file is fixed.
+		m.err = fmt.Errorf(m.msgs.text("status.config_reload_failed"), msg.err)
+	case msg.cfg != nil:
+		old := m.cfg
+		if err := m.applyConfig(*msg.cfg); err != nil {
+			m.err = err
+		}
+		if old.Sort != msg.cfg.Sort {
+			if err := m.store.SetListOrder(msg.cfg.Sort == "manual", msg.cfg.Sort == "size"); err != nil {
+				m.err = err
+			}
+		}
+		m.refreshArticles()
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		if keys := restartKeys(old, *msg.cfg); len(keys) > 0 {
+			m.statusMsg = m.msgs.format("status.config_restart", strings.Join(keys, ", "))
+		} else {
+			m.statusMsg = m.msgs.text("status.config_reloaded")
+		}
+		if len(msg.cfg.UnknownKeys) > 0 {
+			m.statusMsg += " · " + m.msgs.format("status.config_unknown_keys", strings.Join(msg.cfg.UnknownKeys, ", "))
+		}
+	}
+	if !m.cfg.WatchConfig {
+		return m, nil
+	}
+	return m, m.watchConfig()
+}
+
+// restartKeys returns the config keys changed between old and new that
+// only take effect when shelf is restarted.
+func restartKeys(old, new config.Config) []string {
+	var keys []string
+	changed := func(key string, differs bool) {
+		if differs {
+			keys = append(keys, key)
+		}
+	}
+	changed("data_dir", old.DataDir != new.DataDir)
+	changed("endpoint", old.Endpoint != new.Endpoint)
+	changed("endpoints", !maps.Equal(old.Endpoints, new.Endpoints))
+	changed("extract_strategy", old.ExtractStrategy != new.ExtractStrategy)
+	changed("demote_h1", old.DemoteH1 != new.DemoteH1)
+	changed("pdf_command", old.PDFCommand != new.PDFCommand)
+	changed("import_rate", old.ImportRate != new.ImportRate)
+	changed("import_concurrency", old.ImportConcurrency != new.ImportConcurrency)
+	changed("log_level", old.LogLevel != new.LogLevel)
+	changed("search_history", old.SearchHistory != new.SearchHistory)
+	changed("archive_tag", old.ArchiveTag != new.ArchiveTag)
+	changed("archive_aliases", !slices.Equal(old.ArchiveAliases, new.ArchiveAliases))
+	changed("default_tags", !slices.Equal(old.DefaultTags, new.DefaultTags))
+	changed("image_dir", old.ImageDir != new.ImageDir)
+	return keys
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestConfigReload(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{DataDir: "/data", Density: "comfortable", Sort: "date", TimeFormat: "relative", WatchConfig: true}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	if err := m.applyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unchanged file keeps being watched without touching anything.
+	next, cmd := m.handleConfigPolled(configPolledMsg{})
+	m = next.(Model)
+	if cmd == nil || m.statusMsg != "" {
+		t.Fatalf("unchanged: cmd %v, status %q", cmd, m.statusMsg)
+	}
+
+	edited := cfg
+	edited.Density = "compact"
+	edited.Sort = "manual"
+	next, _ = m.handleConfigPolled(configPolledMsg{cfg: &edited, modTime: time.Now()})
+	m = next.(Model)
+	if m.density != densityCompact || !m.manualOrder || m.statusMsg != "Reloaded shelf.toml" {
+		t.Errorf("after edit: density %q, manual %v, status %q", m.density, m.manualOrder, m.statusMsg)
+	}
+
+	edited.DataDir = "/elsewhere"
+	edited.WatchConfig = false
+	next, cmd = m.handleConfigPolled(configPolledMsg{cfg: &edited, modTime: time.Now()})
+	m = next.(Model)
+	if m.statusMsg != "Reloaded shelf.toml; restart shelf for changes to data_dir" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+	if cmd != nil {
+		t.Errorf("still watching after watch_config was turned off")
+	}
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openSavedSearches shows the saved-search picker. The first row clears any
+// active saved search; the rest are the searches from the config file.
+func (m Model) openSavedSearches() (tea.Model, tea.Cmd) {
+	if len(m.savedSearches) == 0 {
+		m.statusMsg = m.msgs.text("status.no_saved_searches")
+		return m, nil
+	}
+	m.state = stateSavedSearches
+	m.savedCursor = 0
+	for i, s := range m.savedSearches {
+		if s.Name == m.activeSearch {
+			m.savedCursor = i + 1
+		}
+	}
+	m.savedScroll = clampScroll(m.savedCursor, 0, m.calcVisibleItems(), len(m.savedSearches)+1)
+	return m, nil
+}
+
+func (m Model) handleSavedSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.savedCursor > 0 {
+			m.savedCursor--
+		}
+		m.savedScroll = clampScroll(m.savedCursor, m.savedScroll, m.calcVisibleItems(), len(m.savedSearches)+1)
+	case key.Matches(msg, m.keys.Down):
+		if m.savedCursor < len(m.savedSearches) {
+			m.savedCursor++
+		}
+		m.savedScroll = clampScroll(m.savedCursor, m.savedScroll, m.calcVisibleItems(), len(m.savedSearches)+1)
+	case key.Matches(msg, m.keys.Submit):
+		if m.savedCursor == 0 {
+			m.activeSearch = ""
+			m.searchInput = m.searchInput.Clear()
+		} else {
+			s := m.savedSearches[m.savedCursor-1]
+			m.activeSearch = s.Name
+			m.searchInput = m.searchInput.SetValue(s.Query)
+		}
+		m.state = stateList
+		m.cursor = 0
+		m.scrollPos = 0
+		m.refreshArticles()
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+	}
+	return m, nil
+}
+
+func (m Model) renderSavedSearches() string {
+	contentWidth := m.width - 4
+	var sb strings.Builder
+	end := m.savedScroll + m.calcVisibleItems()
+	row := func(i int, name, query string) {
+		if i < m.savedScroll || i >= end {
+			return
+		}
+		if i > m.savedScroll {
+			sb.WriteString("\n\n")
+		}
+		name = truncateString(name, contentWidth-2, m.ellipsis)
+		if i == m.savedCursor {
+			sb.WriteString(m.styles.SelectionMarker.Render(""))
+			sb.WriteString(m.styles.SelectedTitle.Render(name))
+		} else {
+			sb.WriteString("  ")
+			sb.WriteString(m.styles.ListItemTitle.Render(name))
+		}
+		sb.WriteString("\n  ")
+		sb.WriteString(m.styles.ListItemDesc.Render(truncateString(query, contentWidth-2, m.ellipsis)))
+	}
+	row(0, m.msgs.text("view.all_articles"), m.msgs.text("view.no_filter"))
+	for i, s := range m.savedSearches {
+		row(i+1, s.Name, s.Query)
+	}
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/state"
+)
+
+// storeScannedMsg reports that the initial scan of the articles, started
+// from Init, has finished.
+type storeScannedMsg struct{ err error }
+
+// scanStore scans the articles in the background, so that a large shelf
+// shows a placeholder rather than nothing until it's read.
+func (m Model) scanStore() tea.Cmd {
+	store := m.store
+	return func() tea.Msg {
+		return storeScannedMsg{err: store.Scan()}
+	}
+}
+
+// handleStoreScanned fills in the list once the initial scan is done,
+// restoring the last session's search if that was held back for it.
+func (m Model) handleStoreScanned(msg storeScannedMsg) (tea.Model, tea.Cmd) {
+	m.scanning = false
+	if msg.err != nil {
+		m.err = msg.err
+	}
+	session := m.pendingSession
+	m.pendingSession = nil
+	return m.showArticles(session), m.measureTotalSize()
+}
+
+// totalSizeMsg reports the on-disk size of all articles, for the header.
+type totalSizeMsg struct{ size int64 }
+
+// measureTotalSize measures the articles' total size in the background:
+// it walks every article directory whose size isn't known yet, which is
+// too slow for a large shelf to do while rendering.
+func (m Model) measureTotalSize() tea.Cmd {
+	store := m.store
+	return func() tea.Msg {
+		return totalSizeMsg{size: store.TotalSize()}
+	}
+}
+
+// showArticles lists the scanned articles, with session's search and
+// filters if it's non-nil.
+func (m Model) showArticles(session *state.Session) Model {
+	if session != nil {
+		return m.applySession(session)
+	}
+	m.refreshArticles()
+	return m
+}
+
+// renderScanning renders the placeholder shown in place of the list while
+// the initial scan runs.
+func (m Model) renderScanning() string {
+	return m.spinner.View() + " " + m.styles.Muted.Render(m.msgs.format("view.scanning", m.scanTotal))
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/state"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestInitialScan(t *testing.T) {
+	dir := t.TempDir()
+	saved, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Rust Async", "Go Generics"} {
+		if err := saved.SaveContent(title, "---\ntitle: "+title+"\n---\n\nBody.\n", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := storage.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Scanned() || store.Count() != 0 || store.EntryCount() != 2 {
+		t.Fatalf("opened store: scanned %v, %d listed, %d entries", store.Scanned(), store.Count(), store.EntryCount())
+	}
+	m := Model{
+		store:          store,
+		keys:           DefaultKeyMap(),
+		styles:         DefaultStyles(),
+		searchInput:    NewSearchInput(DefaultStyles()),
+		appState:       testState(t),
+		width:          80,
+		height:         30,
+		scanning:       true,
+		scanTotal:      store.EntryCount(),
+		pendingSession: &state.Session{Query: "rust"},
+	}
+
+	// Until the scan is done there's a placeholder, not "no articles".
+	if view := m.View(); !strings.Contains(view, "Scanning 2 articles...") {
+		t.Errorf("no placeholder while scanning:\n%s", view)
+	}
+	// Keys other than quitting are ignored meanwhile, so nothing is saved
+	// or searched for that the scan's results would then replace.
+	for _, k := range []string{"a", "/", "i"} {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		if m = next.(Model); m.state != stateList || cmd != nil {
+			t.Errorf("%s while scanning: state %v, cmd %v", k, m.state, cmd != nil)
+		}
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Errorf("q while scanning doesn't quit")
+	}
+	msg := m.scanStore()()
+	next, cmd := m.Update(msg)
+	m = next.(Model)
+	if m.scanning || m.err != nil || !store.Scanned() {
+		t.Fatalf("after the scan: scanning %v, err %v, scanned %v", m.scanning, m.err, store.Scanned())
+	}
+	// The list fills in, with the session's search restored.
+	if m.searchInput.Value() != "rust" || len(m.articles) != 1 || m.articles[0].Title != "Rust Async" {
+		t.Errorf("after the scan: query %q, %d articles", m.searchInput.Value(), len(m.articles))
+	}
+	if view := m.View(); strings.Contains(view, "Scanning") || !strings.Contains(view, "Rust Async") {
+		t.Errorf("list not shown after the scan:\n%s", view)
+	}
+
+	// The total size is measured afterwards, in the background.
+	if strings.Contains(m.View(), "on disk") || cmd == nil {
+		t.Fatalf("total size shown before it's measured, or not measured")
+	}
+	next, _ = m.Update(cmd())
+	m = next.(Model)
+	if want := storage.FormatSize(store.TotalSize()) + " on disk"; !strings.Contains(m.View(), want) {
+		t.Errorf("header missing %q:\n%s", want, m.View())
+	}
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"github.com/irfansharif/shelf/pkg/state"
+)
+
+// SaveSession records the list filter in the state file so the next launch
+// can restore it. It does nothing unless restore_session is set.
+func (m Model) SaveSession() {
+	if !m.restoreSession {
+		return
+	}
+	session := &state.Session{
+		Query:        m.searchInput.Value(),
+		SavedSearch:  m.activeSearch,
+		ShowArchived: m.showArchived,
+	}
+	if *session == (state.Session{}) {
+		session = nil
+	}
+	m.appState.Session = session
+	m.saveState()
+}
+
+// applySession restores a list filter saved by SaveSession. A saved search
+// since removed from the config keeps its query but loses its name, and a
+// query that no longer matches anything falls back to the full list.
+func (m Model) applySession(s *state.Session) Model {
+	m.searchInput = m.searchInput.SetValue(s.Query)
+	m.showArchived = s.ShowArchived
+	for _, saved := range m.savedSearches {
+		if saved.Name == s.SavedSearch {
+			m.activeSearch = saved.Name
+		}
+	}
+	m.refreshArticles()
+	if len(m.articles) == 0 && s.Query != "" {
+		m.searchInput = m.searchInput.Clear()
+		m.activeSearch = ""
+		m.refreshArticles()
+		m.statusMsg = m.msgs.text("status.session_no_match")
+	}
+	return m
+}
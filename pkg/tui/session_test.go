@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/irfansharif/shelf/pkg/config"
+	"github.com/irfansharif/shelf/pkg/state"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestApplySession(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, tags string }{
+		{"Rust Async", "rust"},
+		{"Go Generics", "go"},
+		{"Old Rust", "rust, archived"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newModel := func() Model {
+		return Model{
+			store:         store,
+			searchInput:   NewSearchInput(DefaultStyles()),
+			savedSearches: []config.SavedSearch{{Name: "Rust", Query: "tag:rust"}},
+		}
+	}
+	titles := func(m Model) []string {
+		var titles []string
+		for _, a := range m.articles {
+			titles = append(titles, a.Title)
+		}
+		return titles
+	}
+
+	for _, tc := range []struct {
+		name       string
+		session    state.Session
+		wantQuery  string
+		wantSearch string
+		wantCount  int
+	}{
+		{"saved search", state.Session{Query: "tag:rust", SavedSearch: "Rust"}, "tag:rust", "Rust", 1},
+		{"with archived", state.Session{Query: "tag:rust", SavedSearch: "Rust", ShowArchived: true}, "tag:rust", "Rust", 2},
+		// A saved search no longer in the config keeps its query.
+		{"removed saved search", state.Session{Query: "tag:go", SavedSearch: "Go"}, "tag:go", "", 1},
+		// A query that now matches nothing falls back to the full list.
+		{"no matches", state.Session{Query: "tag:python", SavedSearch: "Rust"}, "", "", 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newModel().applySession(&tc.session)
+			if got := m.searchInput.Value(); got != tc.wantQuery {
+				t.Errorf("query = %q, want %q", got, tc.wantQuery)
+			}
+			if m.activeSearch != tc.wantSearch {
+				t.Errorf("active search = %q, want %q", m.activeSearch, tc.wantSearch)
+			}
+			if len(m.articles) != tc.wantCount {
+				t.Errorf("articles = %v, want %d", titles(m), tc.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// maxStatusLines is how many lines a long status or error message wraps to
+// above the footer. An error cut short there ends by saying e shows it in
+// full.
+const maxStatusLines = 3
+
+// statusText returns the lines of the status or error message shown above
+// the footer, wrapped to the screen, and whether it was cut short at
+// maxStatusLines.
+func (m Model) statusText() (lines []string, cut bool) {
+	usable := m.width - 4
+	var text string
+	switch {
+	case m.state == stateConfirmDelete:
+		title := m.pendingDeleteTitle
+		full := m.msgs.format("status.confirm_trash", title)
+		if runewidth.StringWidth(full) > usable && usable > 20 {
+			overhead := runewidth.StringWidth(m.msgs.format("status.confirm_trash", ""))
+			maxTitle := usable - overhead
+			if maxTitle > runewidth.StringWidth(m.ellipsis) {
+				title = truncateString(title, maxTitle, m.ellipsis)
+				full = m.msgs.format("status.confirm_trash", title)
+			} else {
+				// Title won't fit; drop it entirely.
+				full = m.msgs.text("status.confirm_delete_untitled")
+			}
+		}
+		return []string{full}, false
+	case m.state == stateConfirmQuit:
+		return []string{m.msgs.text("status.confirm_quit")}, false
+	case m.state == stateConfirmDeleteTag:
+		n := len(m.store.ListByTag(m.deleting))
+		return []string{truncateString(m.msgs.format("status.confirm_delete_tag", m.deleting, n), usable, m.ellipsis)}, false
+	case m.state == stateConfirmResetTag:
+		return []string{truncateString(m.msgs.format("status.confirm_reset_tag", m.resetCount(m.resetting), m.resetting), usable, m.ellipsis)}, false
+	case m.state == stateConfirmResume:
+		return []string{truncateString(m.msgs.format("status.confirm_resume", len(m.appState.ImportQueue)), usable, m.ellipsis)}, false
+	case m.err != nil:
+		text = m.msgs.errorText(m.err)
+	case m.statusMsg != "":
+		text = m.statusMsg
+	default:
+		return nil, false
+	}
+	if usable <= 20 {
+		return []string{text}, false
+	}
+
+	lines = strings.Split(lipgloss.NewStyle().Width(usable).Render(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	if len(lines) <= maxStatusLines {
+		return lines, false
+	}
+	lines = lines[:maxStatusLines]
+	last := lines[maxStatusLines-1] + m.ellipsis
+	if m.err != nil {
+		hint := " " + m.msgs.format("status.error_more", m.keys.ShowError.Help().Key)
+		last = truncateString(last, usable-runewidth.StringWidth(hint), m.ellipsis) + hint
+	}
+	lines[maxStatusLines-1] = truncateString(last, usable, m.ellipsis)
+	return lines, true
+}
+
+// statusHeight returns how many lines the status takes above the footer,
+// at least the one left blank when there's none.
+func (m Model) statusHeight() int {
+	lines, _ := m.statusText()
+	return max(1, len(lines))
+}
+
+// renderStatus renders the status or error message above the footer, or
+// "" if there's none.
+func (m Model) renderStatus() string {
+	lines, _ := m.statusText()
+	if len(lines) == 0 {
+		return ""
+	}
+	style := m.styles.Error
+	if m.err == nil && m.state != stateConfirmDelete && m.state != stateConfirmQuit && m.state != stateConfirmDeleteTag && m.state != stateConfirmResetTag && m.state != stateConfirmResume {
+		style = m.styles.Muted
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
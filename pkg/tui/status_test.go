@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestLongStatus(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		ellipsis:    "...",
+		width:       60,
+		height:      30,
+	}
+	height := lipgloss.Height(m.View()) // with no status
+	detail := "the endpoint said: " + strings.Repeat("upstream timed out reading the page; ", 8) + "giving up"
+	m.err = &fetchError{err: errors.New(detail)}
+
+	// A long error wraps over the status lines, and ends by offering the
+	// rest.
+	lines, cut := m.statusText()
+	if len(lines) != maxStatusLines || !cut {
+		t.Fatalf("status is %d lines, cut %v; want %d, cut", len(lines), cut, maxStatusLines)
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "... (e: full error)") {
+		t.Errorf("last status line %q doesn't offer the full error", last)
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > m.width-4 {
+			t.Errorf("status line %q is %d columns, wider than %d", line, w, m.width-4)
+		}
+	}
+	if h := lipgloss.Height(m.View()); h != height {
+		t.Errorf("view is %d lines with a long error, want %d", h, height)
+	}
+
+	// e shows all of it.
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = next.(Model)
+	if m.state != statePreview || m.err != nil || !strings.Contains(m.previewText.body, "giving up") {
+		t.Fatalf("after e: state %v, err %v, preview %q", m.state, m.err, m.previewText.body)
+	}
+
+	// A short one takes a single line, and e isn't taken from the list.
+	m.state = stateList
+	m.err = errors.New("no source URL")
+	if lines, cut := m.statusText(); len(lines) != 1 || cut {
+		t.Errorf("short error: %d lines, cut %v", len(lines), cut)
+	}
+	if h := lipgloss.Height(m.View()); h != height {
+		t.Errorf("view is %d lines with a short error, want %d", h, height)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m = next.(Model); m.state != stateList || m.err != nil {
+		t.Errorf("e after a short error: state %v, err %v", m.state, m.err)
+	}
+}
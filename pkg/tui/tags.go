@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+const (
+	tagSidebarWidth = 24 // columns for the tag names and counts
+	tagSidebarGap   = 3  // " │ " between the sidebar and the list
+)
+
+// toggleTagSidebar shows the tag sidebar and focuses it, or hides it if it
+// is already shown. The first row clears the tag filter; the rest are the
+// store's tags, most used first.
+func (m Model) toggleTagSidebar() (tea.Model, tea.Cmd) {
+	if m.showTags {
+		m.showTags = false
+		m.state = stateList
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		return m, nil
+	}
+	m.tags = m.store.Tags()
+	if len(m.tags) == 0 {
+		m.statusMsg = m.msgs.text("status.no_tags")
+		return m, nil
+	}
+	m.showTags = true
+	return m.focusTagSidebar()
+}
+
+// focusTagSidebar moves the keyboard focus to the tag sidebar, with the
+// cursor on the tag currently filtered by, if any.
+func (m Model) focusTagSidebar() (tea.Model, tea.Cmd) {
+	m.state = stateTags
+	m.tagCursor = 0
+	for i, t := range m.tags {
+		if m.isTagFilter(t.Tag) {
+			m.tagCursor = i + 1
+		}
+	}
+	m.tagScroll = clampScroll(m.tagCursor, m.tagScroll, m.listHeight(), len(m.tags)+1)
+	return m, nil
+}
+
+func (m Model) handleTagKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.tagCursor > 0 {
+			m.tagCursor--
+		}
+		m.tagScroll = clampScroll(m.tagCursor, m.tagScroll, m.listHeight(), len(m.tags)+1)
+	case key.Matches(msg, m.keys.Down):
+		if m.tagCursor < len(m.tags) {
+			m.tagCursor++
+		}
+		m.tagScroll = clampScroll(m.tagCursor, m.tagScroll, m.listHeight(), len(m.tags)+1)
+	case key.Matches(msg, m.keys.Submit):
+		m.activeSearch = ""
+		if m.tagCursor == 0 {
+			m.searchInput = m.searchInput.Clear()
+		} else {
+			m.searchInput = m.searchInput.SetValue(storage.TagQuery(m.tags[m.tagCursor-1].Tag))
+		}
+		m.state = stateList
+		m.cursor = 0
+		m.scrollPos = 0
+		m.refreshArticles()
+	case key.Matches(msg, m.keys.ResetProgress) && m.tagCursor > 0:
+		tag := m.tags[m.tagCursor-1].Tag
+		if m.resetCount(tag) == 0 {
+			m.statusMsg = m.msgs.format("status.tag_progress_reset", 0, tag)
+			return m, nil
+		}
+		m.state = stateConfirmResetTag
+		m.resetting = tag
+	case key.Matches(msg, m.keys.RenameTag) && m.tagCursor > 0:
+		m.state = stateRenameTag
+		m.renaming = m.tags[m.tagCursor-1].Tag
+		m.tagInput = m.tagInput.SetValue(m.renaming)
+		var cmd tea.Cmd
+		m.tagInput, cmd = m.tagInput.Focus()
+		return m, cmd
+	case key.Matches(msg, m.keys.Delete) && m.tagCursor > 0:
+		tag := m.tags[m.tagCursor-1].Tag
+		if m.store.IsStatusTag(tag) {
+			m.err = errors.New(m.msgs.format("status.status_tag", tag))
+			return m, nil
+		}
+		m.state = stateConfirmDeleteTag
+		m.deleting = tag
+	case key.Matches(msg, m.keys.Tags):
+		return m.toggleTagSidebar()
+	case key.Matches(msg, m.keys.FocusTags), key.Matches(msg, m.keys.Cancel):
+		m.state = stateList
+	case key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+	}
+	return m, nil
+}
+
+// handleRenameTagKeys handles the prompt for the selected tag's new name,
+// entered from the sidebar with r. Enter renames it on every article, and
+// the sidebar and any filter by it follow.
+func (m Model) handleRenameTagKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		if m.tagInput.Value() != "" {
+			m.tagInput = m.tagInput.Reset()
+			return m, nil
+		}
+		m.state = stateTags
+		return m, nil
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.state = stateTags
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		name := strings.TrimSpace(m.tagInput.Value())
+		m.tagInput = m.tagInput.Blur()
+		m.state = stateTags
+		if name == "" || name == m.renaming {
+			return m, nil
+		}
+		filtered := m.isTagFilter(m.renaming)
+		n, err := m.store.RenameTag(m.renaming, name)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.statusMsg = m.msgs.format("status.tag_renamed", m.renaming, name, n)
+		if filtered {
+			m.searchInput = m.searchInput.SetValue(storage.TagQuery(name))
+		}
+		m.refreshArticles()
+		for i, t := range m.tags {
+			if strings.EqualFold(t.Tag, name) {
+				m.tagCursor = i + 1
+			}
+		}
+		m.tagScroll = clampScroll(m.tagCursor, m.tagScroll, m.listHeight(), len(m.tags)+1)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// handleConfirmDeleteTagKeys answers the prompt to remove the selected tag
+// from every article carrying it, entered from the sidebar with d.
+func (m Model) handleConfirmDeleteTagKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.state = stateTags
+		filtered := m.isTagFilter(m.deleting)
+		n, err := m.store.DeleteTag(m.deleting, false)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.statusMsg = m.msgs.format("status.tag_deleted", m.deleting, n)
+		if filtered {
+			m.activeSearch = ""
+			m.searchInput = m.searchInput.Clear()
+		}
+		m.refreshArticles()
+		m.tagScroll = clampScroll(m.tagCursor, m.tagScroll, m.listHeight(), len(m.tags)+1)
+	case "n", "N", "esc", "ctrl+c":
+		m.state = stateTags
+	}
+	return m, nil
+}
+
+// resetCount returns how many articles tagged tag have reading progress
+// for ResetTagProgress to clear.
+func (m Model) resetCount(tag string) int {
+	n := 0
+	for _, a := range m.store.ListByTag(tag) {
+		if a.Progress > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// handleConfirmResetTagKeys answers the prompt to reset the reading
+// progress of every article with a tag, asked from the sidebar with 0.
+func (m Model) handleConfirmResetTagKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.state = stateTags
+		n, err := m.store.ResetTagProgress(m.resetting)
+		if err != nil {
+			m.err = err
+		}
+		m.statusMsg = m.msgs.format("status.tag_progress_reset", n, m.resetting)
+		m.refreshArticles()
+	case "n", "N", "esc", "ctrl+c":
+		m.state = stateTags
+	}
+	return m, nil
+}
+
+// isTagFilter reports whether the list is filtered by exactly tag, as
+// selecting it in the sidebar does.
+func (m Model) isTagFilter(tag string) bool {
+	return strings.EqualFold(strings.TrimSpace(m.searchInput.Value()), storage.TagQuery(tag))
+}
+
+// listWidth returns the columns available to the article list, which
+// shrinks to make room for the tag sidebar when it's shown.
+func (m Model) listWidth() int {
+	width := m.width - 4 // account for App padding
+	if m.showTags {
+		width -= tagSidebarWidth + tagSidebarGap
+	}
+	return width
+}
+
+// renderTagSidebar renders height rows of the tag sidebar, each padded to
+// tagSidebarWidth, with a rule down its right-hand side.
+func (m Model) renderTagSidebar(height int) string {
+	focused := m.state == stateTags || m.state == stateConfirmDeleteTag || m.state == stateConfirmResetTag
+	rule := m.styles.Muted.Render(" │ ")
+	rows := make([]string, 0, height)
+	row := func(i int, name string, count int, active bool) {
+		if i < m.tagScroll || len(rows) >= height {
+			return
+		}
+		prefix := "  "
+		nameStyle := m.styles.Tag
+		if active {
+			nameStyle = m.styles.SelectedTitle
+		}
+		if focused && i == m.tagCursor {
+			prefix = m.styles.SelectionMarker.Render("")
+			nameStyle = m.styles.SelectedTitle
+		}
+		countStr := fmt.Sprint(count)
+		nameWidth := tagSidebarWidth - lipgloss.Width(prefix) - len(countStr) - 1
+		name = truncateString(name, nameWidth, m.ellipsis)
+		pad := strings.Repeat(" ", max(1, nameWidth-lipgloss.Width(name)+1))
+		rows = append(rows, prefix+nameStyle.Render(name)+pad+m.styles.Muted.Render(countStr)+rule)
+	}
+	row(0, m.msgs.text("view.all_tags"), m.store.Count(), m.searchInput.Value() == "")
+	for i, t := range m.tags {
+		row(i+1, t.Tag, t.Count, m.isTagFilter(t.Tag))
+	}
+	blank := strings.Repeat(" ", tagSidebarWidth) + rule
+	for len(rows) < height {
+		rows = append(rows, blank)
+	}
+	return strings.Join(rows, "\n")
+}
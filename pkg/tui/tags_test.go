@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestTagSidebar(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, tags string }{
+		{"Rust Async", "rust, async"},
+		{"Rust Lifetimes", "rust"},
+		{"Go Generics", "go"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "tab":
+				msg = tea.KeyMsg{Type: tea.KeyTab}
+			}
+			next, _ := m.Update(msg)
+			m = next.(Model)
+		}
+	}
+	checkWidth := func() {
+		t.Helper()
+		for _, line := range strings.Split(m.View(), "\n") {
+			if w := lipgloss.Width(line); w > m.width {
+				t.Errorf("line is %d wide, more than the terminal's %d: %q", w, m.width, line)
+			}
+		}
+	}
+
+	press("t")
+	if m.state != stateTags || !m.showTags {
+		t.Fatalf("t: state %d, sidebar shown %t; want the sidebar focused", m.state, m.showTags)
+	}
+	if got := m.listWidth(); got != m.width-4-tagSidebarWidth-tagSidebarGap {
+		t.Errorf("list width with the sidebar = %d", got)
+	}
+	checkWidth()
+
+	// Tags are listed most used first; selecting one filters by it.
+	press("j", "enter")
+	if m.state != stateList || m.searchInput.Value() != "tag:rust" || len(m.articles) != 2 {
+		t.Errorf("selecting rust: state %d, query %q, %d articles", m.state, m.searchInput.Value(), len(m.articles))
+	}
+	if !strings.Contains(m.View(), "Rust Lifetimes") {
+		t.Errorf("filtered list missing an article:\n%s", m.View())
+	}
+	checkWidth()
+
+	// Tab returns to the sidebar at the active tag; "All tags" clears it.
+	press("tab")
+	if m.state != stateTags || m.tagCursor != 1 {
+		t.Errorf("tab: state %d, tag cursor %d; want the sidebar on rust", m.state, m.tagCursor)
+	}
+	press("k", "enter")
+	if m.searchInput.Value() != "" || len(m.articles) != 3 {
+		t.Errorf("all tags: query %q, %d articles", m.searchInput.Value(), len(m.articles))
+	}
+
+	// t hides the sidebar and gives the list its width back.
+	press("t")
+	if m.showTags || m.state != stateList || m.listWidth() != m.width-4 {
+		t.Errorf("hiding: sidebar shown %t, state %d, list width %d", m.showTags, m.state, m.listWidth())
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, tags string }{
+		{"Rust Async", "rust, async"},
+		{"Rust Lifetimes", "systems, rust"},
+		{"Go Generics", "go"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		tagInput:    NewTagInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			case "tab":
+				msg = tea.KeyMsg{Type: tea.KeyTab}
+			case "ctrl+c":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlC}
+			}
+			next, _ := m.Update(msg)
+			m = next.(Model)
+		}
+	}
+	tagsOf := func(title string) string {
+		t.Helper()
+		for _, a := range store.List() {
+			if a.Title == title {
+				return strings.Join(a.Tags, ", ")
+			}
+		}
+		t.Fatalf("%q not listed", title)
+		return ""
+	}
+
+	// Filter by rust, then go back to it in the sidebar.
+	press("t", "j", "enter", "tab")
+	if m.state != stateTags || m.searchInput.Value() != "tag:rust" {
+		t.Fatalf("state %d, query %q; want the sidebar, filtered by rust", m.state, m.searchInput.Value())
+	}
+
+	// r asks for the new name, starting from the old one; esc backs out.
+	press("r")
+	if m.state != stateRenameTag || m.tagInput.Value() != "rust" {
+		t.Fatalf("r: state %d, input %q", m.state, m.tagInput.Value())
+	}
+	press("esc")
+	if m.state != stateTags || tagsOf("Rust Async") != "rust, async" {
+		t.Fatalf("esc: state %d, tags %q", m.state, tagsOf("Rust Async"))
+	}
+
+	// Renaming to a tag already in use merges the two.
+	press("r", "ctrl+c", "systems", "enter")
+	if m.state != stateTags || m.err != nil || m.statusMsg != `Renamed "rust" to "systems" on 2 article(s)` {
+		t.Fatalf("rename: state %d, err %v, status %q", m.state, m.err, m.statusMsg)
+	}
+	if got := tagsOf("Rust Async"); got != "systems, async" {
+		t.Errorf("Rust Async tagged %q", got)
+	}
+	if got := tagsOf("Rust Lifetimes"); got != "systems" {
+		t.Errorf("Rust Lifetimes tagged %q, want a single systems", got)
+	}
+
+	// The filter and the sidebar follow the rename.
+	if m.searchInput.Value() != "tag:systems" || len(m.articles) != 2 {
+		t.Errorf("after rename: query %q, %d articles", m.searchInput.Value(), len(m.articles))
+	}
+	if len(m.tags) != 3 || m.tagCursor == 0 || m.tags[m.tagCursor-1].Tag != "systems" {
+		t.Errorf("after rename: tags %v, cursor %d", m.tags, m.tagCursor)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct{ title, tags string }{
+		{"Rust Async", "todo, rust, pinned"},
+		{"Rust Lifetimes", "rust, todo"},
+		{"Go Generics", "go"},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "tab":
+				msg = tea.KeyMsg{Type: tea.KeyTab}
+			}
+			next, _ := m.Update(msg)
+			m = next.(Model)
+		}
+	}
+	selectTag := func(tag string) {
+		t.Helper()
+		for i, tc := range m.tags {
+			if tc.Tag == tag {
+				m.tagCursor = i + 1
+				return
+			}
+		}
+		t.Fatalf("%q not in the sidebar: %v", tag, m.tags)
+	}
+
+	// Filter by todo, then go back to it in the sidebar.
+	press("t")
+	selectTag("todo")
+	press("enter", "tab")
+
+	// d says how many articles it'd change, and n leaves them be.
+	press("d")
+	if lines, _ := m.statusText(); m.state != stateConfirmDeleteTag || len(lines) != 1 || lines[0] != `Remove tag "todo" from 2 article(s)? [y/n]` {
+		t.Fatalf("d: state %d, status %q", m.state, lines)
+	}
+	press("n")
+	if m.state != stateTags || len(store.ListByTag("todo")) != 2 {
+		t.Fatalf("n: state %d, %d still tagged", m.state, len(store.ListByTag("todo")))
+	}
+
+	// y removes it everywhere, and the filter by it goes too.
+	press("d", "y")
+	if m.state != stateTags || m.err != nil || m.statusMsg != `Removed tag "todo" from 2 article(s)` {
+		t.Fatalf("y: state %d, err %v, status %q", m.state, m.err, m.statusMsg)
+	}
+	if n := len(store.ListByTag("todo")); n != 0 {
+		t.Errorf("%d articles still tagged todo", n)
+	}
+	if m.searchInput.Value() != "" || len(m.articles) != 3 {
+		t.Errorf("after removing the filtered tag: query %q, %d articles", m.searchInput.Value(), len(m.articles))
+	}
+	for _, tc := range m.tags {
+		if tc.Tag == "todo" {
+			t.Errorf("todo still in the sidebar: %v", m.tags)
+		}
+	}
+
+	// The pin tag isn't removed from the sidebar.
+	selectTag("pinned")
+	press("d")
+	if m.state != stateTags || m.err == nil || !store.List()[0].IsPinned() {
+		t.Errorf("d on pinned: state %d, err %v", m.state, m.err)
+	}
+}
+
+func TestResetTagProgress(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []struct {
+		title, tags string
+		progress    int
+	}{
+		{"Rust Async", "rust", 12},
+		{"Rust Lifetimes", "rust", 0},
+		{"Go Generics", "go", 7},
+	} {
+		content := fmt.Sprintf("---\ntitle: %s\ntags: %s\n---\n\nBody.\n", a.title, a.tags)
+		if err := store.SaveContent(a.title, content, nil); err != nil {
+			t.Fatal(err)
+		}
+		if a.progress > 0 {
+			if err := store.UpdateProgress(store.ArticlePath(a.title), a.progress); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+			m = next.(Model)
+		}
+	}
+	progress := func(title string) int {
+		t.Helper()
+		for _, a := range store.List() {
+			if a.Title == title {
+				return a.Progress
+			}
+		}
+		t.Fatalf("%q not listed", title)
+		return 0
+	}
+
+	// 0 on rust asks first, saying how many articles it would reset; n
+	// leaves them be.
+	press("t", "j", "0")
+	if m.state != stateConfirmResetTag {
+		t.Fatalf("0: state %d, want the confirmation", m.state)
+	}
+	if want := `Reset reading progress of 1 article(s) tagged "rust"?`; !strings.Contains(m.View(), want) {
+		t.Errorf("prompt missing %q:\n%s", want, m.View())
+	}
+	press("n")
+	if m.state != stateTags || progress("Rust Async") != 12 {
+		t.Fatalf("n: state %d, progress %d", m.state, progress("Rust Async"))
+	}
+
+	press("0", "y")
+	if m.state != stateTags || m.statusMsg != `Reset progress of 1 article(s) tagged "rust"` {
+		t.Errorf("y: state %d, status %q", m.state, m.statusMsg)
+	}
+	if progress("Rust Async") != 0 || progress("Go Generics") != 7 {
+		t.Errorf("after reset: Rust Async at %d, Go Generics at %d", progress("Rust Async"), progress("Go Generics"))
+	}
+
+	// With nothing to reset, there's nothing to ask.
+	press("0")
+	if m.state != stateTags {
+		t.Errorf("0 with nothing to reset: state %d", m.state)
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/safari"
+)
+
+// Limits for looking up titles of untitled tabs before opening the import
+// buffer (see import_fetch_titles).
+const (
+	titleFetchConcurrency = 8
+	titleFetchTimeout     = 3 * time.Second
+	titleFetchMaxBytes    = 64 << 10 // <title> is near the top of <head>
+)
+
+var titleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// titleFetchedMsg carries the title looked up for one untitled tab, or ""
+// if the lookup failed.
+type titleFetchedMsg struct {
+	source string
+	index  int // into the source's tabs
+	title  string
+	gen    uint64
+}
+
+// resolveTitles looks up the titles of the untitled, unsaved tabs in
+// tabsBySource before the import buffer opens, showing progress as they
+// come in. The buffer opens once they're all in, or sooner if the user
+// skips the rest. window says the tabs are the front window's. With nothing
+// to look up, the buffer opens straight away.
+func (m Model) resolveTitles(tabsBySource map[string][]safari.Tab, window bool) (tea.Model, tea.Cmd) {
+	m.titleTabs = tabsBySource
+	m.titleWindow = window
+	m.titleGen++
+	m.titlesTotal = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.titleCancel = cancel
+	client := &http.Client{Timeout: titleFetchTimeout}
+	sem := make(chan struct{}, titleFetchConcurrency)
+	savedURLs := m.savedURLs()
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for source, tabs := range tabsBySource {
+		for i, t := range tabs {
+			if t.Title != "" || savedURLs[t.URL] {
+				continue
+			}
+			m.titlesTotal++
+			cmds = append(cmds, fetchTitleCmd(ctx, client, sem, source, i, t.URL, m.titleGen))
+		}
+	}
+	m.titlesPending = m.titlesTotal
+	if m.titlesPending == 0 {
+		return m.openTitledImportBuffer()
+	}
+	m.state = stateResolvingTitles
+	return m, tea.Batch(cmds...)
+}
+
+// fetchTitleCmd returns a command that looks up the title of the tab at
+// url, waiting its turn on sem.
+func fetchTitleCmd(ctx context.Context, client *http.Client, sem chan struct{}, source string, index int, url string, gen uint64) tea.Cmd {
+	return func() tea.Msg {
+		msg := titleFetchedMsg{source: source, index: index, gen: gen}
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			return msg
+		}
+		msg.title, _ = fetchTitle(ctx, client, url)
+		return msg
+	}
+}
+
+// handleTitleFetched fills in a looked-up title, opening the import buffer
+// once the last is in.
+func (m Model) handleTitleFetched(msg titleFetchedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.titleGen || m.state != stateResolvingTitles {
+		return m, nil
+	}
+	if msg.title != "" {
+		m.titleTabs[msg.source][msg.index].Title = msg.title
+	}
+	if m.titlesPending--; m.titlesPending > 0 {
+		return m, nil
+	}
+	return m.openTitledImportBuffer()
+}
+
+func (m Model) handleResolvingTitlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Submit), key.Matches(msg, m.keys.Cancel):
+		// Go on with the titles found so far.
+		return m.openTitledImportBuffer()
+	case key.Matches(msg, m.keys.Quit), msg.String() == "ctrl+c":
+		m.stopTitleFetches()
+		m.titleTabs = nil
+		m.state = stateList
+		m.suppressQuit = true
+	}
+	return m, nil
+}
+
+// openTitledImportBuffer stops any title lookups still running and opens
+// the import buffer with the titles found.
+func (m Model) openTitledImportBuffer() (tea.Model, tea.Cmd) {
+	m.stopTitleFetches()
+	tabs := m.titleTabs
+	m.titleTabs = nil
+	m.state = stateGatheringTabs
+	if m.titleWindow {
+		return m.openImportBuffer(formatWindowImportFile(m.msgs, tabs["window"], m.savedURLs()))
+	}
+	return m.openImportBuffer(formatImportFile(m.msgs, tabs, m.savedURLs(), m.importWarnings, m.importSort, m.importSources))
+}
+
+// stopTitleFetches cancels the title lookups in flight and discards any
+// results still to arrive.
+func (m *Model) stopTitleFetches() {
+	if m.titleCancel != nil {
+		m.titleCancel()
+		m.titleCancel = nil
+	}
+	m.titleGen++
+}
+
+// fetchTitle returns the contents of the <title> element of the page at url.
+func fetchTitle(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, titleFetchMaxBytes))
+	if err != nil {
+		return "", err
+	}
+	m := titleRe.FindSubmatch(body)
+	if m == nil {
+		return "", fmt.Errorf("%s: no <title>", url)
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if title == "" {
+		return "", fmt.Errorf("%s: empty <title>", url)
+	}
+	return title, nil
+}
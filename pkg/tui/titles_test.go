@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestResolveTitles(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/post":
+			w.Write([]byte("<html><head><TITLE data-x=1>\n  Rust &amp; Async\n</TITLE></head></html>"))
+		case "/untitled":
+			w.Write([]byte("<html><head></head></html>"))
+		case "/slow":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			w.Write([]byte("<title>Too Late</title>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := "---\ntitle: Saved\nsource: " + srv.URL + "/saved\n---\n\nBody.\n"
+	if err := store.SaveContent("Saved", saved, nil); err != nil {
+		t.Fatal(err)
+	}
+	var model tea.Model = Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	tabs := map[string][]safari.Tab{
+		"local": {
+			{URL: srv.URL + "/post"},
+			{URL: srv.URL + "/post", Title: "Kept"},
+			{URL: srv.URL + "/untitled"},
+			{URL: srv.URL + "/slow"},
+		},
+		"readinglist": {
+			{URL: srv.URL + "/missing"},
+			{URL: srv.URL + "/saved"}, // not worth looking up
+		},
+	}
+	model, cmd := model.(Model).resolveTitles(tabs, false)
+	if m := model.(Model); m.state != stateResolvingTitles || m.titlesTotal != 4 {
+		t.Fatalf("state %v, %d titles to look up; want %v, 4", m.state, m.titlesTotal, stateResolvingTitles)
+	}
+
+	// Run the lookups, feeding back all but the one that hangs.
+	results := make(chan titleFetchedMsg)
+	for _, c := range cmd().(tea.BatchMsg) {
+		go func() {
+			if msg, ok := c().(titleFetchedMsg); ok {
+				results <- msg
+			}
+		}()
+	}
+	for range 3 {
+		model, _ = model.Update(<-results)
+	}
+	if view := model.View(); !strings.Contains(view, "3/4") || !strings.Contains(view, "[esc] skip the rest") {
+		t.Errorf("view doesn't show progress:\n%s", view)
+	}
+
+	// Skipping opens the buffer with the titles found so far.
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m := model.(Model); m.state != stateGatheringTabs {
+		t.Fatalf("state after skipping = %v", m.state)
+	}
+	buffers, _ := filepath.Glob(filepath.Join(tmp, "shelf-import-*.txt"))
+	if len(buffers) != 1 {
+		t.Fatalf("import buffers: %v", buffers)
+	}
+	content, err := os.ReadFile(buffers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Rust & Async", "Kept", srv.URL + "/slow", srv.URL + "/missing"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("buffer missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "/saved") {
+		t.Errorf("buffer lists a saved URL:\n%s", content)
+	}
+
+	// The skipped lookup is cancelled, and its result ignored.
+	model, _ = model.Update(<-results)
+	if m := model.(Model); m.state != stateGatheringTabs || m.titleTabs != nil {
+		t.Errorf("late title changed the model: state %v", m.state)
+	}
+
+	if _, err := fetchTitle(context.Background(), srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Error("expected an error for a 404")
+	}
+}
@@ -1,25 +1,34 @@
 package tui
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	neturl "net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/mattn/go-runewidth"
 
+	"github.com/irfansharif/shelf/pkg/config"
 	"github.com/irfansharif/shelf/pkg/extractor"
 	"github.com/irfansharif/shelf/pkg/safari"
+	"github.com/irfansharif/shelf/pkg/state"
 	"github.com/irfansharif/shelf/pkg/storage"
+	"github.com/irfansharif/shelf/pkg/termimg"
 )
 
 // State represents the current UI state.
@@ -33,53 +42,218 @@ const (
 	stateConfirmOverwrite
 	stateConfirmDelete
 	stateGatheringTabs
+	stateResolvingTitles
 	stateImporting
 	stateSafariWaiting
 	stateHelp
+	stateImportFailures
+	stateSavedSearches
+	stateImages
+	statePreview
+	statePermissions
+	stateImportPreview
+	stateTags
+	stateConfirmQuit
+	stateNewNote
+	statePeek
+	stateExporting
+	stateArchiveNote
+	stateRenameTag
+	stateConfirmDeleteTag
+	stateConfirmResume
+	stateConfirmResetTag
 )
 
 // Model is the main TUI model.
 type Model struct {
-	state          State
-	store          *storage.Store
-	extract        *extractor.Extractor
-	keys           KeyMap
-	styles         Styles
-	width          int
-	height         int
+	state        State
+	store        *storage.Store
+	extract      *extractor.Extractor
+	extractCache *extractCache // extractions fetched this session, by URL
+	keys         KeyMap
+	styles       Styles
+	width        int
+	height       int
 	safariURL    string         // URL being fetched via Safari (for process endpoint)
 	safariWindow *safari.Window // tracked Safari window for the current fetch
+	logger       *slog.Logger
+	appState     *state.State // persisted across runs (activity, paused imports)
+
+	// Articles saved between newSince (the previous visit) and visitStart
+	// are marked as new until opened; seenNew holds those opened since.
+	newSince   time.Time
+	visitStart time.Time
+	seenNew    map[string]bool
+
+	checkEndpoint  bool  // probe the endpoint at startup
+	restoreSession bool  // save the list filter on exit and restore it at startup
+	endpointErr    error // result of the startup probe, shown in the header
 
 	// List state
 	articles     []storage.ArticleMeta
 	cursor       int
 	scrollPos    int
 	showArchived bool
+	density      density
+	timeFormat   timeFormat
+	ellipsis     string   // ends truncated text; see truncateString
+	showSection  bool     // show the source's site section beside its domain
+	domainMarks  bool     // domain_marks: a dot colored by domain beside each article
+	matchCounts  bool     // match_counts: results by the field they matched
+	manualOrder  bool     // sort = "manual": K and J reorder articles
+	msgs         messages // user-facing strings; nil is English
+
+	// Initial scan, run from Init when the store comes unscanned (see
+	// storage.Open)
+	scanning       bool
+	scanTotal      int            // entries in articles/, for the placeholder
+	totalSize      int64          // on-disk size of all articles; see measureTotalSize
+	pendingSession *state.Session // restored once the scan is done
+
+	// Tag sidebar
+	showTags  bool
+	tags      []storage.TagCount
+	tagCursor int // 0 is "All tags", i+1 is tags[i]
+	tagScroll int
+	renaming  string // tag being renamed, in stateRenameTag
+	deleting  string // tag to remove, in stateConfirmDeleteTag
+	resetting string // tag whose progress to reset, in stateConfirmResetTag
 
 	// Components
-	urlInput    URLInputModel
-	searchInput SearchInputModel
-	spinner     spinner.Model
+	urlInput      URLInputModel
+	titleInput    URLInputModel // title of a new note
+	noteInput     URLInputModel // why an article is being archived
+	tagInput      URLInputModel // new name for a tag
+	searchInput   SearchInputModel
+	searchHistory *searchHistory
+	spinner       spinner.Model
+
+	// Saved searches
+	savedSearches []config.SavedSearch
+	savedCursor   int // 0 is "All articles", i+1 is savedSearches[i]
+	savedScroll   int
+	activeSearch  string // name of the applied saved search, if any
+
+	// User-defined keys that run actions on the selected article
+	macros []config.Macro
+
+	// Settings in effect, and the config file's modification time when
+	// they were read, to notice edits to it (see watch_config)
+	cfg           config.Config
+	configModTime time.Time
+
+	// Export of the listed articles
+	exportRoot  string   // folder exports are written under
+	exportPaths []string // articles being exported, in list order
+	exportDir   string   // this export's folder, inside exportRoot
+	exportDone  int
+	exportGen   uint64 // incremented per export; stale copies are ignored
+
+	// Quick peek at the selected article
+	peeked    peek
+	peekCache map[string]peek // by FilePath
+	peekOrder []string        // peekCache's keys, oldest first
+
+	// Image list for the selected article
+	imageArticle  storage.ArticleMeta
+	images        []storage.ImageRef
+	imageCursor   int
+	imageScroll   int
+	imageProtocol termimg.Protocol // inline graphics support, detected at startup
+	imagePreview  string           // rendered preview of the selected image, if shown
+
+	// Open behavior and the in-app preview
+	openAction    openAction // what Enter does
+	preview       viewport.Model
+	previewTitle  string
+	previewNote   string       // why the previewed article was archived, if known
+	previewText   *previewText // the body, wrapped as far as it's been scrolled
+	previewReturn State        // screen to go back to
+
+	// SHELF_DEBUG=1 enables keys.Probe, which shows the raw response for
+	// a URL.
+	debugURL string // last URL that failed to extract
 
 	// Overwrite confirmation
 	pendingResult  *extractor.ExtractResult // post-fetch slug collision
-	overwritePath  string                   // pre-fetch URL match: file path to delete
+	overwritePath  string                   // pre-fetch URL match: file path to re-fetch
 	overwriteTitle string                   // pre-fetch URL match: title for display
+	overwriteInfo  overwriteDetails         // the saved article, shown at the prompt
+	overwrite      string                   // "ask", "always" or "never", from the config
+	overwriteMemo  string                   // "always" or "never" if chosen at the prompt, until the batch ends
+
+	// Reason for archiving (archive_note)
+	askArchiveNote bool
+	archiving      storage.ArticleMeta // article whose reason is being asked
 
 	// Delete confirmation
+	deleteStyle        string // "confirm" or "dd"
 	pendingDeletePath  string // file path of article pending deletion
 	pendingDeleteTitle string // title for display in confirmation prompt
 
+	confirmQuit bool // ask before q quits from the list
+	ctrlCQuits  bool // ctrl_c = "quit": ctrl+c quits from any screen
+
+	// Multi-key chords such as dd: the first key is held here until the
+	// next keypress or keyChordTimeout.
+	pendingKey   string
+	pendingKeyAt time.Time
+
+	// Changes u undoes and ctrl+r redoes, oldest first
+	undoStack []undoOp
+	redoStack []undoOp
+
+	// Find-as-you-type (f): letters typed since are held here until
+	// findTimeout passes between them.
+	finding    bool
+	findPrefix string
+	findAt     time.Time
+
 	// Import state
-	importQueue   []string
-	importTotal   int
-	importDone    int
-	importSkipped int
-	importErrors  []string
+	importQueue       []importItem // URLs not yet dispatched
+	importInFlight    []importItem // imports dispatched but not yet finished
+	importPaused      bool         // stop dispatching; the queue is kept
+	importWindow      bool         // in stateConfirmResume, a new import is of the front window
+	importConcurrency int          // max imports in flight
+	importLimiter     *hostLimiter
+	importRetrying    bool   // an importRetryMsg is on its way
+	importGen         uint64 // incremented per batch; stale results are discarded
+	importTotal       int
+	importDone        int
+	importSkipped     int
+	importErrors      []importFailure
+	importWarnings    []error    // Safari sources that couldn't be read
+	importFetchTitles bool       // look up <title> for untitled tabs
+	importSort        importSort // arrangement of the import buffer
+	importSources     []string   // Safari sources to gather, in buffer order
+
+	// Title lookups for untitled tabs before the import buffer opens
+	titleTabs     map[string][]safari.Tab // tabs by source, titled as lookups finish
+	titleWindow   bool                    // the tabs are the front window's
+	titlesTotal   int
+	titlesPending int
+	titleCancel   context.CancelFunc
+	titleGen      uint64 // incremented per lookup; stale results are discarded
+
+	// macOS permission checklist shown before a Safari import
+	permissions             []safari.Permission
+	permCursor              int
+	permissionsAcknowledged bool // continue without asking again this session
+
+	// Dry-run preview between editing the import buffer and importing
+	importPreview       []importPreviewItem
+	importPreviewCursor int
+	importPreviewScroll int
+	importBufferPath    string // edited buffer, kept so the user can re-edit it
+
+	// Import failure review
+	failCursor   int
+	failScroll   int
+	failSelected map[int]bool // indices into importErrors marked for retry
 
 	// Status
-	err        error
-	statusMsg  string
+	err       error
+	statusMsg string
 
 	// Fetch generation counter — incremented when a fetch starts, checked
 	// when results arrive. Stale results (from cancelled fetches) are
@@ -87,12 +261,22 @@ type Model struct {
 	fetchGen uint64
 
 	// Tmux split
-	tmuxPaneID   string // tmux pane ID for the editor split (e.g. "%42")
-	positionFile string // temp file where vim writes cursor position on exit
+	tmuxPaneID   string   // tmux pane ID for the editor split (e.g. "%42")
+	tmuxNewPanes bool     // tmux_panes = "new": split a pane per article
+	tmuxPanes    []string // with tmuxNewPanes, the editor panes open, oldest first
+	tmuxSplits   int      // editor panes split so far, naming their wait-for channels
+	editingPath  string   // article last opened in the editor, for its highlights
+	positionFile string   // temp file where vim writes cursor position on exit
+
+	// Checkpointing the reading position while vim is open; see
+	// checkpointProgress.
+	checkpoint    time.Duration // checkpoint: how often vim saves it; 0: on exit only
+	checkpointing bool          // a checkpointProgress tick is pending
 
 	// suppressQuit is set when ctrl+c cancels a non-list state. This
 	// prevents the SIGINT-generated QuitMsg (which arrives after the
-	// KeyMsg transitions state to stateList) from killing the app.
+	// KeyMsg transitions state to stateList) from killing the app; see
+	// QuitFilter. Update clears it on the next message.
 	suppressQuit bool
 }
 
@@ -104,46 +288,137 @@ type (
 		gen    uint64
 	}
 	articleDeletedMsg struct{ id string }
-	extractionErrMsg struct {
+	extractionErrMsg  struct {
+		url string
 		err error
 		gen uint64
 	}
-	editorFinishedMsg   struct{ err error }
-	clearStatusMsg          struct{}
-	safariOpenedMsg         struct {
+	editorFinishedMsg struct {
+		err    error
+		paneID string // the tmux pane the editor ran in, if any
+		path   string // the article it was opened on, if it can't have changed
+	}
+	clearStatusMsg  struct{}
+	safariOpenedMsg struct {
 		window *safari.Window
 		err    error
 	}
-	safariHTMLExtractedMsg  struct {
+	safariHTMLExtractedMsg struct {
 		url  string
 		html string
 		err  error
 	}
+	endpointCheckedMsg struct{ err error }
+	frontTabMsg        struct {
+		url string
+		err error
+		gen uint64
+	}
 )
 
-// New creates a new TUI model. endpointURL is the Modal endpoint used for
-// HTML-to-Markdown conversion.
-func New(store *storage.Store, endpointURL string) Model {
+// endpointCheckTimeout bounds the startup endpoint check. It's generous
+// because the first request may wait for a Modal container to start.
+const endpointCheckTimeout = 10 * time.Second
+
+// spinners maps the config file's spinner names to bubbles spinners.
+var spinners = map[string]spinner.Spinner{
+	"dot":       spinner.Dot,
+	"minidot":   spinner.MiniDot,
+	"line":      spinner.Line,
+	"jump":      spinner.Jump,
+	"pulse":     spinner.Pulse,
+	"points":    spinner.Points,
+	"globe":     spinner.Globe,
+	"moon":      spinner.Moon,
+	"monkey":    spinner.Monkey,
+	"meter":     spinner.Meter,
+	"hamburger": spinner.Hamburger,
+	"ellipsis":  spinner.Ellipsis,
+}
+
+// New creates a new TUI model. cfg.Endpoint is the Modal endpoint used for
+// HTML-to-Markdown conversion; logger records import outcomes. A store from
+// storage.Open, not yet scanned, is scanned in the background from Init.
+func New(store *storage.Store, cfg config.Config, logger *slog.Logger) Model {
 	styles := DefaultStyles()
 	keys := DefaultKeyMap()
+	keys.Probe.SetEnabled(os.Getenv("SHELF_DEBUG") == "1")
 
 	s := spinner.New()
-	s.Spinner = spinner.Dot
 	s.Style = styles.Spinner
 
+	pdfArgv, _ := config.SplitCommand(cfg.PDFCommand) // checked by config.Load
+	extractOpts := []extractor.Option{
+		extractor.WithStrategy(extractor.Strategy(cfg.ExtractStrategy)),
+		extractor.WithPDFCommand(pdfArgv...),
+		extractor.WithDomainEndpoints(cfg.Endpoints),
+	}
+	if cfg.DemoteH1 {
+		extractOpts = append(extractOpts, extractor.WithDemoteH1())
+	}
+
 	m := Model{
 		state:        stateList,
 		store:        store,
-		extract:      extractor.New(endpointURL),
+		extract:      extractor.New(cfg.Endpoint, extractOpts...),
 		keys:         keys,
 		styles:       styles,
 		urlInput:     NewURLInput(styles),
+		titleInput:   NewTitleInput(styles),
+		noteInput:    NewArchiveNoteInput(styles),
+		tagInput:     NewTagInput(styles),
 		searchInput:  NewSearchInput(styles),
 		spinner:      s,
 		positionFile: filepath.Join(os.TempDir(), fmt.Sprintf("shelf-pos-%d", os.Getpid())),
+		logger:       logger,
+
+		imageProtocol:     termimg.Detect(),
+		searchHistory:     loadSearchHistory(filepath.Join(cfg.DataDir, "search_history"), cfg.SearchHistory),
+		importConcurrency: max(1, cfg.ImportConcurrency),
+		importLimiter:     newHostLimiter(cfg.ImportRate, 1),
+		extractCache:      newExtractCache(extractCacheSize),
+		exportRoot:        filepath.Join(cfg.DataDir, "exports"),
+		checkEndpoint:     cfg.CheckEndpoint,
+	}
+	appState, err := state.Load(filepath.Join(cfg.DataDir, state.FileName))
+	if err != nil {
+		logger.Warn("loading state", "err", err)
+	}
+	m.appState = appState
+	m.newSince, m.visitStart = appState.LastVisit, time.Now()
+	appState.LastVisit = m.visitStart
+	m.saveState()
+	if err := m.applyConfig(cfg); err != nil {
+		m.err = err
+	}
+	if len(cfg.UnknownKeys) > 0 {
+		m.statusMsg = m.msgs.format("status.config_unknown_keys", strings.Join(cfg.UnknownKeys, ", "))
+	}
+	if cfg.WatchConfig {
+		if info, err := os.Stat(config.Path()); err == nil {
+			m.configModTime = info.ModTime()
+		}
+	}
+	var session *state.Session
+	if m.restoreSession {
+		session = appState.Session
+	}
+	if !store.Scanned() {
+		// Init scans the articles in the background; see scanStore.
+		m.scanning, m.scanTotal = true, store.EntryCount()
+		m.pendingSession = session
+		return m
+	}
+	return m.showArticles(session)
+}
+
+// saveState persists m.appState, logging rather than surfacing failures:
+// losing activity counts or a paused queue isn't worth interrupting the user
+// over.
+func (m Model) saveState() {
+	if err := m.appState.Save(); err != nil {
+		m.logger.Warn("saving state", "err", err)
 	}
-	m.refreshArticles()
-	return m
 }
 
 // InListState reports whether the model is in the default list browsing state
@@ -154,7 +429,28 @@ func (m Model) InListState() bool {
 
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return nil
+	var cmds []tea.Cmd
+	if m.checkEndpoint {
+		cmds = append(cmds, m.checkEndpointCmd())
+	}
+	if m.cfg.WatchConfig {
+		cmds = append(cmds, m.watchConfig())
+	}
+	if m.scanning {
+		cmds = append(cmds, m.scanStore(), m.spinner.Tick)
+	} else {
+		cmds = append(cmds, m.measureTotalSize())
+	}
+	return tea.Batch(cmds...)
+}
+
+// checkEndpointCmd probes the endpoint in the background so a
+// misconfigured or unreachable one is flagged before the first save.
+func (m Model) checkEndpointCmd() tea.Cmd {
+	ext := m.extract
+	return func() tea.Msg {
+		return endpointCheckedMsg{err: ext.Check(endpointCheckTimeout)}
+	}
 }
 
 // Update handles messages and updates the model.
@@ -166,45 +462,84 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.urlInput = m.urlInput.SetWidth(msg.Width)
+		m.titleInput = m.titleInput.SetWidth(msg.Width)
+		m.noteInput = m.noteInput.SetWidth(msg.Width)
+		m.tagInput = m.tagInput.SetWidth(msg.Width)
 		m.searchInput = m.searchInput.SetWidth(msg.Width)
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		if m.state == statePreview {
+			m = m.resizePreview()
+		}
 		return m, nil
 
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
 
+	case debugProbedMsg:
+		return m.handleDebugProbed(msg)
+
+	case imagesLocalizedMsg:
+		return m.handleImagesLocalized(msg)
+
+	case endpointCheckedMsg:
+		m.endpointErr = msg.err
+		if msg.err != nil {
+			m.logger.Warn("endpoint check failed", "err", msg.err)
+		}
+		return m, nil
+
 	case spinner.TickMsg:
-		if m.state == stateLoading || m.state == stateGatheringTabs || m.state == stateImporting {
+		if m.scanning || m.state == stateLoading || m.state == stateGatheringTabs || m.state == stateResolvingTitles || m.state == stateImporting || m.state == stateExporting {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
 		}
 		return m, nil
 
+	case storeScannedMsg:
+		return m.handleStoreScanned(msg)
+
+	case totalSizeMsg:
+		m.totalSize = msg.size
+		return m, nil
+
 	case articleExtractedMsg:
 		// Discard results from cancelled fetches.
 		if msg.gen != m.fetchGen {
 			return m, nil
 		}
+		m.endpointErr = nil // evidently reachable after all
+		m.debugURL = ""
 		images := make([]storage.ImageFile, len(msg.result.Images))
 		for i, img := range msg.result.Images {
 			images[i] = storage.ImageFile{Path: img.Path, Data: img.Data}
 		}
-		// If overwriting a URL-matched article, delete old first.
-		if m.overwritePath != "" {
-			_ = m.store.Delete(m.overwritePath)
+		// A URL-matched article being overwritten is re-fetched in place.
+		save := m.store.SaveContent
+		if path := m.overwritePath; path != "" {
+			save = func(title, content string, images []storage.ImageFile) error {
+				return m.store.Refetch(path, title, content, images)
+			}
 			m.overwritePath = ""
 			m.overwriteTitle = ""
 		}
-		if err := m.store.SaveContent(msg.result.Title, msg.result.Content, images); err != nil {
+		if err := save(msg.result.Title, msg.result.Content, images); err != nil {
 			var existsErr *storage.ErrArticleExists
 			if errors.As(err, &existsErr) {
-				m.state = stateConfirmOverwrite
+				if m.isLocked(m.store.ArticlePath(msg.result.Title)) {
+					m.state = stateList
+					m.err = m.errLocked()
+					return m, nil
+				}
 				m.pendingResult = msg.result
-				return m, nil
+				return m.askOverwrite()
 			}
 			m.state = stateList
-			m.err = err
+			m.err = &saveError{err: err}
+			var lockedErr *storage.ErrArticleLocked
+			if errors.As(err, &lockedErr) {
+				m.err = m.errLocked()
+			}
 			return m, nil
 		}
 		m.state = stateList
@@ -217,13 +552,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 		}
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
-		return m.openSelectedArticle()
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		next, cmd := m.openSelectedArticle()
+		return next, tea.Batch(cmd, m.measureTotalSize())
 
 	case safariOpenedMsg:
 		if msg.err != nil {
 			m.state = stateList
-			m.err = fmt.Errorf("opening Safari: %w", msg.err)
+			m.err = fmt.Errorf(m.msgs.text("status.safari_open_failed"), msg.err)
 			m.safariURL = ""
 			m.safariWindow = nil
 			return m, nil
@@ -232,6 +568,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Stay in stateSafariWaiting — user will press Enter when ready.
 		return m, nil
 
+	case frontTabMsg:
+		if msg.gen != m.fetchGen || m.state != stateLoading {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.state = stateList
+			m.err = msg.err
+			return m, nil
+		}
+		m.urlInput = m.urlInput.SetValue(msg.url)
+		return m.submitURL(msg.url)
+
 	case safariHTMLExtractedMsg:
 		if msg.err != nil {
 			m.state = stateList
@@ -256,11 +604,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		url := strings.TrimSpace(m.urlInput.Value())
 		if url != "" && m.overwritePath == "" {
 			slug := titleFromURL(url)
-			title := fmt.Sprintf("Refetch needed — %s", slug)
-			content := fmt.Sprintf("---\ntitle: %q\nauthor:\nsource: %s\nsaved: %s\ntags:\nprogress:\n---\n\n*Extraction failed — use R to re-fetch via Safari.*\n",
-				title, url, time.Now().Format(time.RFC3339))
+			title := m.msgs.format("article.placeholder_title", slug)
+			content := fmt.Sprintf("---\ntitle: %q\nauthor:\nsource: %s\nsaved: %s\ntags:\nprogress:\n---\n\n%s\n",
+				title, url, time.Now().Format(time.RFC3339), m.msgs.text("article.placeholder_body"))
 			if err := m.store.SaveContent(title, content, nil); err != nil {
-				m.err = msg.err
+				m.err = &saveError{err: err}
 			} else {
 				m.refreshArticles()
 				for i, a := range m.articles {
@@ -269,43 +617,100 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						break
 					}
 				}
-				m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
-				m.statusMsg = fmt.Sprintf("Saved placeholder — use [R] to refetch via Safari")
+				m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+				m.statusMsg = m.msgs.text("status.placeholder_saved")
 			}
 		} else {
-			m.err = msg.err
+			m.err = &fetchError{err: msg.err}
 		}
 		m.overwritePath = ""
 		m.overwriteTitle = ""
+		if m.keys.Probe.Enabled() {
+			// Show what the page returned straight away.
+			m.debugURL = msg.url
+			return m.probeURL(msg.url)
+		}
 		return m, nil
 
 	case articleDeletedMsg:
 		m.refreshArticles()
-		m.statusMsg = "Article deleted"
-		return m, nil
+		m.statusMsg = m.msgs.text("status.article_deleted")
+		return m, m.measureTotalSize()
+
+	case checkpointMsg:
+		return m.handleCheckpoint()
 
 	case editorFinishedMsg:
-		m.tmuxPaneID = ""
+		if msg.paneID == m.tmuxPaneID {
+			m.tmuxPaneID = ""
+		}
+		m.tmuxPanes = slices.DeleteFunc(slices.Clone(m.tmuxPanes), func(id string) bool { return id == msg.paneID })
 		if msg.err != nil {
 			m.err = msg.err
 		}
 		m.savePositionFromFile()
+		if msg.path != "" {
+			m.syncHighlights(msg.path)
+		} else {
+			m.syncHighlights(m.editingPath)
+		}
 		// Reload index to pick up any manual edits to markdown metadata.
 		if err := m.store.Reload(); err != nil {
 			m.err = err
 		}
 		m.refreshArticles()
-		return m, nil
+		return m, m.measureTotalSize()
+
+	case permissionsCheckedMsg:
+		return m.handlePermissionsChecked(msg)
 
 	case safariTabsGatheredMsg:
 		return m.handleSafariTabsGathered(msg)
 
+	case frontWindowTabsMsg:
+		return m.handleFrontWindowTabs(msg)
+
+	case titleFetchedMsg:
+		return m.handleTitleFetched(msg)
+
 	case importEditorFinishedMsg:
 		return m.handleImportEditorFinished(msg)
 
 	case importArticleResultMsg:
 		return m.handleImportArticleResult(msg)
 
+	case importRetryMsg:
+		return m.handleImportRetry(msg)
+
+	case exportedMsg:
+		return m.handleExported(msg)
+
+	case configPolledMsg:
+		return m.handleConfigPolled(msg)
+
+	case pagerFinishedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+
+	case notesEditorFinishedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		// Pick up notes.md if it was just created (or emptied).
+		if err := m.store.Reload(); err != nil {
+			m.err = err
+		}
+		m.refreshArticles()
+		return m, nil
+
+	case externalOpenedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+
 	case clearStatusMsg:
 		m.statusMsg = ""
 		m.err = nil
@@ -317,6 +722,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.state {
 	case stateAddURL:
 		m.urlInput, cmd = m.urlInput.Update(msg)
+	case stateNewNote:
+		m.titleInput, cmd = m.titleInput.Update(msg)
+	case stateArchiveNote:
+		m.noteInput, cmd = m.noteInput.Update(msg)
+	case stateRenameTag:
+		m.tagInput, cmd = m.tagInput.Update(msg)
 	case stateSearch:
 		m.searchInput, cmd = m.searchInput.Update(msg)
 		// Update filtered articles
@@ -324,17 +735,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.cursor >= len(m.articles) {
 			m.cursor = max(0, len(m.articles)-1)
 		}
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 	}
 
 	return m, cmd
 }
 
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.ctrlCQuits && msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// Until the initial scan is done there's only quitting: there's no list
+	// to act on yet, an article saved meanwhile would be dropped when the
+	// scan's results replace the list, and a search typed would be replaced
+	// by the restored session's.
+	if m.scanning {
+		if key.Matches(msg, m.keys.Quit) {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
 	// Handle state-specific keys first
 	switch m.state {
 	case stateAddURL:
 		return m.handleAddURLKeys(msg)
+	case stateNewNote:
+		return m.handleNewNoteKeys(msg)
+	case stateArchiveNote:
+		return m.handleArchiveNoteKeys(msg)
+	case stateRenameTag:
+		return m.handleRenameTagKeys(msg)
 	case stateSearch:
 		return m.handleSearchKeys(msg)
 	case stateLoading, stateGatheringTabs:
@@ -346,6 +778,8 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, nil
+	case stateResolvingTitles:
+		return m.handleResolvingTitlesKeys(msg)
 	case stateSafariWaiting:
 		switch {
 		case key.Matches(msg, m.keys.Submit): // Enter
@@ -365,20 +799,60 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case stateImporting:
+		if key.Matches(msg, m.keys.PauseImport) {
+			return m.toggleImportPause()
+		}
+		// While paused, leaving keeps the queue for later.
+		if m.importPaused && (key.Matches(msg, m.keys.Cancel) || key.Matches(msg, m.keys.Quit)) {
+			return m.leavePausedImport()
+		}
 		// Cancel stops remaining imports but keeps already-saved articles.
 		if key.Matches(msg, m.keys.Cancel) || key.Matches(msg, m.keys.Quit) || msg.String() == "ctrl+c" {
+			m.importPaused = false
+			m.setPausedImport(nil)
 			m.importQueue = nil
+			m.importInFlight = nil
+			m.importGen++ // in-flight results are discarded
 			m.state = stateList
 			m.suppressQuit = true
 			m.refreshArticles()
-			m.statusMsg = m.importSummary() + " (cancelled)"
+			m.statusMsg = m.withSourceWarnings(m.msgs.format("status.import_aborted", m.importSummary()))
+			m.logger.Info("import cancelled", "done", m.importDone, "total", m.importTotal)
 			return m, nil
 		}
 		return m, nil
+	case stateExporting:
+		return m.handleExportingKeys(msg)
 	case stateConfirmOverwrite:
 		return m.handleConfirmOverwriteKeys(msg)
 	case stateConfirmDelete:
 		return m.handleConfirmDeleteKeys(msg)
+	case stateConfirmQuit:
+		return m.handleConfirmQuitKeys(msg)
+	case stateConfirmDeleteTag:
+		return m.handleConfirmDeleteTagKeys(msg)
+	case stateConfirmResume:
+		return m.handleConfirmResumeKeys(msg)
+	case stateConfirmResetTag:
+		return m.handleConfirmResetTagKeys(msg)
+	case stateImportPreview:
+		return m.handleImportPreviewKeys(msg)
+	case stateImportFailures:
+		return m.handleImportFailuresKeys(msg)
+	case stateSavedSearches:
+		return m.handleSavedSearchKeys(msg)
+	case stateTags:
+		return m.handleTagKeys(msg)
+	case stateImages:
+		return m.handleImagesKeys(msg)
+	case statePreview:
+		return m.handlePreviewKeys(msg)
+	case statePermissions:
+		return m.handlePermissionsKeys(msg)
+	case statePeek:
+		// Any key closes the peek, and does nothing else.
+		m.state = stateList
+		return m, nil
 	case stateHelp:
 		// Exit help and re-process the key as a list action,
 		// so e.g. pressing X both closes help and toggles archives.
@@ -390,27 +864,69 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Fall through to list key handling below.
 	}
 
+	if m.finding {
+		var handled bool
+		if m, handled = m.handleFindKey(msg); handled {
+			return m, nil
+		}
+	}
+
+	// ShowError shows an error cut short in the status lines in full.
+	if m.err != nil && key.Matches(msg, m.keys.ShowError) {
+		if _, cut := m.statusText(); cut {
+			full := m.msgs.errorText(m.err)
+			m.err = nil
+			return m.showPreview(m.msgs.text("view.error"), full), nil
+		}
+	}
+
 	// Any keypress in the list clears a previous status/error toast.
 	m.statusMsg = ""
 	m.err = nil
 
+	// A choice remembered at the overwrite prompt answers it while adding
+	// and re-fetching articles; any other key ends the batch.
+	if m.overwriteMemo != "" && !key.Matches(msg, m.keys.Add, m.keys.SaveTab, m.keys.Reload, m.keys.SafariReload, m.keys.Up, m.keys.Down, m.keys.Top, m.keys.Bottom) {
+		m.overwriteMemo = ""
+	}
+
+	// Complete a pending chord. Within the timeout, the second key is
+	// consumed whether or not it forms a known chord, so a lone d followed
+	// by another key does nothing.
+	if m.pendingKey != "" {
+		chord := m.pendingKey + msg.String()
+		expired := time.Since(m.pendingKeyAt) > keyChordTimeout
+		m.pendingKey = ""
+		if !expired {
+			return m.handleChord(chord)
+		}
+	}
+
+	if action, ok := actionForKey(msg, m.keys, m.openAction); ok {
+		return m.openSelected(action)
+	}
+
 	// List state keys
 	switch {
 	case key.Matches(msg, m.keys.Quit):
+		if m.confirmQuit {
+			m.state = stateConfirmQuit
+			return m, nil
+		}
 		return m, tea.Quit
 
 	case key.Matches(msg, m.keys.Up):
 		if m.cursor > 0 {
 			m.cursor--
 		}
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 		return m, nil
 
 	case key.Matches(msg, m.keys.Down):
 		if m.cursor < len(m.articles)-1 {
 			m.cursor++
 		}
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 		return m, nil
 
 	case key.Matches(msg, m.keys.Top):
@@ -422,12 +938,9 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.articles) > 0 {
 			m.cursor = len(m.articles) - 1
 		}
-		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 		return m, nil
 
-	case key.Matches(msg, m.keys.Open):
-		return m.openSelectedArticle()
-
 	case key.Matches(msg, m.keys.Add):
 		m.state = stateAddURL
 		m.urlInput = m.urlInput.Reset()
@@ -436,31 +949,103 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.urlInput, cmd = m.urlInput.Focus()
 		return m, cmd
 
-	case key.Matches(msg, m.keys.Import):
-		m.state = stateGatheringTabs
+	case key.Matches(msg, m.keys.NewNote):
+		m.state = stateNewNote
+		m.titleInput = m.titleInput.Reset()
+		m.err = nil
+		var cmd tea.Cmd
+		m.titleInput, cmd = m.titleInput.Focus()
+		return m, cmd
+
+	case key.Matches(msg, m.keys.SaveTab):
+		m.state = stateLoading
 		m.err = nil
-		return m, tea.Batch(m.spinner.Tick, gatherSafariTabs())
+		m.urlInput = m.urlInput.Reset()
+		m.fetchGen++
+		return m, tea.Batch(m.spinner.Tick, m.readFrontTab())
+
+	case key.Matches(msg, m.keys.Import):
+		return m.startSafariImport()
+
+	case key.Matches(msg, m.keys.ImportWindow):
+		return m.startWindowImport()
+
+	case key.Matches(msg, m.keys.Probe):
+		return m.probeURL(m.debugTarget())
 
 	case key.Matches(msg, m.keys.Delete):
 		if len(m.articles) == 0 || m.cursor >= len(m.articles) {
 			return m, nil
 		}
+		if m.deleteStyle == "dd" {
+			m.pendingKey = msg.String()
+			m.pendingKeyAt = time.Now()
+			return m, nil
+		}
 		article := m.articles[m.cursor]
 		m.pendingDeletePath = article.FilePath
 		m.pendingDeleteTitle = article.Title
 		m.state = stateConfirmDelete
 		return m, nil
 
+	case key.Matches(msg, m.keys.Yank):
+		if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+			return m, nil
+		}
+		m.pendingKey = msg.String()
+		m.pendingKeyAt = time.Now()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Images):
+		return m.openImageList()
+
+	case key.Matches(msg, m.keys.Notes):
+		return m.openNotes()
+
 	case key.Matches(msg, m.keys.Archive):
 		return m.archiveSelectedArticle()
 
+	case key.Matches(msg, m.keys.Pin):
+		return m.pinSelectedArticle()
+
+	case key.Matches(msg, m.keys.Lock):
+		return m.lockSelectedArticle()
+
+	case key.Matches(msg, m.keys.ClearWarnings):
+		return m.clearSelectedWarnings()
+
+	case key.Matches(msg, m.keys.MoveUp):
+		return m.moveSelectedArticle(-1)
+
+	case key.Matches(msg, m.keys.MoveDown):
+		return m.moveSelectedArticle(1)
+
+	case key.Matches(msg, m.keys.ResetProgress):
+		return m.resetSelectedProgress()
+
+	case key.Matches(msg, m.keys.Undo):
+		return m.undo()
+
+	case key.Matches(msg, m.keys.Redo):
+		return m.redo()
+
 	case key.Matches(msg, m.keys.ShowArchive):
 		m.showArchived = !m.showArchived
 		m.refreshArticles()
 		return m, nil
 
+	case key.Matches(msg, m.keys.SavedSearch):
+		return m.openSavedSearches()
+
+	case key.Matches(msg, m.keys.Tags):
+		return m.toggleTagSidebar()
+
+	case key.Matches(msg, m.keys.FocusTags) && m.showTags:
+		return m.focusTagSidebar()
+
 	case key.Matches(msg, m.keys.Search):
 		m.state = stateSearch
+		m.activeSearch = ""
 		m.searchInput = m.searchInput.Clear()
 		m.refreshArticles()
 		m.cursor = 0
@@ -475,10 +1060,16 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		article := m.articles[m.cursor]
 		if article.SourceURL == "" {
-			m.err = fmt.Errorf("no source URL for %q", article.Title)
+			m.err = errors.New(m.msgs.format("status.no_source_url", article.Title))
+			return m, nil
+		}
+		if article.Locked {
+			m.err = m.errLocked()
 			return m, nil
 		}
-		// Pre-fill the URL bar and go straight to fetching.
+		// Pre-fill the URL bar and go straight to fetching, past any
+		// extraction cached earlier in the session.
+		m.extractCache.forget(article.SourceURL)
 		m.urlInput = m.urlInput.SetValue(article.SourceURL).Blur()
 		m.overwritePath = article.FilePath
 		m.overwriteTitle = article.Title
@@ -493,13 +1084,32 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = stateHelp
 		return m, nil
 
+	case key.Matches(msg, m.keys.Peek):
+		return m.peekSelected()
+
+	case key.Matches(msg, m.keys.Find):
+		return m.startFind()
+
+	case key.Matches(msg, m.keys.OpenDataDir):
+		return m.openDataDir()
+
+	case key.Matches(msg, m.keys.Reveal):
+		return m.revealSelected()
+
+	case key.Matches(msg, m.keys.Export):
+		return m.exportView()
+
 	case key.Matches(msg, m.keys.SafariReload):
 		if len(m.articles) == 0 || m.cursor >= len(m.articles) {
 			return m, nil
 		}
 		article := m.articles[m.cursor]
 		if article.SourceURL == "" {
-			m.err = fmt.Errorf("no source URL for %q", article.Title)
+			m.err = errors.New(m.msgs.format("status.no_source_url", article.Title))
+			return m, nil
+		}
+		if article.Locked {
+			m.err = m.errLocked()
 			return m, nil
 		}
 		m.overwritePath = article.FilePath
@@ -510,6 +1120,9 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, m.openInSafari(article.SourceURL)
 	}
 
+	if mac, ok := m.macroForKey(msg); ok {
+		return m.runMacro(mac)
+	}
 	return m, nil
 }
 
@@ -528,70 +1141,90 @@ func (m Model) handleAddURLKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Submit):
-		rawURL := strings.TrimSpace(m.urlInput.Value())
-		if rawURL == "" {
-			m.state = stateList
-			m.err = fmt.Errorf("URL cannot be empty")
+		return m.submitURL(m.urlInput.Value())
+	}
+
+	// Pass to text input
+	var cmd tea.Cmd
+	m.urlInput, cmd = m.urlInput.Update(msg)
+	return m, cmd
+}
+
+// handleNewNoteKeys handles the title prompt for a new note. Once titled,
+// the note is saved, with no source, and opened in the editor to be written.
+func (m Model) handleNewNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		if m.titleInput.Value() != "" {
+			m.titleInput = m.titleInput.Reset()
 			return m, nil
 		}
-		// Validate URL format before sending to the server.
-		originalURL := rawURL
-		if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
-			rawURL = "https://" + rawURL
-			m.urlInput = m.urlInput.SetValue(rawURL)
-		}
-		if u, err := neturl.Parse(rawURL); err != nil || u.Host == "" || !strings.Contains(u.Host, ".") {
-			m.err = fmt.Errorf("invalid URL: %s", originalURL)
-			m.state = stateList
+		m.state = stateList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.state = stateList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		title := strings.TrimSpace(m.titleInput.Value())
+		m.titleInput = m.titleInput.Blur()
+		m.state = stateList
+		if title == "" {
+			m.err = errors.New(m.msgs.text("status.title_empty"))
 			return m, nil
 		}
-		url := rawURL
-		m.urlInput = m.urlInput.Blur()
-		// Check if an article from this URL already exists.
-		for _, a := range m.store.List() {
-			if a.SourceURL == url {
-				if a.IsArchived() {
-					// Unarchive instead of re-fetching.
-					var newTags []string
-					for _, t := range a.Tags {
-						if strings.ToLower(t) != "archived" {
-							newTags = append(newTags, t)
-						}
-					}
-					if err := m.store.UpdateTags(a.FilePath, newTags); err != nil {
-						m.err = err
-						m.state = stateList
-						return m, nil
-					}
-					m.state = stateList
-					m.statusMsg = fmt.Sprintf("Unarchived %q", a.Title)
-					m.refreshArticles()
-					for i, ar := range m.articles {
-						if ar.FilePath == a.FilePath {
-							m.cursor = i
-							break
-						}
-					}
-					m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
-					return m, nil
-				}
-				m.state = stateConfirmOverwrite
-				m.overwritePath = a.FilePath
-				m.overwriteTitle = a.Title
+		filePath, err := m.store.SaveNote(title)
+		if err != nil {
+			var existsErr *storage.ErrArticleExists
+			if errors.As(err, &existsErr) {
+				m.err = errors.New(m.msgs.format("plain.already_saved", existsErr.Title))
 				return m, nil
 			}
+			m.err = &saveError{err: err}
+			return m, nil
 		}
-		m.state = stateLoading
-		m.fetchGen++
-		return m, tea.Batch(
-			m.spinner.Tick,
-			m.extractArticle(url),
-		)
+		m.refreshArticles()
+		for i, a := range m.articles {
+			if a.FilePath == filePath {
+				m.cursor = i
+				break
+			}
+		}
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+		return m.openSelectedArticle()
 	}
 
-	// Pass to text input
 	var cmd tea.Cmd
-	m.urlInput, cmd = m.urlInput.Update(msg)
+	m.titleInput, cmd = m.titleInput.Update(msg)
+	return m, cmd
+}
+
+// handleArchiveNoteKeys handles the prompt for why an article is being
+// archived. Enter archives it, with the reason if one was typed.
+func (m Model) handleArchiveNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		if m.noteInput.Value() != "" {
+			m.noteInput = m.noteInput.Reset()
+			return m, nil
+		}
+		m.state = stateList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.state = stateList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		note := strings.TrimSpace(m.noteInput.Value())
+		m.noteInput = m.noteInput.Blur()
+		m.state = stateList
+		return m.setArchived(m.archiving, true, note)
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
 	return m, cmd
 }
 
@@ -600,6 +1233,8 @@ func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case msg.String() == "ctrl+c":
 		if m.searchInput.Value() != "" {
 			m.searchInput = m.searchInput.Clear()
+			m.searchHistory.reset()
+			m.activeSearch = ""
 			m.refreshArticles()
 			m.cursor = 0
 			m.scrollPos = 0
@@ -613,6 +1248,7 @@ func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = stateList
 		m.searchInput = m.searchInput.Deactivate()
 		m.searchInput = m.searchInput.Clear()
+		m.searchHistory.reset()
 		m.refreshArticles()
 		m.cursor = 0
 		m.scrollPos = 0
@@ -621,43 +1257,127 @@ func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Submit):
 		m.state = stateList
 		m.searchInput = m.searchInput.Deactivate()
+		if err := m.searchHistory.add(m.searchInput.Value()); err != nil {
+			m.logger.Warn("saving search history", "err", err)
+		}
 		return m, nil
 	}
 
-	// Pass to search input
 	var cmd tea.Cmd
-	m.searchInput, cmd = m.searchInput.Update(msg)
+	switch msg.String() {
+	case "up":
+		// Recall older queries, like shell history.
+		if q, ok := m.searchHistory.prev(m.searchInput.Value()); ok {
+			m.searchInput = m.searchInput.SetValue(q)
+		}
+	case "down":
+		if q, ok := m.searchHistory.next(); ok {
+			m.searchInput = m.searchInput.SetValue(q)
+		}
+	default:
+		// Pass to search input
+		m.searchInput, cmd = m.searchInput.Update(msg)
+	}
 	// Update filtered results
 	m.articles = m.applyArchiveFilter(m.store.Search(m.searchInput.Value()))
 	if m.cursor >= len(m.articles) {
 		m.cursor = max(0, len(m.articles)-1)
 	}
-	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 	return m, cmd
 }
 
-func (m Model) extractArticle(url string) tea.Cmd {
-	gen := m.fetchGen
-	return func() tea.Msg {
-		result, err := m.extract.Extract(url)
-		if err != nil {
-			return extractionErrMsg{err: err, gen: gen}
-		}
-		return articleExtractedMsg{result: result, gen: gen}
+// submitURL starts saving the article at rawURL, as if it had been typed
+// into the URL input: an existing article from the same URL is unarchived
+// or offered for re-fetching instead.
+func (m Model) submitURL(rawURL string) (tea.Model, tea.Cmd) {
+	// Validate URL format before sending to the server.
+	url, err := validateAndNormalizeURL(rawURL)
+	if err != nil {
+		m.err = err
+		m.state = stateList
+		return m, nil
 	}
-}
-
-func (m Model) extractArticleFromHTML(url, html string) tea.Cmd {
-	gen := m.fetchGen
-	return func() tea.Msg {
+	m.urlInput = m.urlInput.SetValue(url)
+	m.urlInput = m.urlInput.Blur()
+	// Check if an article from this URL already exists.
+	for _, a := range m.store.List() {
+		if a.SourceURL == url {
+			if a.IsArchived() {
+				// Unarchive instead of re-fetching.
+				if err := m.store.SetArchived(a.FilePath, false); err != nil {
+					m.err = err
+					m.state = stateList
+					return m, nil
+				}
+				m.state = stateList
+				m.statusMsg = m.msgs.format("status.unarchived", a.Title)
+				m.refreshArticles()
+				for i, ar := range m.articles {
+					if ar.FilePath == a.FilePath {
+						m.cursor = i
+						break
+					}
+				}
+				m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+				return m, nil
+			}
+			if a.Locked {
+				m.state = stateList
+				m.err = m.errLocked()
+				return m, nil
+			}
+			m.overwritePath = a.FilePath
+			m.overwriteTitle = a.Title
+			return m.askOverwrite()
+		}
+	}
+	m.state = stateLoading
+	m.fetchGen++
+	return m, tea.Batch(
+		m.spinner.Tick,
+		m.extractArticle(url),
+	)
+}
+
+// extractArticle fetches and converts url, or takes the result of an
+// earlier fetch this session from the extraction cache.
+func (m Model) extractArticle(url string) tea.Cmd {
+	gen := m.fetchGen
+	cache := m.extractCache
+	return func() tea.Msg {
+		if result, ok := cache.get(url); ok {
+			return articleExtractedMsg{result: result, gen: gen}
+		}
+		result, err := m.extract.Extract(url)
+		if err != nil {
+			return extractionErrMsg{url: url, err: err, gen: gen}
+		}
+		cache.put(url, result)
+		return articleExtractedMsg{result: result, gen: gen}
+	}
+}
+
+func (m Model) extractArticleFromHTML(url, html string) tea.Cmd {
+	gen := m.fetchGen
+	return func() tea.Msg {
 		result, err := m.extract.ExtractFromHTML(url, html)
 		if err != nil {
-			return extractionErrMsg{err: err, gen: gen}
+			return extractionErrMsg{url: url, err: err, gen: gen}
 		}
 		return articleExtractedMsg{result: result, gen: gen}
 	}
 }
 
+// readFrontTab reads the URL of Safari's frontmost tab, to save it.
+func (m Model) readFrontTab() tea.Cmd {
+	gen := m.fetchGen
+	return func() tea.Msg {
+		url, err := safari.FrontTabURL()
+		return frontTabMsg{url: url, err: err, gen: gen}
+	}
+}
+
 func (m Model) openInSafari(url string) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(750 * time.Millisecond) // Let TUI render before Safari steals focus.
@@ -669,13 +1389,14 @@ func (m Model) openInSafari(url string) tea.Cmd {
 func (m Model) extractSafariHTML() tea.Cmd {
 	url := m.safariURL
 	w := m.safariWindow
+	msgs := m.msgs
 	return func() tea.Msg {
 		html, err := w.TabSource()
 		if err != nil {
-			return safariHTMLExtractedMsg{url: url, err: fmt.Errorf("extracting HTML from Safari: %w", err)}
+			return safariHTMLExtractedMsg{url: url, err: fmt.Errorf(msgs.text("status.safari_extract_failed"), err)}
 		}
 		if strings.TrimSpace(html) == "" {
-			return safariHTMLExtractedMsg{url: url, err: fmt.Errorf("Safari returned empty HTML")}
+			return safariHTMLExtractedMsg{url: url, err: errors.New(msgs.text("status.safari_empty_html"))}
 		}
 		_ = w.Close()
 		return safariHTMLExtractedMsg{url: url, html: html}
@@ -685,50 +1406,101 @@ func (m Model) extractSafariHTML() tea.Cmd {
 func (m Model) handleConfirmOverwriteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
+		return m.acceptOverwrite()
+	case "a":
+		m.overwriteMemo = "always"
+		return m.acceptOverwrite()
+	case "s":
+		m.overwriteMemo = "never"
+		return m.declineOverwrite()
+	case "n", "N", "esc", "ctrl+c":
+		m.suppressQuit = true
+		return m.declineOverwrite()
+	}
+	return m, nil
+}
+
+// askOverwrite asks whether to overwrite an article that's already saved,
+// either before fetching it again (overwritePath is set) or after a fetch
+// whose title is taken (pendingResult is set), unless the overwrite setting
+// or a choice remembered at the prompt answers for the user.
+func (m Model) askOverwrite() (tea.Model, tea.Cmd) {
+	choice := m.overwrite
+	if m.overwriteMemo != "" {
+		choice = m.overwriteMemo
+	}
+	switch choice {
+	case "always":
+		return m.acceptOverwrite()
+	case "never":
+		title := m.overwriteTitle
 		if m.pendingResult != nil {
-			// Post-fetch slug collision: force save.
-			images := make([]storage.ImageFile, len(m.pendingResult.Images))
-			for i, img := range m.pendingResult.Images {
-				images[i] = storage.ImageFile{Path: img.Path, Data: img.Data}
-			}
-			if err := m.store.SaveContentForce(m.pendingResult.Title, m.pendingResult.Content, images); err != nil {
-				m.state = stateList
-				m.err = err
-				m.pendingResult = nil
-				return m, nil
-			}
+			title = m.pendingResult.Title
+		}
+		next, cmd := m.declineOverwrite()
+		m = next.(Model)
+		m.statusMsg = m.msgs.format("status.overwrite_skipped", title)
+		return m, cmd
+	}
+	path := m.overwritePath
+	if m.pendingResult != nil {
+		path = m.store.ArticlePath(m.pendingResult.Title)
+	}
+	m.overwriteInfo = m.overwriteDetailsFor(path)
+	m.state = stateConfirmOverwrite
+	return m, nil
+}
+
+// acceptOverwrite overwrites the saved article: with the fetched one for a
+// title collision, or by fetching it again for a URL match.
+func (m Model) acceptOverwrite() (tea.Model, tea.Cmd) {
+	if m.pendingResult != nil {
+		// Post-fetch slug collision: force save.
+		images := make([]storage.ImageFile, len(m.pendingResult.Images))
+		for i, img := range m.pendingResult.Images {
+			images[i] = storage.ImageFile{Path: img.Path, Data: img.Data}
+		}
+		if err := m.store.SaveContentForce(m.pendingResult.Title, m.pendingResult.Content, images); err != nil {
 			m.state = stateList
-			m.refreshArticles()
-			m.err = nil
-			for i, a := range m.articles {
-				if a.Title == m.pendingResult.Title {
-					m.cursor = i
-					break
-				}
+			m.err = &saveError{err: err}
+			var lockedErr *storage.ErrArticleLocked
+			if errors.As(err, &lockedErr) {
+				m.err = m.errLocked()
 			}
-			m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
 			m.pendingResult = nil
-			return m.openSelectedArticle()
+			return m, nil
 		}
-		// Pre-fetch URL match: proceed to fetch (overwritePath stays set).
-		url := strings.TrimSpace(m.urlInput.Value())
-		m.state = stateLoading
-		m.fetchGen++
-		return m, tea.Batch(
-			m.spinner.Tick,
-			m.extractArticle(url),
-		)
-	case "n", "N", "esc", "ctrl+c":
 		m.state = stateList
-		m.suppressQuit = true
+		m.refreshArticles()
+		m.err = nil
+		for i, a := range m.articles {
+			if a.Title == m.pendingResult.Title {
+				m.cursor = i
+				break
+			}
+		}
+		m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
 		m.pendingResult = nil
-		m.overwritePath = ""
-		m.overwriteTitle = ""
-		return m, nil
+		return m.openSelectedArticle()
 	}
-	return m, nil
+	// Pre-fetch URL match: proceed to fetch (overwritePath stays set).
+	url := strings.TrimSpace(m.urlInput.Value())
+	m.state = stateLoading
+	m.fetchGen++
+	return m, tea.Batch(
+		m.spinner.Tick,
+		m.extractArticle(url),
+	)
 }
 
+// declineOverwrite keeps the saved article and returns to the list.
+func (m Model) declineOverwrite() (tea.Model, tea.Cmd) {
+	m.state = stateList
+	m.pendingResult = nil
+	m.overwritePath = ""
+	m.overwriteTitle = ""
+	return m, nil
+}
 
 func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -737,13 +1509,7 @@ func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.pendingDeletePath = ""
 		m.pendingDeleteTitle = ""
 		m.state = stateList
-		if err := m.store.Delete(path); err != nil {
-			m.err = err
-			return m, nil
-		}
-		return m, func() tea.Msg {
-			return articleDeletedMsg{id: path}
-		}
+		return m.deleteArticle(path)
 	case "n", "N", "esc", "ctrl+c":
 		m.state = stateList
 		m.suppressQuit = true
@@ -754,6 +1520,93 @@ func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleConfirmQuitKeys answers the confirm_quit prompt. Like the other
+// confirmations, ctrl+c backs out rather than quitting; the state is back to
+// list before tea.Quit so main's filter lets the quit through.
+func (m Model) handleConfirmQuitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.state = stateList
+		return m, tea.Quit
+	case "n", "N", "esc", "ctrl+c":
+		m.state = stateList
+		m.suppressQuit = true
+		return m, nil
+	}
+	return m, nil
+}
+
+// keyChordTimeout is how long the first key of a chord waits for the second.
+const keyChordTimeout = time.Second
+
+// handleChord runs a completed two-key chord from the list. Unknown chords
+// are no-ops.
+func (m Model) handleChord(chord string) (tea.Model, tea.Cmd) {
+	switch chord {
+	case "dd":
+		if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+			return m, nil
+		}
+		return m.deleteArticle(m.articles[m.cursor].FilePath)
+	case "yy", "yb", "yu":
+		if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+			return m, nil
+		}
+		return m.yankArticle(m.articles[m.cursor], chord[1])
+	}
+	return m, nil
+}
+
+// yankArticle copies part of an article to the clipboard: its local file
+// path ('y'), markdown body ('b'), or source URL ('u').
+func (m Model) yankArticle(article storage.ArticleMeta, what byte) (tea.Model, tea.Cmd) {
+	var text, copied string
+	switch what {
+	case 'y':
+		text, copied = m.store.GetFilePath(article.FilePath), "status.copied_path"
+	case 'b':
+		full, err := m.store.Get(article.FilePath)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		text, copied = full.Content, "status.copied_body"
+	case 'u':
+		if article.SourceURL == "" {
+			m.err = errors.New(m.msgs.format("status.no_source_url", article.Title))
+			return m, nil
+		}
+		text, copied = article.SourceURL, "status.copied_url"
+	}
+	if err := copyToClipboard(text); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.statusMsg = m.msgs.format(copied, article.Title)
+	return m, nil
+}
+
+// deleteArticle moves the article at path to the trash, where u can bring
+// it back from.
+func (m Model) deleteArticle(path string) (tea.Model, tea.Cmd) {
+	title := path
+	for _, a := range m.articles {
+		if a.FilePath == path {
+			title = a.Title
+			break
+		}
+	}
+	trashed, err := m.store.Trash(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m = m.pushUndo(undoOp{desc: m.msgs.format("status.deleted", title), filePath: path, trashed: trashed})
+	return m, func() tea.Msg {
+		return articleDeletedMsg{id: path}
+	}
+}
+
 func inTmux() bool {
 	return os.Getenv("TMUX") != ""
 }
@@ -767,22 +1620,79 @@ func isVimEditor(editor string) bool {
 	return base == "vim" || base == "nvim"
 }
 
+// vimWritePosition is a vim command that writes
+// "absolutePath:line:col:topLine" for the current window to posFile.
+func vimWritePosition(posFile string) string {
+	return "call " + vimPositionExpr(posFile)
+}
+
+// vimPositionExpr is the vim expression vimWritePosition calls.
+func vimPositionExpr(posFile string) string {
+	return fmt.Sprintf(
+		`writefile([expand('%%:p') . ':' . line('.') . ':' . col('.') . ':' . line('w0')], '%s')`,
+		posFile,
+	)
+}
+
+// vimCheckpoint is a vim command that writes the position to posFile every
+// interval, as vimWritePosition does on exit, for shelf to save as progress
+// while the editor is still open. It skips a buffer with unsaved changes:
+// saving progress rewrites the file, and vim would then have two versions
+// of it. Otherwise the rewritten file is read back in quietly when vim is
+// idle.
+func vimCheckpoint(posFile string, interval time.Duration) string {
+	return fmt.Sprintf(
+		`set autoread | call timer_start(%d, {-> &modified ? 0 : %s}, {'repeat': -1}) | au CursorHold,FocusGained * silent! checktime`,
+		interval.Milliseconds(), vimPositionExpr(posFile),
+	)
+}
+
+// vimRestoreView is a vim command that scrolls to and places the cursor at
+// view, or "" when there is no column or scroll position to restore beyond
+// the line.
+func vimRestoreView(view state.EditorView) string {
+	if view.Col <= 0 || view.TopLine <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("call winrestview({'lnum': %d, 'col': %d, 'topline': %d})", view.Line, view.Col-1, view.TopLine)
+}
+
+// editorView returns where to open an article in vim: the view it was last
+// left at, if that's still the line its progress is at, or else just the
+// progress line. Progress can change outside the editor (e.g. "0" resets
+// it), and then the remembered view is stale.
+func (m Model) editorView(article storage.ArticleMeta) state.EditorView {
+	if view, ok := m.appState.EditorViews[article.FilePath]; ok && view.Line == article.Progress {
+		return view
+	}
+	return state.EditorView{Line: article.Progress}
+}
+
 // vimEditorCommand builds a shell command string for vim/nvim that:
-// - Opens the file at the saved progress line (if any)
+// - Opens the file where the reader left it (see editorView), if anywhere
 // - Sets a VimLeave autocmd to write the final cursor position to posFile
-func vimEditorCommand(editor, fpath, posFile string, progress int) string {
+// - With a checkpoint interval, also writes it there every interval
+// - Maps <leader>h in visual mode to wrap the selection in ==highlight==
+func vimEditorCommand(editor, fpath, posFile string, view state.EditorView, checkpoint time.Duration) string {
 	startArg := ""
-	if progress > 0 {
-		startArg = fmt.Sprintf("+%d ", progress)
+	if view.Line > 0 {
+		startArg = fmt.Sprintf("+%d ", view.Line)
 	}
-	// The autocmd writes "absolutePath:lineNum" to posFile on VimLeave.
-	autocmd := fmt.Sprintf(
-		`au VimLeave * call writefile([expand('%%:p') . ':' . line('.')], '%s')`,
-		posFile,
-	)
-	return fmt.Sprintf(`%s %s-c "%s" %q`, editor, startArg, autocmd, fpath)
+	if restore := vimRestoreView(view); restore != "" {
+		startArg += fmt.Sprintf(`-c "%s" `, restore)
+	}
+	autocmd := "au VimLeave * " + vimWritePosition(posFile)
+	if checkpoint > 0 {
+		startArg += fmt.Sprintf(`-c "%s" `, vimCheckpoint(posFile, checkpoint))
+	}
+	// Append after the selection's end mark first, so inserting at its start
+	// doesn't move it. Backticks are escaped for the shell's double quotes.
+	highlight := "xnoremap <leader>h <Esc>\\`>a==<Esc>\\`<i==<Esc>"
+	return fmt.Sprintf(`%s %s-c "%s" -c "%s" %q`, editor, startArg, autocmd, highlight, fpath)
 }
 
+// openSelectedArticle opens the selected article in $EDITOR, restoring and
+// afterwards recording the reader's position for vim/nvim.
 func (m Model) openSelectedArticle() (tea.Model, tea.Cmd) {
 	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
 		return m, nil
@@ -795,33 +1705,36 @@ func (m Model) openSelectedArticle() (tea.Model, tea.Cmd) {
 	if editor == "" {
 		editor = "nvim"
 	}
+	previous := m.editingPath
+	m.editingPath = article.FilePath
 
 	if !inTmux() {
-		return m.openArticleExecProcess(editor, fpath, article.Progress)
+		return m.openArticleExecProcess(editor, fpath, m.editorView(article))
 	}
 
-	// Clean up stale pane ID if pane is dead.
+	// Clean up stale pane IDs if panes are dead.
 	if m.tmuxPaneID != "" && !tmuxPaneAlive(m.tmuxPaneID) {
 		m.tmuxPaneID = ""
 	}
+	m.tmuxPanes = slices.DeleteFunc(slices.Clone(m.tmuxPanes), func(id string) bool { return !tmuxPaneAlive(id) })
 
 	// Tmux: reuse existing pane if alive and editor is vim/nvim.
-	if m.tmuxPaneID != "" {
+	if m.tmuxPaneID != "" && !m.tmuxNewPanes {
 		if isVimEditor(editor) {
 			// Save the current file's cursor position before switching.
-			saveCmd := fmt.Sprintf(
-				`:call writefile([expand('%%:p') . ':' . line('.')], '%s')`,
-				m.positionFile,
-			)
+			saveCmd := ":" + vimWritePosition(m.positionFile)
 			_ = exec.Command("tmux", "send-keys", "-t", m.tmuxPaneID, saveCmd, "Enter").Run()
 			time.Sleep(50 * time.Millisecond)
 			m.savePositionFromFile()
+			m.syncHighlights(previous)
 
 			// Send :e command to switch files in the existing editor.
-			// Use +LINE to restore saved position.
+			// Use +LINE to restore saved position, then the rest of the
+			// view where there is one.
+			view := m.editorView(article)
 			eCmd := fmt.Sprintf(":e %s", fpath)
-			if article.Progress > 0 {
-				eCmd = fmt.Sprintf(":e +%d %s", article.Progress, fpath)
+			if view.Line > 0 {
+				eCmd = fmt.Sprintf(":e +%d %s", view.Line, fpath)
 			}
 			cmd := exec.Command("tmux", "send-keys", "-t", m.tmuxPaneID,
 				eCmd, "Enter")
@@ -829,6 +1742,9 @@ func (m Model) openSelectedArticle() (tea.Model, tea.Cmd) {
 				// send-keys failed (pane might have just died), clear ID and fall through.
 				m.tmuxPaneID = ""
 			} else {
+				if restore := vimRestoreView(view); restore != "" {
+					_ = exec.Command("tmux", "send-keys", "-t", m.tmuxPaneID, ":"+restore, "Enter").Run()
+				}
 				return m, nil
 			}
 		}
@@ -839,38 +1755,59 @@ func (m Model) openSelectedArticle() (tea.Model, tea.Cmd) {
 	if shell == "" {
 		shell = "/bin/sh"
 	}
-	channel := fmt.Sprintf("shelf-editor-done-%d", os.Getpid())
+	m.tmuxSplits++
+	channel := fmt.Sprintf("shelf-editor-done-%d-%d", os.Getpid(), m.tmuxSplits)
 
 	editorCmd := fmt.Sprintf("%s %q", editor, fpath)
 	if isVimEditor(editor) {
-		editorCmd = vimEditorCommand(editor, fpath, m.positionFile, article.Progress)
+		editorCmd = vimEditorCommand(editor, fpath, m.positionFile, m.editorView(article), m.checkpoint)
 	}
-	splitCmd := exec.Command("tmux", "split-window", "-h", "-l", "63%",
+	// The first editor pane takes most of the window beside the list; in
+	// "new" mode, later ones halve the newest editor pane.
+	split := []string{"split-window", "-h", "-l", "63%"}
+	if m.tmuxNewPanes && len(m.tmuxPanes) > 0 {
+		split = []string{"split-window", "-h", "-l", "50%", "-t", m.tmuxPanes[len(m.tmuxPanes)-1]}
+	}
+	splitCmd := exec.Command("tmux", append(split,
 		"-P", "-F", "#{pane_id}",
 		shell, "-l", "-c",
-		fmt.Sprintf("%s; tmux wait-for -S %s", editorCmd, channel))
+		fmt.Sprintf("%s; tmux wait-for -S %s", editorCmd, channel))...)
 	out, err := splitCmd.Output()
 	if err != nil {
-		m.err = fmt.Errorf("tmux split-window: %w", err)
+		m.err = fmt.Errorf(m.msgs.text("status.tmux_split_failed"), err)
 		return m, nil
 	}
-	m.tmuxPaneID = strings.TrimSpace(string(out))
+	paneID := strings.TrimSpace(string(out))
+	finished := editorFinishedMsg{paneID: paneID}
+	if m.tmuxNewPanes {
+		// Each pane keeps its article, so its highlights can be synced
+		// whichever pane is closed first.
+		m.tmuxPanes = append(m.tmuxPanes, paneID)
+		finished.path = article.FilePath
+	} else {
+		m.tmuxPaneID = paneID
+	}
 
 	// Block in background until the editor exits.
-	return m, func() tea.Msg {
-		err := exec.Command("tmux", "wait-for", channel).Run()
-		return editorFinishedMsg{err: err}
+	wait := func() tea.Msg {
+		finished.err = exec.Command("tmux", "wait-for", channel).Run()
+		return finished
 	}
+	if m.checkpoint > 0 && isVimEditor(editor) && !m.checkpointing {
+		m.checkpointing = true
+		return m, tea.Batch(wait, m.checkpointProgress())
+	}
+	return m, wait
 }
 
-func (m Model) openArticleExecProcess(editor, fpath string, progress int) (tea.Model, tea.Cmd) {
+func (m Model) openArticleExecProcess(editor, fpath string, view state.EditorView) (tea.Model, tea.Cmd) {
 	shell := os.Getenv("SHELL")
 	if shell == "" {
 		shell = "/bin/sh"
 	}
 	editorCmd := fmt.Sprintf("%s %q", editor, fpath)
 	if isVimEditor(editor) {
-		editorCmd = vimEditorCommand(editor, fpath, m.positionFile, progress)
+		editorCmd = vimEditorCommand(editor, fpath, m.positionFile, view, m.checkpoint)
 	}
 	c := exec.Command(shell, "-l", "-c", editorCmd)
 	c.Stdin = os.Stdin
@@ -882,32 +1819,71 @@ func (m Model) openArticleExecProcess(editor, fpath string, progress int) (tea.M
 }
 
 // savePositionFromFile reads the vim cursor position file, updates the
-// article's progress in the store, and refreshes the article list.
+// article's progress in the store and its editor view in the app state,
+// and refreshes the article list.
 func (m *Model) savePositionFromFile() {
 	data, err := os.ReadFile(m.positionFile)
 	if err != nil {
 		return
 	}
 	os.Remove(m.positionFile)
-	// Format: absolutePath:lineNum
-	parts := strings.SplitN(strings.TrimSpace(string(data)), ":", 2)
-	if len(parts) != 2 {
-		return
-	}
-	lineNum, err := strconv.Atoi(parts[1])
-	if err != nil || lineNum <= 0 {
+	absPath, view, ok := parsePosition(strings.TrimSpace(string(data)))
+	if !ok {
 		return
 	}
-	absPath := parts[0]
 	for _, a := range m.store.List() {
 		if m.store.GetFilePath(a.FilePath) == absPath {
-			_ = m.store.UpdateProgress(a.FilePath, lineNum)
+			wasFinished := a.IsFinished()
+			if a.Progress != view.Line {
+				// Checkpoints while the reader stays put don't rewrite
+				// the file.
+				_ = m.store.UpdateProgress(a.FilePath, view.Line)
+			}
+			if a.Progress = view.Line; a.IsFinished() && !wasFinished {
+				m.appState.RecordFinish(time.Now())
+			}
+			if m.appState.EditorViews == nil {
+				m.appState.EditorViews = make(map[string]state.EditorView)
+			}
+			m.appState.EditorViews[a.FilePath] = view
+			m.saveState()
 			break
 		}
 	}
 	m.refreshArticles()
 }
 
+// parsePosition parses what vimWritePosition writes,
+// "absolutePath:line:col:topLine". The path may itself contain colons, so
+// the numbers are taken from the end.
+func parsePosition(s string) (absPath string, view state.EditorView, ok bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 4 {
+		return "", state.EditorView{}, false
+	}
+	nums := make([]int, 3)
+	for i, p := range parts[len(parts)-3:] {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			return "", state.EditorView{}, false
+		}
+		nums[i] = n
+	}
+	view = state.EditorView{Line: nums[0], Col: nums[1], TopLine: nums[2]}
+	return strings.Join(parts[:len(parts)-3], ":"), view, true
+}
+
+// syncHighlights refreshes the highlights.md of the article at filePath
+// from the ==highlight== markers in it, after the reader has had it open.
+func (m Model) syncHighlights(filePath string) {
+	if filePath == "" {
+		return
+	}
+	if err := m.store.SyncHighlights(filePath); err != nil {
+		m.logger.Warn("saving highlights", "path", filePath, "err", err)
+	}
+}
+
 func (m *Model) refreshArticles() {
 	if m.searchInput.Value() != "" {
 		m.articles = m.applyArchiveFilter(m.store.Search(m.searchInput.Value()))
@@ -917,18 +1893,28 @@ func (m *Model) refreshArticles() {
 	if m.cursor >= len(m.articles) {
 		m.cursor = max(0, len(m.articles)-1)
 	}
-	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	if m.showTags {
+		m.tags = m.store.Tags()
+		m.tagCursor = min(m.tagCursor, len(m.tags))
+	}
 }
 
-// calcVisibleItems returns the number of list items that fit on screen.
+// calcVisibleItems returns the number of list items that fit on screen, for
+// screens whose items take a title line and a description line.
 func (m Model) calcVisibleItems() int {
-	listHeight := m.height - 12 - m.helpGridHeight()
-	itemHeight := 3
-	visibleItems := listHeight / itemHeight
-	if visibleItems < 1 {
-		visibleItems = 1
-	}
-	return visibleItems
+	return listItemsThatFit(m.listHeight(), densityComfortable)
+}
+
+// listVisibleItems returns the number of articles that fit in the main list
+// at the configured density.
+func (m Model) listVisibleItems() int {
+	return listItemsThatFit(m.listHeight(), m.density)
+}
+
+// listHeight returns the number of lines available to list screens.
+func (m Model) listHeight() int {
+	return m.height - 12 - m.helpGridHeight() - (m.statusHeight() - 1)
 }
 
 // clampScroll adjusts scrollPos so cursor stays within the visible viewport.
@@ -970,7 +1956,8 @@ func titleFromURL(rawURL string) string {
 }
 
 func (m Model) applyArchiveFilter(articles []storage.ArticleMeta) []storage.ArticleMeta {
-	if m.showArchived {
+	// An explicit status:archived query shows archived articles regardless.
+	if m.showArchived || storage.ParseQuery(m.searchInput.Value()).Status == storage.StatusArchived {
 		return articles
 	}
 	var filtered []storage.ArticleMeta
@@ -982,36 +1969,46 @@ func (m Model) applyArchiveFilter(articles []storage.ArticleMeta) []storage.Arti
 	return filtered
 }
 
+// archiveSelectedArticle archives the selected article, first asking why
+// with archive_note, or unarchives it.
 func (m Model) archiveSelectedArticle() (tea.Model, tea.Cmd) {
 	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
 		return m, nil
 	}
 
 	article := m.articles[m.cursor]
-	tags := article.Tags
-
-	if article.IsArchived() {
-		// Remove "archived" tag
-		var newTags []string
-		for _, t := range tags {
-			if strings.ToLower(t) != "archived" {
-				newTags = append(newTags, t)
-			}
-		}
-		tags = newTags
-		if err := m.store.UpdateTags(article.FilePath, tags); err != nil {
-			m.err = err
-			return m, nil
+	if m.askArchiveNote && !article.IsArchived() {
+		m.state = stateArchiveNote
+		m.archiving = article
+		m.noteInput = m.noteInput.Reset()
+		var cmd tea.Cmd
+		m.noteInput, cmd = m.noteInput.Focus()
+		return m, cmd
+	}
+	return m.setArchived(article, !article.IsArchived(), "")
+}
+
+// setArchived archives or unarchives article, with note saying why if it's
+// archived and the reader gave one, keeping the cursor on it as it moves.
+func (m Model) setArchived(article storage.ArticleMeta, archived bool, note string) (tea.Model, tea.Cmd) {
+	desc := m.msgs.format("status.archived", article.Title)
+	if !archived {
+		desc = m.msgs.format("status.unarchived", article.Title)
+	}
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		if err := m.store.SetArchived(article.FilePath, archived); err != nil {
+			return err
 		}
-		m.statusMsg = fmt.Sprintf("Unarchived %q", article.Title)
-	} else {
-		tags = append(tags, "archived")
-		if err := m.store.UpdateTags(article.FilePath, tags); err != nil {
-			m.err = err
-			return m, nil
+		if archived && note != "" {
+			return m.store.SetArchiveNote(article.FilePath, note)
 		}
-		m.statusMsg = fmt.Sprintf("Archived %q", article.Title)
+		return nil
+	}); err != nil {
+		m.err = err
+		return m, nil
 	}
+	m.statusMsg = desc
 
 	m.refreshArticles()
 	// Move cursor to the article's new position in the list.
@@ -1021,35 +2018,188 @@ func (m Model) archiveSelectedArticle() (tea.Model, tea.Cmd) {
 			break
 		}
 	}
-	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.calcVisibleItems(), len(m.articles))
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	return m, nil
+}
+
+// pinSelectedArticle toggles the pin on the selected article, keeping the
+// cursor on it as it moves to or from the top of the list.
+func (m Model) pinSelectedArticle() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	desc := m.msgs.format("status.pinned", article.Title)
+	if article.IsPinned() {
+		desc = m.msgs.format("status.unpinned", article.Title)
+	}
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		return m.store.SetPinned(article.FilePath, !article.IsPinned())
+	}); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.statusMsg = desc
+
+	m.refreshArticles()
+	for i, a := range m.articles {
+		if a.FilePath == article.FilePath {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	return m, nil
+}
+
+// lockSelectedArticle locks the selected article against re-fetching and
+// overwriting, or unlocks it.
+func (m Model) lockSelectedArticle() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	desc := m.msgs.format("status.locked", article.Title)
+	if article.Locked {
+		desc = m.msgs.format("status.unlocked", article.Title)
+	}
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		return m.store.SetLocked(article.FilePath, !article.Locked)
+	}); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.statusMsg = desc
+	m.refreshArticles()
+	return m, nil
+}
+
+// clearSelectedWarnings removes the extraction warnings from the selected
+// article, saying what they were.
+func (m Model) clearSelectedWarnings() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	if len(article.Warnings) == 0 {
+		m.statusMsg = m.msgs.format("status.no_warnings", article.Title)
+		return m, nil
+	}
+	desc := m.msgs.format("status.warnings_cleared", article.Title, strings.Join(article.Warnings, "; "))
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		return m.store.ClearWarnings(article.FilePath)
+	}); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.statusMsg = desc
+	m.refreshArticles()
+	return m, nil
+}
+
+// isLocked reports whether the article at filePath is locked.
+func (m Model) isLocked(filePath string) bool {
+	for _, a := range m.store.List() {
+		if a.FilePath == filePath {
+			return a.Locked
+		}
+	}
+	return false
+}
+
+// moveSelectedArticle moves the selected article delta places in the manual
+// order, keeping the cursor on it.
+func (m Model) moveSelectedArticle(delta int) (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+	if !m.manualOrder {
+		m.statusMsg = m.msgs.text("status.sort_not_manual")
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	if err := m.store.Move(article.FilePath, delta); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.refreshArticles()
+	for i, a := range m.articles {
+		if a.FilePath == article.FilePath {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	return m, nil
+}
+
+// resetSelectedProgress clears the selected article's reading progress, so
+// it next opens at the top.
+func (m Model) resetSelectedProgress() (tea.Model, tea.Cmd) {
+	if len(m.articles) == 0 || m.cursor >= len(m.articles) {
+		return m, nil
+	}
+
+	article := m.articles[m.cursor]
+	desc := m.msgs.format("status.progress_reset", article.Title)
+	var err error
+	if m, err = m.recordEdit(article.FilePath, desc, func() error {
+		return m.store.UpdateProgress(article.FilePath, 0)
+	}); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.statusMsg = desc
+	m.refreshArticles()
 	return m, nil
 }
 
 // View renders the TUI.
 func (m Model) View() string {
 	if m.width == 0 {
-		return "Loading..."
+		return m.msgs.text("view.loading")
 	}
 
 	var sb strings.Builder
 
 	// Header
 	filtered := len(m.articles)
-	sb.WriteString(m.styles.Header.Render("Articles"))
+	sb.WriteString(m.styles.Header.Render(m.msgs.text("header.articles")))
+	if m.activeSearch != "" {
+		sb.WriteString(m.styles.Header.Render(" › " + m.activeSearch))
+	}
 	if m.showArchived {
-		sb.WriteString(m.styles.Muted.Render(" (+archived)"))
+		sb.WriteString(m.styles.Muted.Render(" " + m.msgs.text("header.with_archived")))
+	}
+	if m.endpointErr != nil {
+		sb.WriteString(m.styles.Error.Render(" · " + m.msgs.text("header.endpoint_unreachable")))
 	}
-	showCounts := m.state != stateAddURL && m.state != stateLoading && m.state != stateConfirmOverwrite && m.state != stateConfirmDelete && m.state != stateGatheringTabs && m.state != stateImporting && m.state != stateSafariWaiting
+	// Counts show on the screens that list articles; any other, such as a
+	// prompt or progress screen, hides them.
+	showCounts := !m.scanning && slices.Contains([]State{stateList, stateSearch, stateTags, stateHelp, statePeek}, m.state)
 	if showCounts {
 		if m.searchInput.Value() != "" {
 			total := len(m.applyArchiveFilter(m.store.List()))
 			if filtered == 0 {
-				sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (0 of %d)", total)))
+				sb.WriteString(m.styles.Muted.Render(" " + m.msgs.format("header.count_none", total)))
 			} else {
-				sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (%d of %d of %d)", m.cursor+1, filtered, total)))
+				sb.WriteString(m.styles.Muted.Render(" " + m.msgs.format("header.count_filtered", m.cursor+1, filtered, total)))
+				if m.matchCounts {
+					if counts := m.renderMatchCounts(); counts != "" {
+						sb.WriteString(m.styles.Muted.Render(" · " + counts))
+					}
+				}
 			}
 		} else {
-			sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (%d of %d)", m.cursor+1, filtered)))
+			sb.WriteString(m.styles.Muted.Render(" " + m.msgs.format("header.count", m.cursor+1, filtered)))
 		}
 		// Show archived count hint when archived articles are hidden.
 		if !m.showArchived {
@@ -1061,9 +2211,27 @@ func (m Model) View() string {
 				}
 			}
 			if archivedCount > 0 {
-				sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" · %d archived", archivedCount)))
+				sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("header.archived_count", archivedCount)))
 			}
 		}
+		if n := m.newCount(); n > 0 {
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("header.new_count", n)))
+		}
+		if n := len(m.appState.ImportQueue); n > 0 {
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("header.import_paused", n)))
+		}
+		switch m.overwriteMemo {
+		case "always":
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.text("header.overwriting_all")))
+		case "never":
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.text("header.skipping_saved")))
+		}
+		if streak := m.appState.Streak(time.Now()); streak > 0 {
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("header.streak", streak)))
+		}
+		if m.totalSize > 0 {
+			sb.WriteString(m.styles.Muted.Render(" · " + m.msgs.format("header.total_size", storage.FormatSize(m.totalSize))))
+		}
 	}
 	sb.WriteString("\n\n")
 
@@ -1071,8 +2239,14 @@ func (m Model) View() string {
 	switch m.state {
 	case stateAddURL, stateLoading, stateConfirmOverwrite, stateSafariWaiting:
 		sb.WriteString(m.urlInput.View())
-	case stateGatheringTabs, stateImporting:
-		// No input bar during import.
+	case stateNewNote:
+		sb.WriteString(m.titleInput.View())
+	case stateArchiveNote:
+		sb.WriteString(m.noteInput.View())
+	case stateRenameTag:
+		sb.WriteString(m.tagInput.View())
+	case stateGatheringTabs, stateResolvingTitles, stateImporting, stateExporting, stateImportPreview, stateImportFailures, stateSavedSearches, stateImages, statePreview, statePermissions:
+		// No input bar during import or while picking from a list.
 	default:
 		sb.WriteString(m.searchInput.View())
 	}
@@ -1082,84 +2256,105 @@ func (m Model) View() string {
 	switch m.state {
 	case stateAddURL:
 		// Nothing below the URL input bar
+	case stateNewNote:
+		sb.WriteString(m.styles.Muted.Render(m.msgs.text("view.new_note")))
+	case stateArchiveNote:
+		sb.WriteString(m.styles.Muted.Render(m.msgs.format("view.archive_note", m.archiving.Title)))
+	case stateRenameTag:
+		sb.WriteString(m.styles.Muted.Render(m.msgs.format("view.rename_tag", m.renaming, len(m.store.ListByTag(m.renaming)))))
 	case stateLoading:
 		sb.WriteString(m.spinner.View())
-		sb.WriteString(" Fetching article...")
-	case stateConfirmDelete:
+		sb.WriteString(" " + m.msgs.text("view.fetching"))
+	case stateConfirmDelete, stateConfirmQuit, stateConfirmDeleteTag, stateConfirmResetTag, stateConfirmResume:
 		// Show the article list with the confirmation inline as a status message.
 		sb.WriteString(m.renderList())
 	case stateConfirmOverwrite:
 		if m.pendingResult != nil {
-			sb.WriteString(fmt.Sprintf("Article %q already exists. Overwrite?", m.pendingResult.Title))
+			sb.WriteString(m.msgs.format("view.confirm_overwrite", m.pendingResult.Title))
 		} else {
-			sb.WriteString(fmt.Sprintf("Already saved as %q. Re-fetch?", m.overwriteTitle))
+			sb.WriteString(m.msgs.format("view.confirm_refetch", m.overwriteTitle))
 		}
+		sb.WriteString(m.renderOverwriteDetails())
 	case stateSafariWaiting:
-		sb.WriteString("Safari opened — complete any verification, then press Enter...")
+		sb.WriteString(m.msgs.text("view.safari_waiting"))
 	case stateGatheringTabs:
 		sb.WriteString(m.spinner.View())
-		sb.WriteString(" Gathering Safari tabs...")
-	case stateImporting:
+		sb.WriteString(" " + m.msgs.text("view.gathering_tabs"))
+	case stateResolvingTitles:
 		sb.WriteString(m.spinner.View())
+		sb.WriteString(" " + m.msgs.format("view.resolving_titles", m.titlesTotal-m.titlesPending, m.titlesTotal))
+	case stateImporting:
 		saved := m.importDone - m.importSkipped - len(m.importErrors)
-		sb.WriteString(fmt.Sprintf(" Importing %d/%d...", m.importDone+1, m.importTotal))
+		switch {
+		case m.importPaused && len(m.importInFlight) > 0:
+			sb.WriteString(m.spinner.View())
+			sb.WriteString(" " + m.msgs.format("view.import_pausing", len(m.importQueue), len(m.importInFlight)))
+		case m.importPaused:
+			sb.WriteString(m.msgs.format("view.import_paused", len(m.importQueue)))
+		default:
+			sb.WriteString(m.spinner.View())
+			sb.WriteString(" " + m.msgs.format("view.importing", min(m.importDone+1, m.importTotal), m.importTotal))
+		}
 		if saved > 0 || m.importSkipped > 0 {
 			details := []string{}
 			if saved > 0 {
-				details = append(details, fmt.Sprintf("%d saved", saved))
+				details = append(details, m.msgs.format("view.import_saved", saved))
 			}
 			if m.importSkipped > 0 {
-				details = append(details, fmt.Sprintf("%d skipped", m.importSkipped))
+				details = append(details, m.msgs.format("view.import_skipped", m.importSkipped))
 			}
 			if len(m.importErrors) > 0 {
-				details = append(details, fmt.Sprintf("%d failed", len(m.importErrors)))
+				details = append(details, m.msgs.format("view.import_failed", len(m.importErrors)))
 			}
 			sb.WriteString(" " + strings.Join(details, ", "))
 		}
-	case stateHelp:
+	case stateExporting:
+		sb.WriteString(m.spinner.View())
+		sb.WriteString(" " + m.msgs.format("view.exporting", min(m.exportDone+1, len(m.exportPaths)), len(m.exportPaths)))
+	case stateImportPreview:
+		sb.WriteString(m.renderImportPreview())
+	case stateImportFailures:
+		sb.WriteString(m.renderImportFailures())
+	case stateSavedSearches:
+		sb.WriteString(m.renderSavedSearches())
+	case stateImages:
+		sb.WriteString(m.renderImages())
+	case statePreview:
+		sb.WriteString(m.renderPreview())
+	case statePermissions:
+		sb.WriteString(m.renderPermissions())
+	case stateHelp, stateTags:
 		sb.WriteString(m.renderList())
 	default:
 		sb.WriteString(m.renderList())
 	}
 
 	// Status/error message — placed just above the footer help text.
-	var statusLine string
-	if m.state == stateConfirmDelete {
-		usable := m.width - 4
-		title := m.pendingDeleteTitle
-		full := fmt.Sprintf("Delete %q? This cannot be undone.", title)
-		if len(full) > usable && usable > 20 {
-			overhead := len("Delete \"\"? This cannot be undone.")
-			maxTitle := usable - overhead
-			if maxTitle > 3 {
-				title = truncateString(title, maxTitle)
-				full = fmt.Sprintf("Delete %q? This cannot be undone.", title)
-			} else {
-				// Title won't fit; drop it entirely.
-				full = "Delete this article?"
-			}
-		}
-		statusLine = m.styles.Error.Render(full)
-	} else if m.err != nil {
-		statusLine = m.styles.Error.Render(fmt.Sprintf("Error: %v", m.err))
-	} else if m.statusMsg != "" {
-		statusLine = m.styles.Muted.Render(m.statusMsg)
-	}
+	statusLine := m.renderStatus()
+	statusLines := strings.Count(statusLine, "\n") + 1
 
-	// Build the help grid (shown above footer in stateHelp).
+	// Build the help grid (shown above footer in stateHelp), or the quick
+	// peek in its place.
 	var helpGrid string
 	var helpGridLines int
-	if m.state == stateHelp {
+	if m.state == statePeek {
+		contentHeight0 := strings.Count(sb.String(), "\n") + 1
+		available := m.height - contentHeight0 - 2 - 1 - statusLines // App padding, footer
+		if maxLines := min(available-2, m.peekLines()); maxLines > 0 {
+			helpGrid = m.renderPeek(maxLines)
+			helpGridLines = maxLines + 2 // lines + separator + blank
+		}
+	} else if m.state == stateHelp {
 		// Calculate how many help grid rows fit in the remaining space.
 		content0 := sb.String()
 		contentHeight0 := strings.Count(content0, "\n") + 1
 		appPaddingV0 := 2
-		footerLines0 := 2
+		footerLines0 := 1 + statusLines
 		// Available lines for the help section (separator + blank + rows).
 		available := m.height - contentHeight0 - appPaddingV0 - footerLines0
 		maxRows := available - 2 // reserve 2 for separator + blank line
-		if maxRows > 6 {
-			maxRows = 6
+		if rows := m.helpRows(); maxRows > rows {
+			maxRows = rows
 		}
 		if maxRows > 0 {
 			helpGrid = m.renderHelpOverlay(maxRows)
@@ -1171,7 +2366,8 @@ func (m Model) View() string {
 	content := sb.String()
 	contentHeight := strings.Count(content, "\n") + 1
 	appPaddingV := 2 // Top + bottom padding from App style
-	footerLines := 2 // Status/blank line + help text
+	// Status (or blank) lines + help text.
+	footerLines := 1 + statusLines
 	remaining := m.height - contentHeight - appPaddingV - footerLines - helpGridLines
 	if remaining > 0 {
 		sb.WriteString(strings.Repeat("\n", remaining))
@@ -1196,19 +2392,35 @@ func (m Model) View() string {
 	return m.styles.App.Render(sb.String())
 }
 
+// renderList renders the article list, beside the tag sidebar if it's
+// shown.
 func (m Model) renderList() string {
+	list := m.renderArticles()
+	if !m.showTags {
+		return list
+	}
+	height := max(m.listHeight(), strings.Count(list, "\n")+1)
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.renderTagSidebar(height), list)
+}
+
+func (m Model) renderArticles() string {
+	if m.scanning {
+		return m.renderScanning()
+	}
 	if len(m.articles) == 0 {
 		if m.searchInput.Value() != "" {
-			return renderNoResults(m.searchInput.Value(), m.styles)
+			return renderNoResults(m.searchInput.Value(), m.msgs, m.styles)
 		}
-		return renderEmptyState(m.styles)
+		return renderEmptyState(m.msgs, m.styles)
 	}
 
 	var sb strings.Builder
 
-	// Use the pre-computed scroll position maintained by Update.
-	visibleItems := m.calcVisibleItems()
-	start := m.scrollPos
+	// Use the pre-computed scroll position maintained by Update, kept on
+	// the cursor if a long status message has since taken some of the
+	// list's lines.
+	visibleItems := m.listVisibleItems()
+	start := clampScroll(m.cursor, m.scrollPos, visibleItems, len(m.articles))
 	end := start + visibleItems
 	if end > len(m.articles) {
 		end = len(m.articles)
@@ -1225,29 +2437,47 @@ func (m Model) renderList() string {
 		}
 	}
 
-	contentWidth := m.width - 4
+	contentWidth := m.listWidth()
+	gap := "\n\n"
+	renderItem := renderArticleItem
+	if m.density == densityCompact {
+		gap = "\n"
+		renderItem = renderCompactArticleItem
+	}
 
 	for i := start; i < end; i++ {
 		if i > start {
 			if i == archiveBoundary {
 				// Draw a labeled separator between non-archived and archived groups.
-				label := " archived "
-				dashCount := contentWidth - len(label)
+				label := " " + m.msgs.text("list.archived_separator") + " "
+				dashCount := contentWidth - runewidth.StringWidth(label)
 				if dashCount < 2 {
 					dashCount = 2
 				}
 				left := dashCount / 2
 				right := dashCount - left
 				sep := strings.Repeat("─", left) + label + strings.Repeat("─", right)
-				sb.WriteString("\n\n")
+				sb.WriteString(gap)
 				sb.WriteString(m.styles.Muted.Render(sep))
-				sb.WriteString("\n\n")
+				sb.WriteString(gap)
 			} else {
-				sb.WriteString("\n\n")
+				sb.WriteString(gap)
 			}
 		}
 		selected := i == m.cursor
-		sb.WriteString(renderArticleItem(m.articles[i], selected, contentWidth, m.styles))
+		article := m.articles[i]
+		article.FileSize = m.store.ArticleSize(article)
+		if !m.showSection {
+			article.SourceSection = ""
+		}
+		if m.isNew(article) {
+			article.Title = newGlyph + cmp.Or(article.Title, m.msgs.text("view.untitled"))
+		}
+		mark := ""
+		if m.domainMarks {
+			mark = domainMark(article.SourceDomain)
+		}
+		sb.WriteString(renderItem(article, mark, selected, contentWidth, m.timeFormat, m.msgs, m.ellipsis, m.styles))
 	}
 
 	return sb.String()
@@ -1258,62 +2488,105 @@ func (m Model) renderHelp() string {
 
 	switch m.state {
 	case stateAddURL:
-		parts = append(parts, "[enter] fetch", "[ctrl+c] clear", "[esc] cancel")
+		parts = append(parts, m.msgs.text("footer.fetch"), m.msgs.text("footer.ctrlc_clear"), m.msgs.text("footer.cancel"))
+	case stateNewNote:
+		parts = append(parts, m.msgs.text("footer.create"), m.msgs.text("footer.ctrlc_clear"), m.msgs.text("footer.cancel"))
+	case stateArchiveNote:
+		parts = append(parts, m.msgs.text("footer.archive"), m.msgs.text("footer.ctrlc_clear"), m.msgs.text("footer.cancel"))
+	case stateRenameTag:
+		parts = append(parts, m.msgs.text("footer.rename"), m.msgs.text("footer.ctrlc_clear"), m.msgs.text("footer.cancel"))
 	case stateSearch:
-		parts = append(parts, "[enter] done", "[ctrl+c] clear", "[esc] cancel")
+		parts = append(parts, m.msgs.text("footer.enter_done"))
+		if m.searchHistory != nil {
+			parts = append(parts, m.msgs.text("footer.history"))
+		}
+		parts = append(parts, m.msgs.text("footer.ctrlc_clear"), m.msgs.text("footer.cancel"))
 	case stateLoading:
-		parts = append(parts, "[esc] cancel")
+		parts = append(parts, m.msgs.text("footer.cancel"))
 	case stateConfirmDelete:
-		parts = append(parts, "[y] delete", "[n] cancel")
+		parts = append(parts, m.msgs.text("footer.confirm_delete"), m.msgs.text("footer.n_cancel"))
+	case stateConfirmQuit:
+		parts = append(parts, m.msgs.text("footer.confirm_quit"), m.msgs.text("footer.n_cancel"))
+	case stateConfirmDeleteTag:
+		parts = append(parts, m.msgs.text("footer.confirm_untag"), m.msgs.text("footer.n_cancel"))
+	case stateConfirmResetTag:
+		parts = append(parts, m.msgs.text("footer.confirm_reset"), m.msgs.text("footer.n_cancel"))
+	case stateConfirmResume:
+		parts = append(parts, m.msgs.text("footer.resume_import"), m.msgs.text("footer.new_import"), m.msgs.text("footer.cancel"))
 	case stateConfirmOverwrite:
-		parts = append(parts, "[y] overwrite", "[n] cancel")
+		parts = append(parts, m.msgs.text("footer.confirm_overwrite"), m.msgs.text("footer.overwrite_always"), m.msgs.text("footer.overwrite_never"), m.msgs.text("footer.n_cancel"))
 	case stateSafariWaiting:
-		parts = append(parts, "[enter] extract", "[esc] cancel")
+		parts = append(parts, m.msgs.text("footer.extract"), m.msgs.text("footer.cancel"))
 	case stateGatheringTabs:
-		parts = append(parts, "[esc] cancel")
+		parts = append(parts, m.msgs.text("footer.cancel"))
+	case stateResolvingTitles:
+		parts = append(parts, m.msgs.text("footer.skip_titles"), m.msgs.text("footer.ctrlc_cancel"))
 	case stateImporting:
-		parts = append(parts, "[esc] cancel")
-	case stateHelp:
-		parts = append(parts, "press any key to close")
+		if m.importPaused {
+			parts = append(parts, m.msgs.text("footer.resume"), m.msgs.text("footer.resume_later"), m.msgs.text("footer.ctrlc_cancel"))
+		} else {
+			parts = append(parts, m.msgs.text("footer.pause"), m.msgs.text("footer.cancel"))
+		}
+	case stateExporting:
+		parts = append(parts, m.msgs.text("footer.cancel"))
+	case stateImportPreview:
+		parts = append(parts, m.msgs.text("footer.import_confirm"), m.msgs.text("footer.edit"), m.msgs.text("footer.cancel"))
+	case stateImportFailures:
+		parts = append(parts, m.msgs.text("footer.select"), m.msgs.text("footer.retry"), m.msgs.text("footer.copy"), m.msgs.text("footer.esc_done"))
+		if m.keys.Probe.Enabled() {
+			parts = append(parts, m.msgs.text("footer.debug"))
+		}
+	case stateSavedSearches:
+		parts = append(parts, m.msgs.text("footer.apply"), m.msgs.text("footer.cancel"))
+	case stateTags:
+		parts = append(parts, m.msgs.text("footer.filter"), m.msgs.text("footer.rename_tag"), m.msgs.text("footer.delete_tag"), m.msgs.text("footer.reset_progress"), m.msgs.text("footer.focus_list"), m.msgs.text("footer.hide_tags"), m.msgs.text("footer.back"))
+	case stateImages:
+		parts = append(parts, m.msgs.text("footer.open"), m.msgs.text("footer.preview"), m.msgs.text("footer.localize"), m.msgs.text("footer.back"))
+	case statePreview:
+		parts = append(parts, m.msgs.text("footer.scroll"), m.msgs.text("footer.top_bottom"), m.msgs.text("footer.back"))
+	case statePermissions:
+		parts = append(parts, m.msgs.text("footer.open_settings"), m.msgs.text("footer.recheck"), m.msgs.text("footer.continue"), m.msgs.text("footer.cancel"))
+	case stateHelp, statePeek:
+		parts = append(parts, m.msgs.text("footer.close_help"))
 	default:
-		archiveLabel := "[x/X] archive/show"
+		archiveLabel := m.msgs.text("footer.archive_show")
 		if len(m.articles) > 0 && m.cursor < len(m.articles) && m.articles[m.cursor].IsArchived() {
-			archiveLabel = "[x/X] unarchive/hide"
+			archiveLabel = m.msgs.text("footer.unarchive_hide")
 		}
 		if m.showArchived {
-			archiveLabel = "[x/X] archive/hide"
+			archiveLabel = m.msgs.text("footer.archive_hide")
 			if len(m.articles) > 0 && m.cursor < len(m.articles) && m.articles[m.cursor].IsArchived() {
-				archiveLabel = "[x/X] unarchive/hide"
+				archiveLabel = m.msgs.text("footer.unarchive_hide")
 			}
 		}
 		parts = append(parts,
-			"[a]dd URL",
-			"[i]mport",
-			"[enter] open",
-			"[d]elete",
+			m.msgs.text("footer.add"),
+			m.msgs.text("footer.import"),
+			m.msgs.text("footer.open"),
+			m.msgs.text("footer.delete"),
 			archiveLabel,
-			"[/] search",
-			"[r/R]efetch",
-			"[?] help",
-			"[q]uit",
+			m.msgs.text("footer.search"),
+			m.msgs.text("footer.refetch"),
+			m.msgs.text("footer.help"),
+			m.msgs.text("footer.quit"),
 		)
 	}
 
 	usable := m.width - 4 // account for App padding
 	sep := "  "
 	result := strings.Join(parts, sep)
-	if len(result) > usable && usable > 0 {
+	if runewidth.StringWidth(result) > usable && usable > 0 {
 		// Try single-space separator first.
 		sep = " "
 		result = strings.Join(parts, sep)
 	}
-	if len(result) > usable && usable > 0 {
+	if runewidth.StringWidth(result) > usable && usable > 0 {
 		// Drop items from the end (least important) until it fits,
 		// but always keep the last item (quit/cancel).
 		for len(parts) > 2 {
 			parts = append(parts[:len(parts)-2], parts[len(parts)-1])
 			result = strings.Join(parts, sep)
-			if len(result) <= usable {
+			if runewidth.StringWidth(result) <= usable {
 				break
 			}
 		}
@@ -1327,50 +2600,94 @@ func (m Model) helpGridHeight() int {
 	if m.state != stateHelp {
 		return 0
 	}
-	// 1 separator line + 1 blank line + 6 keybinding rows = 8.
-	return 8
+	// 1 separator line + 1 blank line + keybinding rows.
+	return 2 + m.helpRows()
 }
 
-func (m Model) renderHelpOverlay(maxRows int) string {
-	type entry struct{ key, desc string }
-
-	col1 := []entry{
-		{"j / ↓", "move down"},
-		{"k / ↑", "move up"},
-		{"g / Home", "go to top"},
-		{"G / End", "go to bottom"},
-	}
-	col2 := []entry{
-		{"Enter", "open in editor"},
-		{"a", "add URL"},
-		{"d", "delete article"},
-		{"/", "search articles"},
-		{"i", "import from Safari"},
-	}
-	col3 := []entry{
-		{"x", "archive / unarchive"},
-		{"X", "show / hide archived"},
-		{"r", "re-fetch article"},
-		{"R", "re-fetch via Safari"},
-		{"?", "show this help"},
-		{"q", "quit"},
-	}
-
-	// Find max rows across columns.
-	rows := len(col1)
-	if len(col2) > rows {
-		rows = len(col2)
-	}
-	if len(col3) > rows {
-		rows = len(col3)
+// helpEntry is a single key and its description in the help grid.
+type helpEntry struct{ key, desc string }
+
+// helpColumns returns the three columns of the help grid.
+func (m Model) helpColumns() [3][]helpEntry {
+	deleteKey := "d"
+	if m.deleteStyle == "dd" {
+		deleteKey = "dd"
+	}
+
+	col1 := []helpEntry{
+		{"j / ↓", m.msgs.text("help.down")},
+		{"k / ↑", m.msgs.text("help.up")},
+		{"g / Home", m.msgs.text("help.top")},
+		{"G / End", m.msgs.text("help.bottom")},
+		{"f<letters>", m.msgs.text("help.find")},
+		{"t", m.msgs.text("help.tags")},
+		{"Tab", m.msgs.text("help.focus_tags")},
+		{m.keys.RenameTag.Help().Key + " (tags)", m.msgs.text("help.rename_tag")},
+		{"d (tags)", m.msgs.text("help.delete_tag")},
+	}
+	col2 := []helpEntry{
+		{"Enter", m.msgs.format("help.open", m.openAction)},
+		{"E/v/o/p", m.msgs.text("help.open_with")},
+		{"O", m.msgs.text("help.open_data_dir")},
+		{"F", m.msgs.text("help.reveal")},
+		{"a", m.msgs.text("help.add")},
+		{"c", m.msgs.text("help.save_tab")},
+		{"N", m.msgs.text("help.new_note")},
+		{deleteKey, m.msgs.text("help.delete")},
+		{"/", m.msgs.text("help.search")},
+		{"s", m.msgs.text("help.saved_searches")},
+		{"i", m.msgs.text("help.import")},
+		{"w", m.msgs.text("help.import_window")},
+		{m.keys.PauseImport.Help().Key + " (import)", m.msgs.text("help.pause_import")},
+		{"yy/yb/yu", m.msgs.text("help.yank")},
+		{">", m.msgs.text("help.export")},
+	}
+	col3 := []helpEntry{
+		{"x", m.msgs.text("help.archive")},
+		{"X", m.msgs.text("help.show_archived")},
+		{"P", m.msgs.text("help.pin")},
+		{"L", m.msgs.text("help.lock")},
+		{"W", m.msgs.text("help.clear_warnings")},
+		{"K / J", m.msgs.text("help.move")},
+		{"0", m.msgs.text("help.reset_progress")},
+		{"u / ctrl+r", m.msgs.text("help.undo")},
+		{"r", m.msgs.text("help.refetch")},
+		{"R", m.msgs.text("help.refetch_safari")},
+		{"I", m.msgs.text("help.images")},
+		{"n", m.msgs.text("help.notes")},
+		{"space", m.msgs.text("help.peek")},
+		{m.keys.ShowError.Help().Key, m.msgs.text("help.show_error")},
+		{"?", m.msgs.text("help.help")},
+		{"q", m.msgs.text("help.quit")},
+	}
+	if m.keys.Probe.Enabled() {
+		col3 = append(col3, helpEntry{m.keys.Probe.Help().Key, m.msgs.text("help.debug")})
+	}
+	for _, mac := range m.macros {
+		col1 = append(col1, helpEntry{mac.Key, strings.Join(mac.Actions, ", ")})
+	}
+	return [3][]helpEntry{col1, col2, col3}
+}
+
+// helpRows returns the number of rows in the full help grid.
+func (m Model) helpRows() int {
+	rows := 0
+	for _, col := range m.helpColumns() {
+		rows = max(rows, len(col))
 	}
+	return rows
+}
+
+func (m Model) renderHelpOverlay(maxRows int) string {
+	cols := m.helpColumns()
+	rows := m.helpRows()
 	if maxRows > 0 && rows > maxRows {
 		rows = maxRows
 	}
 
 	// Calculate key display width per column (for alignment).
 	sw := runewidth.StringWidth
-	keyWidth := func(col []entry) int {
+	keyWidth := func(col []helpEntry) int {
 		w := 0
 		for _, e := range col {
 			if sw(e.key) > w {
@@ -1379,15 +2696,10 @@ func (m Model) renderHelpOverlay(maxRows int) string {
 		}
 		return w
 	}
-	kw1 := keyWidth(col1)
-	kw2 := keyWidth(col2)
-	kw3 := keyWidth(col3)
-
-	cols := [3][]entry{col1, col2, col3}
-	kws := [3]int{kw1, kw2, kw3}
+	kws := [3]int{keyWidth(cols[0]), keyWidth(cols[1]), keyWidth(cols[2])}
 
 	// Compute max description display width per column for alignment.
-	descWidth := func(col []entry) int {
+	descWidth := func(col []helpEntry) int {
 		w := 0
 		for _, e := range col {
 			if sw(e.desc) > w {
@@ -1396,7 +2708,7 @@ func (m Model) renderHelpOverlay(maxRows int) string {
 		}
 		return w
 	}
-	dws := [3]int{descWidth(col1), descWidth(col2), descWidth(col3)}
+	dws := [3]int{descWidth(cols[0]), descWidth(cols[1]), descWidth(cols[2])}
 	colGap := 4 // gap between columns
 
 	indent := "  "
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoLimit is the number of changes u can undo.
+const undoLimit = 50
+
+// undoOp is a change to an article that u undoes and ctrl+r redoes: an
+// edit to its front matter, restored from the fields it changed as they
+// were before and after, or a delete, which moved it to the trash.
+type undoOp struct {
+	desc     string // the status shown when it was done, e.g. `Archived "Essay"`
+	filePath string
+	before   map[string]string // fields the edit changed, as they were; "" if added
+	after    map[string]string // the same fields after the edit; "" if removed
+	trashed  string            // for a delete, where the article is in the trash
+}
+
+// recordEdit runs edit, a change to the front matter of the article at
+// filePath, and records it as undoable under desc. Only the fields it
+// changes are recorded, so undoing it keeps later changes to the others,
+// e.g. progress saved from the editor.
+func (m Model) recordEdit(filePath, desc string, edit func() error) (Model, error) {
+	before, err := m.store.FrontMatterFields(filePath)
+	if err != nil {
+		return m, err
+	}
+	if err := edit(); err != nil {
+		return m, err
+	}
+	after, err := m.store.FrontMatterFields(filePath)
+	if err != nil {
+		return m, err
+	}
+	op := undoOp{desc: desc, filePath: filePath, before: map[string]string{}, after: map[string]string{}}
+	for key, field := range before {
+		if after[key] != field {
+			op.before[key], op.after[key] = field, after[key]
+		}
+	}
+	for key, field := range after {
+		if _, ok := before[key]; !ok {
+			op.before[key], op.after[key] = "", field
+		}
+	}
+	if len(op.before) > 0 {
+		m = m.pushUndo(op)
+	}
+	return m, nil
+}
+
+// pushUndo records a new change, dropping the oldest past undoLimit. Once
+// something new is done, what was undone can't be redone.
+func (m Model) pushUndo(op undoOp) Model {
+	m.undoStack = append(m.undoStack, op)
+	if len(m.undoStack) > undoLimit {
+		m.undoStack = m.undoStack[len(m.undoStack)-undoLimit:]
+	}
+	m.redoStack = nil
+	return m
+}
+
+// undo reverts the most recent change.
+func (m Model) undo() (tea.Model, tea.Cmd) {
+	if len(m.undoStack) == 0 {
+		m.statusMsg = m.msgs.text("status.nothing_to_undo")
+		return m, nil
+	}
+	op := m.undoStack[len(m.undoStack)-1]
+	var err error
+	if op.trashed != "" {
+		err = m.store.Untrash(op.trashed, op.filePath)
+	} else {
+		err = m.store.SetFrontMatterFields(op.filePath, op.before)
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, op)
+	m.statusMsg = m.msgs.format("status.undid", op.desc)
+	return m.selectAfterUndo(op.filePath), nil
+}
+
+// redo makes the most recently undone change again.
+func (m Model) redo() (tea.Model, tea.Cmd) {
+	if len(m.redoStack) == 0 {
+		m.statusMsg = m.msgs.text("status.nothing_to_redo")
+		return m, nil
+	}
+	op := m.redoStack[len(m.redoStack)-1]
+	var err error
+	if op.trashed != "" {
+		op.trashed, err = m.store.Trash(op.filePath)
+	} else {
+		err = m.store.SetFrontMatterFields(op.filePath, op.after)
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, op)
+	m.statusMsg = m.msgs.format("status.redid", op.desc)
+	return m.selectAfterUndo(op.filePath), nil
+}
+
+// selectAfterUndo refreshes the list after an undo or redo, with the
+// cursor on the article changed if it's still listed.
+func (m Model) selectAfterUndo(filePath string) Model {
+	m.refreshArticles()
+	for i, a := range m.articles {
+		if a.FilePath == filePath {
+			m.cursor = i
+			break
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.articles)-1, 0))
+	m.scrollPos = clampScroll(m.cursor, m.scrollPos, m.listVisibleItems(), len(m.articles))
+	return m
+}
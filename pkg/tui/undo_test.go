@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/irfansharif/shelf/pkg/storage"
+)
+
+func TestUndo(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContent("Essay", "---\ntitle: Essay\ntags: rust\n---\n\nBody.\n", nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:       store,
+		keys:        DefaultKeyMap(),
+		styles:      DefaultStyles(),
+		searchInput: NewSearchInput(DefaultStyles()),
+		appState:    testState(t),
+		width:       80,
+		height:      30,
+	}
+	m.refreshArticles()
+	press := func(k tea.KeyMsg) {
+		t.Helper()
+		next, cmd := m.Update(k)
+		m = next.(Model)
+		if cmd != nil {
+			next, _ = m.Update(cmd())
+			m = next.(Model)
+		}
+	}
+	key := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	redo := tea.KeyMsg{Type: tea.KeyCtrlR}
+	article := func() storage.ArticleMeta {
+		t.Helper()
+		list := store.List()
+		if len(list) != 1 {
+			t.Fatalf("%d articles, want 1", len(list))
+		}
+		return list[0]
+	}
+
+	press(key("P"))
+	press(key("x"))
+	if a := article(); !a.IsPinned() || !a.IsArchived() {
+		t.Fatalf("after P, x: pinned %v, archived %v", a.IsPinned(), a.IsArchived())
+	}
+
+	// Progress saved from the editor since isn't undone with them.
+	if err := store.UpdateProgress(article().FilePath, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undo goes back a change at a time, saying what it undid.
+	press(key("u"))
+	if a := article(); !a.IsPinned() || a.IsArchived() || m.statusMsg != `Undid: Archived "Essay"` {
+		t.Fatalf("after u: pinned %v, archived %v, status %q", a.IsPinned(), a.IsArchived(), m.statusMsg)
+	}
+	if a := article(); a.Progress != 7 {
+		t.Fatalf("after u: progress %d, want 7", a.Progress)
+	}
+	press(key("u"))
+	if a := article(); a.IsPinned() || len(a.Tags) != 1 || a.Tags[0] != "rust" {
+		t.Fatalf("after u, u: pinned %v, tags %q", a.IsPinned(), a.Tags)
+	}
+	press(key("u"))
+	if m.statusMsg != "Nothing to undo" {
+		t.Fatalf("undo with nothing left: status %q", m.statusMsg)
+	}
+
+	// Redo makes them again, in order.
+	press(redo)
+	if a := article(); !a.IsPinned() || m.statusMsg != `Redid: Pinned "Essay"` {
+		t.Fatalf("after ctrl+r: pinned %v, status %q", a.IsPinned(), m.statusMsg)
+	}
+	// A new change can't be followed by redoing an older one.
+	press(key("L"))
+	press(redo)
+	if a := article(); a.IsArchived() || m.statusMsg != "Nothing to redo" {
+		t.Fatalf("redo after a new change: archived %v, status %q", a.IsArchived(), m.statusMsg)
+	}
+
+	// Deleted articles come back from the trash.
+	press(key("d"))
+	press(key("y"))
+	if list := store.List(); len(list) != 0 {
+		t.Fatalf("after delete: %d articles", len(list))
+	}
+	press(key("u"))
+	if a := article(); !a.Locked || len(m.articles) != 1 || m.statusMsg != `Undid: Deleted "Essay"` {
+		t.Fatalf("after undoing delete: locked %v, %d listed, status %q", a.Locked, len(m.articles), m.statusMsg)
+	}
+	press(redo)
+	if list := store.List(); len(list) != 0 {
+		t.Fatalf("after redoing delete: %d articles", len(list))
+	}
+	press(key("u"))
+	article()
+}
+
+func TestArchiveNote(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContent("Essay", "---\ntitle: Essay\n---\n\nBody.\n", nil); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		store:          store,
+		keys:           DefaultKeyMap(),
+		styles:         DefaultStyles(),
+		searchInput:    NewSearchInput(DefaultStyles()),
+		noteInput:      NewArchiveNoteInput(DefaultStyles()),
+		appState:       testState(t),
+		askArchiveNote: true,
+		width:          80,
+		height:         30,
+	}
+	m.refreshArticles()
+	press := func(k tea.KeyMsg) {
+		t.Helper()
+		next, cmd := m.Update(k)
+		m = next.(Model)
+		if cmd != nil {
+			if msg := cmd(); msg != nil {
+				next, _ = m.Update(msg)
+				m = next.(Model)
+			}
+		}
+	}
+	key := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	article := func() storage.ArticleMeta {
+		t.Helper()
+		list := store.List()
+		if len(list) != 1 {
+			t.Fatalf("%d articles, want 1", len(list))
+		}
+		return list[0]
+	}
+
+	// x asks why, and esc backs out without archiving.
+	press(key("x"))
+	if m.state != stateArchiveNote {
+		t.Fatalf("after x: state %v, want the archive note prompt", m.state)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if a := article(); m.state != stateList || a.IsArchived() {
+		t.Fatalf("after esc: state %v, archived %v", m.state, a.IsArchived())
+	}
+
+	// The note is kept with the archived article.
+	press(key("x"))
+	press(key("outdated"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if a := article(); m.state != stateList || !a.IsArchived() || a.ArchiveNote != "outdated" {
+		t.Fatalf("after x, enter: state %v, archived %v, note %q", m.state, a.IsArchived(), a.ArchiveNote)
+	}
+
+	// Undo takes back both.
+	press(key("u"))
+	if a := article(); a.IsArchived() || a.ArchiveNote != "" {
+		t.Fatalf("after u: archived %v, note %q", a.IsArchived(), a.ArchiveNote)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, article().FilePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "archive_note") {
+		t.Errorf("archive_note left in the front matter after undo:\n%s", content)
+	}
+
+	// Without archive_note, x archives straight away.
+	m.askArchiveNote = false
+	press(key("x"))
+	if a := article(); m.state != stateList || !a.IsArchived() || a.ArchiveNote != "" {
+		t.Errorf("x without asking: state %v, archived %v, note %q", m.state, a.IsArchived(), a.ArchiveNote)
+	}
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// validateAndNormalizeURL checks that raw is a web page URL worth sending to
+// the extractor, and returns it with https:// added if it had no scheme.
+// Hosts may be domain names, including internationalized ones in Unicode or
+// punycode (xn--), IPv4 or bracketed IPv6 addresses, or localhost. The rest
+// of the URL is returned as typed, so it still matches a saved source URL.
+func validateAndNormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("URL cannot be empty")
+	}
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid URL %q: %s", raw, reason)
+	}
+
+	normalized := raw
+	if scheme, rest, ok := strings.Cut(raw, "://"); ok {
+		switch strings.ToLower(scheme) {
+		case "http", "https":
+			normalized = strings.ToLower(scheme) + "://" + rest
+		default:
+			return "", invalid("only http and https URLs can be saved")
+		}
+	} else {
+		normalized = "https://" + raw
+	}
+
+	u, err := neturl.Parse(normalized)
+	if err != nil {
+		return "", invalid(strings.TrimPrefix(err.Error(), fmt.Sprintf("parse %q: ", normalized)))
+	}
+	if u.User != nil {
+		return "", invalid("user names and passwords aren't supported")
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", invalid("port out of range")
+		}
+	}
+	host := u.Hostname()
+	switch {
+	case host == "":
+		return "", invalid("no host")
+	case strings.HasPrefix(u.Host, "["), net.ParseIP(host) != nil, strings.EqualFold(host, "localhost"):
+		// url.Parse has checked a bracketed IPv6 literal.
+		return normalized, nil
+	}
+	if reason := checkHostname(host); reason != "" {
+		return "", invalid(reason)
+	}
+	return normalized, nil
+}
+
+// checkHostname returns why host isn't a plausible domain name, or "" if
+// it is: dot-separated labels of letters (in any script), digits, hyphens
+// and underscores, not starting or ending with a hyphen, under a top-level
+// domain that isn't all digits.
+func checkHostname(host string) string {
+	host = strings.TrimSuffix(host, ".") // fully qualified
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return "host needs a domain, e.g. example.com"
+	}
+	for _, label := range labels {
+		if label == "" {
+			return "empty label in host"
+		}
+		if len(label) > 63 {
+			return "host label longer than 63 characters"
+		}
+		if strings.EqualFold(label, "xn--") {
+			return "empty punycode label in host"
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Sprintf("host label %q starts or ends with a hyphen", label)
+		}
+		for _, r := range label {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && !unicode.Is(unicode.Mn, r) {
+				return fmt.Sprintf("host has %q in it", r)
+			}
+		}
+	}
+	if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+		return "not a domain name or IP address"
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAndNormalizeURL(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want string // "" if invalid
+		err  string // substring of the error, if invalid
+	}{
+		{raw: "https://example.com/post", want: "https://example.com/post"},
+		{raw: "  example.com/post?id=1#top ", want: "https://example.com/post?id=1#top"},
+		{raw: "HTTP://Example.com/Post", want: "http://Example.com/Post"},
+		{raw: "blog.example.co.uk:8080/a", want: "https://blog.example.co.uk:8080/a"},
+		{raw: "example.com.", want: "https://example.com."},
+		{raw: "my_site.example.com", want: "https://my_site.example.com"},
+
+		// Hosts without a dot.
+		{raw: "localhost:3000/notes", want: "https://localhost:3000/notes"},
+		{raw: "http://LOCALHOST/", want: "http://LOCALHOST/"},
+		{raw: "http://192.168.1.10:8000/a", want: "http://192.168.1.10:8000/a"},
+		{raw: "[::1]:8080/a", want: "https://[::1]:8080/a"},
+		{raw: "http://[2001:db8::1]/", want: "http://[2001:db8::1]/"},
+
+		// Internationalized domain names, either way round.
+		{raw: "https://bücher.example/", want: "https://bücher.example/"},
+		{raw: "xn--bcher-kva.example/buch", want: "https://xn--bcher-kva.example/buch"},
+		{raw: "https://例え.jp/", want: "https://例え.jp/"},
+
+		{raw: "", err: "empty"},
+		{raw: "   ", err: "empty"},
+		{raw: "notaurl", err: "needs a domain"},
+		{raw: "ftp://example.com/file", err: "only http and https"},
+		{raw: "javascript://alert(1)", err: "only http and https"},
+		{raw: "https://", err: "no host"},
+		{raw: "https:///path", err: "no host"},
+		{raw: "http://exa mple.com", err: "invalid"},
+		{raw: "example..com", err: "empty label"},
+		{raw: "-example.com", err: "hyphen"},
+		{raw: "example.com:99999", err: "port out of range"},
+		{raw: "1.2.3.999", err: "not a domain name or IP"},
+		{raw: "[1.2.3.4]/", err: "IP-literal"},
+		{raw: "[::1/", err: "invalid"},
+		{raw: "user:pass@example.com", err: "passwords"},
+		{raw: "xn--.example", err: "punycode"},
+		{raw: "exa$mple.com", err: "'$'"},
+	} {
+		got, err := validateAndNormalizeURL(tc.raw)
+		switch {
+		case tc.want != "" && err != nil:
+			t.Errorf("validateAndNormalizeURL(%q): %v, want %q", tc.raw, err, tc.want)
+		case tc.want != "" && got != tc.want:
+			t.Errorf("validateAndNormalizeURL(%q) = %q, want %q", tc.raw, got, tc.want)
+		case tc.want == "" && err == nil:
+			t.Errorf("validateAndNormalizeURL(%q) = %q, want an error", tc.raw, got)
+		case tc.want == "" && !strings.Contains(err.Error(), tc.err):
+			t.Errorf("validateAndNormalizeURL(%q): %v, want it to mention %q", tc.raw, err, tc.err)
+		}
+	}
+}